@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/emergent-company/specmcp/internal/markdown"
+	"github.com/emergent-company/specmcp/internal/tools/workflow"
+)
+
+const exportUsage = `Usage: specmcp export [flags] <change>
+
+Render a change (by name or ID) as markdown:
+
+  default   a change directory (proposal.md, design.md, tasks.md,
+            specs/<capability>/spec.md) that specmcp import reads back
+  --pr      a single pull request description
+
+Flags:
+  --config path   path to specmcp.toml config file
+  --out path      directory to write (default: ./<change>), or with --pr a file
+                  (default: stdout)
+  --pr            render a pull request description instead of a directory
+`
+
+// runExport handles the "specmcp export" subcommand.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, exportUsage) }
+	configPath := fs.String("config", "", "path to specmcp.toml config file")
+	out := fs.String("out", "", "output directory, or file with --pr")
+	pr := fs.Bool("pr", false, "render a pull request description")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("export takes exactly one change")
+	}
+	change := fs.Arg(0)
+
+	format := "tree"
+	if *pr {
+		format = "pr"
+	} else if *out == "" {
+		*out = change
+	}
+
+	factory, ctx, err := newCLIFactory(*configPath)
+	if err != nil {
+		return err
+	}
+	params, err := json.Marshal(map[string]any{
+		"change_id": change,
+		"format":    format,
+	})
+	if err != nil {
+		return err
+	}
+	result, err := workflow.NewSpecExportMarkdown(factory).Execute(ctx, params)
+	if err != nil || result.IsError {
+		return printToolResult(result, err)
+	}
+
+	var exported struct {
+		Files    map[string]string `json:"files"`
+		Markdown string            `json:"markdown"`
+		Message  string            `json:"message"`
+	}
+	if len(result.Content) == 0 {
+		return errors.New("export returned no content")
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &exported); err != nil {
+		return fmt.Errorf("reading export result: %w", err)
+	}
+
+	switch {
+	case *pr && *out == "":
+		// A PR description without --out goes to stdout as plain markdown.
+		fmt.Print(exported.Markdown)
+		return nil
+	case *pr:
+		if err := os.WriteFile(*out, []byte(exported.Markdown), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("%s\nwrote %s\n", exported.Message, *out)
+		return nil
+	}
+	written, err := markdown.WriteFiles(*out, exported.Files)
+	if err != nil {
+		return err
+	}
+	fmt.Println(exported.Message)
+	for _, p := range written {
+		fmt.Printf("wrote %s\n", p)
+	}
+	return nil
+}
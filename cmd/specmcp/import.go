@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/emergent-company/specmcp/internal/config"
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/markdown"
+	"github.com/emergent-company/specmcp/internal/mcp"
+	"github.com/emergent-company/specmcp/internal/tools/workflow"
+)
+
+const importUsage = `Usage: specmcp import [flags] <dir>
+
+Import a markdown change directory into the Emergent project:
+
+  <dir>/proposal.md                  Proposal (## Why, ## What Changes, ## Impact)
+  <dir>/design.md                    Design
+  <dir>/tasks.md                     Tasks (## N. Section, - [ ] N.M description)
+  <dir>/specs/<capability>/spec.md   Spec, ### Requirement: and #### Scenario: blocks
+
+Re-importing the same directory updates the change in place.
+
+Flags:
+  --config path   path to specmcp.toml config file
+  --name name     change name (default: the directory name)
+  --dry-run       show what would be created or updated without changing anything
+`
+
+// runImport handles the "specmcp import" subcommand.
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, importUsage) }
+	configPath := fs.String("config", "", "path to specmcp.toml config file")
+	name := fs.String("name", "", "change name")
+	dryRun := fs.Bool("dry-run", false, "show what would change without applying it")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("import takes exactly one directory")
+	}
+
+	factory, ctx, err := newCLIFactory(*configPath)
+	if err != nil {
+		return err
+	}
+	dir := fs.Arg(0)
+	files, err := markdown.ReadFiles(os.DirFS(dir))
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", dir, err)
+	}
+	if *name == "" {
+		*name = filepath.Base(filepath.Clean(dir))
+	}
+	params, err := json.Marshal(map[string]any{
+		"files":   files,
+		"name":    *name,
+		"dry_run": *dryRun,
+	})
+	if err != nil {
+		return err
+	}
+	return printToolResult(workflow.NewSpecImportMarkdown(factory).Execute(ctx, params))
+}
+
+// newCLIFactory builds a client factory for one-shot subcommands from the
+// config file, using the configured token and project.
+func newCLIFactory(configPath string) (*emergent.ClientFactory, context.Context, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading config: %w", err)
+	}
+	if cfg.Emergent.Token == "" {
+		return nil, nil, fmt.Errorf("an Emergent token is required (set EMERGENT_TOKEN or emergent.token in specmcp.toml)")
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	factory := emergent.NewClientFactory(
+		cfg.Emergent.URL,
+		cfg.Emergent.Token,
+		cfg.Emergent.MaxRetries,
+		cfg.Emergent.LongOutageIntervalMins,
+		cfg.Emergent.LongOutageThreshold,
+		logger,
+	)
+	factory.SetListCap(cfg.Emergent.MaxListItems)
+	return factory, emergent.WithProjectID(context.Background(), cfg.Emergent.ProjectID), nil
+}
+
+// printToolResult prints a tool result's text to stdout, or returns it as an
+// error if the tool reported one.
+func printToolResult(result *mcp.ToolsCallResult, err error) error {
+	if err != nil {
+		return err
+	}
+	var text string
+	for _, c := range result.Content {
+		text += c.Text
+	}
+	if result.IsError {
+		return errors.New(text)
+	}
+	fmt.Println(text)
+	return nil
+}
@@ -55,15 +55,25 @@ TRANSPORT MODES
     Health check:  GET /health
     Default port:  21452
 
-TOOLS (31)
-
-  Workflow (7):     spec_new, spec_artifact, spec_batch_artifact,
-                    spec_archive, spec_verify, spec_mark_ready, spec_status
-  Query (11):       list_changes, get_change, get_context, get_component,
+TOOLS (50)
+
+  Workflow (17):    spec_new, spec_artifact, spec_batch_artifact,
+                    spec_archive, spec_unarchive, spec_verify,
+                    spec_mark_ready, spec_mark_draft, spec_transition,
+                    spec_update_artifact, spec_delete_artifact,
+                    spec_set_change_dependencies, spec_import_markdown,
+                    spec_export_markdown, spec_export_gherkin,
+                    spec_import_gherkin, spec_status
+  Query (14):       list_changes, get_change, get_context, get_component,
                     get_action, get_data_model, get_service, get_scenario,
-                    get_patterns, impact_analysis, search
+                    get_patterns, impact_analysis, search, get_living_spec,
+                    history, diff
   Tasks (5):        generate_tasks, get_available_tasks, assign_task,
                     complete_task, get_critical_path
+  Review (3):       spec_request_review, spec_approve_review,
+                    spec_reject_review
+  Comments (3):     spec_add_comment, spec_list_comments,
+                    spec_resolve_comment
   Patterns (3):     suggest_patterns, apply_pattern, seed_patterns
   Constitution (2): create_constitution, validate_constitution
   Sync (3):         sync_status, sync, graph_summary
@@ -40,13 +40,16 @@ import (
 	"github.com/emergent-company/specmcp/internal/config"
 	"github.com/emergent-company/specmcp/internal/content"
 	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/guards"
 	"github.com/emergent-company/specmcp/internal/mcp"
 	"github.com/emergent-company/specmcp/internal/scheduler"
+	"github.com/emergent-company/specmcp/internal/tools/comments"
 	"github.com/emergent-company/specmcp/internal/tools/constitution"
 	"github.com/emergent-company/specmcp/internal/tools/improvement"
 	"github.com/emergent-company/specmcp/internal/tools/janitor"
 	"github.com/emergent-company/specmcp/internal/tools/patterns"
 	"github.com/emergent-company/specmcp/internal/tools/query"
+	"github.com/emergent-company/specmcp/internal/tools/review"
 	gosync "github.com/emergent-company/specmcp/internal/tools/sync"
 	"github.com/emergent-company/specmcp/internal/tools/tasks"
 	"github.com/emergent-company/specmcp/internal/tools/workflow"
@@ -78,6 +81,12 @@ func run() error {
 		case "rollback":
 			handleRollbackCommand()
 			return nil
+		case "pack":
+			return runPack(os.Args[2:])
+		case "import":
+			return runImport(os.Args[2:])
+		case "export":
+			return runExport(os.Args[2:])
 		}
 	}
 
@@ -135,15 +144,62 @@ func run() error {
 		cfg.Emergent.LongOutageThreshold,
 		logger,
 	)
+	emFactory.SetListCap(cfg.Emergent.MaxListItems)
+	workflowDef := guards.DefaultWorkflow()
+	if len(cfg.Workflow.Stages) > 0 {
+		if workflowDef, err = guards.ParseWorkflow(cfg.Workflow.Stages); err != nil {
+			return fmt.Errorf("workflow stages: %w", err)
+		}
+		emFactory.SetWorkflowStages(cfg.Workflow.Stages)
+	}
+	guardPolicy, err := guards.ParseGuardPolicy(cfg.Guards.Overrides)
+	if err != nil {
+		return fmt.Errorf("guard overrides: %w", err)
+	}
+	emFactory.SetGuardPolicy(cfg.Guards.Overrides)
+	ruleDefs := make([]emergent.GuardRule, len(cfg.Guards.Rules))
+	for i, r := range cfg.Guards.Rules {
+		ruleDefs[i] = emergent.GuardRule{Name: r.Name, On: r.On, Expr: r.Expr, Severity: r.Severity, Message: r.Message, Remedy: r.Remedy}
+	}
+	guardRules, err := guards.CompileRules(ruleDefs)
+	if err != nil {
+		return fmt.Errorf("guard rules: %w", err)
+	}
+	if err := guardPolicy.CheckGuardNames(workflowDef, guardRules); err != nil {
+		return fmt.Errorf("guard overrides: %w", err)
+	}
+	emFactory.SetGuardRules(ruleDefs)
 
 	// Register workflow tools
 	specArtifact := workflow.NewSpecArtifact(emFactory)
 	registry.Register(workflow.NewSpecNew(emFactory))
 	registry.Register(specArtifact)
 	registry.Register(workflow.NewSpecBatchArtifact(specArtifact))
-	registry.Register(workflow.NewSpecArchive(emFactory))
-	registry.Register(workflow.NewSpecVerify(emFactory))
-	registry.Register(workflow.NewSpecMarkReady(emFactory))
+	specArchive := workflow.NewSpecArchive(emFactory)
+	specUnarchive := workflow.NewSpecUnarchive(emFactory)
+	registry.Register(specArchive)
+	registry.Register(specUnarchive)
+	registry.Register(workflow.NewSpecVerify(emFactory, cfg.Lint))
+	markReady := workflow.NewSpecMarkReady(emFactory, cfg.Lint)
+	reviewPolicy, err := guards.ParseReviewPolicy(cfg.Review.Policy)
+	if err != nil {
+		return fmt.Errorf("review policy: %w", err)
+	}
+	reviewSeverity, err := guards.ParseSeverity(cfg.Review.Severity)
+	if err != nil {
+		return fmt.Errorf("review severity: %w", err)
+	}
+	markReady.SetReviewPolicy(reviewPolicy, reviewSeverity)
+	registry.Register(markReady)
+	registry.Register(workflow.NewSpecUpdateArtifact(emFactory))
+	markDraft := workflow.NewSpecMarkDraft(emFactory)
+	registry.Register(markDraft)
+	registry.Register(workflow.NewSpecDeleteArtifact(emFactory))
+	registry.Register(workflow.NewSpecSetChangeDependencies(emFactory))
+	registry.Register(workflow.NewSpecImportMarkdown(emFactory))
+	registry.Register(workflow.NewSpecExportMarkdown(emFactory))
+	registry.Register(workflow.NewSpecExportGherkin(emFactory))
+	registry.Register(workflow.NewSpecImportGherkin(emFactory))
 	registry.Register(workflow.NewSpecStatus(emFactory))
 
 	// Register query tools
@@ -158,14 +214,32 @@ func run() error {
 	registry.Register(query.NewGetPatterns(emFactory))
 	registry.Register(query.NewImpactAnalysis(emFactory))
 	registry.Register(query.NewSearch(emFactory))
+	registry.Register(query.NewGetLivingSpec(emFactory))
+	registry.Register(query.NewHistory(emFactory))
+	registry.Register(query.NewDiff(emFactory))
 
 	// Register task management tools
 	registry.Register(tasks.NewGenerateTasks(emFactory))
 	registry.Register(tasks.NewGetAvailableTasks(emFactory))
 	registry.Register(tasks.NewAssignTask(emFactory))
-	registry.Register(tasks.NewCompleteTask(emFactory))
+	completeTask := tasks.NewCompleteTask(emFactory)
+	registry.Register(completeTask)
 	registry.Register(tasks.NewGetCriticalPath(emFactory))
 
+	// Register the generic transition tool; transitions with side effects
+	// are passed on to the tools that own them.
+	registry.Register(workflow.NewSpecTransition(emFactory, markReady, markDraft, specArchive, specUnarchive, completeTask))
+
+	// Register review tools
+	registry.Register(review.NewRequestReview(emFactory))
+	registry.Register(review.NewApproveReview(emFactory))
+	registry.Register(review.NewRejectReview(emFactory))
+
+	// Register comment tools
+	registry.Register(comments.NewAddComment(emFactory))
+	registry.Register(comments.NewListComments(emFactory))
+	registry.Register(comments.NewResolveComment(emFactory))
+
 	// Register improvement tools
 	registry.Register(improvement.NewCreateTool(emFactory))
 
@@ -195,7 +269,7 @@ func run() error {
 	registry.RegisterResource(&content.GuideResource{})
 	registry.RegisterResource(&content.WorkflowResource{})
 	registry.RegisterResource(&content.EntityModelResource{})
-	registry.RegisterResource(&content.GuardrailsResource{})
+	registry.RegisterResource(&content.GuardrailsResource{Guards: guards.BuiltinGuards(workflowDef), Policy: guardPolicy, Rules: guardRules})
 	registry.RegisterResource(&content.ToolReferenceResource{})
 
 	// Create core MCP server (transport-agnostic)
@@ -203,6 +277,9 @@ func run() error {
 		Name:    cfg.Server.Name,
 		Version: version,
 	}, logger)
+	if cfg.Idempotency.WindowMinutes > 0 {
+		server.SetIdempotencyStore(mcp.NewIdempotencyStore(time.Duration(cfg.Idempotency.WindowMinutes) * time.Minute))
+	}
 
 	// Start scheduler if enabled (HTTP mode is ideal for background jobs as a long-running daemon)
 	var sched *scheduler.Scheduler
@@ -229,6 +306,12 @@ func run() error {
 		// Stdio mode: inject the configured token into the context so
 		// ClientFactory.ClientFor can create per-request clients.
 		ctx = emergent.WithToken(ctx, cfg.Emergent.Token)
+		ctx = emergent.WithProjectID(ctx, cfg.Emergent.ProjectID)
+		// Check the project schema against the type constants in the
+		// background so an outdated template pack is reported at startup
+		// without delaying the MCP handshake. In HTTP mode the check runs
+		// lazily per project on the first write.
+		go emFactory.CheckSchema(ctx)
 		return server.Run(ctx)
 	}
 }
@@ -240,6 +323,9 @@ func runHTTP(ctx context.Context, server *mcp.Server, cfg *config.Config, logger
 		cfg.Transport.CORSOrigins,
 		logger,
 	)
+	// Per-request project selection (X-Project-ID header or ?project=),
+	// validated against the configured allowlist.
+	httpServer.SetProjects(cfg.Emergent.ProjectID, cfg.Emergent.AllowedProjects)
 
 	addr := net.JoinHostPort(cfg.Transport.Host, cfg.Transport.Port)
 
@@ -0,0 +1,422 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	sdk "github.com/emergent-company/emergent/apps/server-go/pkg/sdk"
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/templatepacks"
+	"github.com/emergent-company/specmcp/internal/config"
+	"github.com/emergent-company/specmcp/internal/packs"
+	"github.com/emergent-company/specmcp/templates"
+)
+
+const packUsage = `Usage: specmcp pack <command> [flags]
+
+Manage the SpecMCP template pack installed in the Emergent project.
+
+Commands:
+  status    Show the embedded and installed pack versions
+  diff      Show type and relationship differences between the pack and the project
+  install   Register the pack and assign it to the project
+  upgrade   Install a newer pack version and unassign the old one
+
+Flags:
+  --config path   path to specmcp.toml config file
+  --pack name     embedded pack file or a path to a pack JSON file (default: ` + templates.DefaultPack + `)
+  --dry-run       show what install or upgrade would do without changing anything
+  --force         upgrade even if types, properties, or relationships would be removed
+  --keep-old      keep the previous pack version assigned after an upgrade
+  --json          print machine-readable output (status and diff)
+
+Embedded packs: %s
+`
+
+// runPack handles the "specmcp pack" subcommand.
+func runPack(args []string) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprintf(os.Stdout, packUsage, strings.Join(embeddedPacks(), ", "))
+		return nil
+	}
+	cmd := args[0]
+
+	flags := flag.NewFlagSet("pack "+cmd, flag.ExitOnError)
+	configPath := flags.String("config", "", "path to specmcp.toml config file")
+	packName := flags.String("pack", templates.DefaultPack, "embedded pack file or path to a pack JSON file")
+	dryRun := flags.Bool("dry-run", false, "show what would change without applying it")
+	force := flags.Bool("force", false, "upgrade even if types, properties, or relationships would be removed")
+	keepOld := flags.Bool("keep-old", false, "keep the previous pack version assigned after an upgrade")
+	asJSON := flags.Bool("json", false, "print machine-readable output")
+	flags.Parse(args[1:])
+
+	switch cmd {
+	case "status", "diff", "install", "upgrade":
+	default:
+		return fmt.Errorf("unknown pack command %q (want status, diff, install, or upgrade)", cmd)
+	}
+
+	pack, err := loadPack(*packName)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	pc, err := newPackClient(*configPath)
+	if err != nil {
+		return err
+	}
+	state, err := pc.inspect(ctx, pack)
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case "status":
+		return printPackStatus(os.Stdout, pack, state, *asJSON)
+	case "diff":
+		if *asJSON {
+			return writeJSON(os.Stdout, state.diff)
+		}
+		printPackDiff(os.Stdout, pack, state.diff)
+		return nil
+	case "install":
+		return pc.install(ctx, pack, state, *dryRun)
+	default:
+		return pc.upgrade(ctx, pack, state, *dryRun, *force, *keepOld)
+	}
+}
+
+// embeddedPacks lists the pack files compiled into the binary.
+func embeddedPacks() []string {
+	names, _ := fs.Glob(templates.FS, "*.json")
+	return names
+}
+
+// loadPack reads a pack from a file path, falling back to the embedded packs.
+func loadPack(name string) (*packs.Pack, error) {
+	data, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		data, err = templates.FS.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("pack %q is neither a file nor an embedded pack (embedded: %s)", name, strings.Join(embeddedPacks(), ", "))
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("reading pack: %w", err)
+	}
+	pack, err := packs.Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return pack, nil
+}
+
+// packClient talks to the template pack API of the configured project.
+type packClient struct {
+	sdk *sdk.Client
+}
+
+// newPackClient creates an SDK client from the specmcp configuration.
+func newPackClient(configPath string) (*packClient, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	if cfg.Emergent.Token == "" {
+		return nil, fmt.Errorf("an Emergent token is required (set EMERGENT_TOKEN or emergent.token in specmcp.toml)")
+	}
+	client, err := sdk.New(sdk.Config{
+		ServerURL: cfg.Emergent.URL,
+		ProjectID: cfg.Emergent.ProjectID,
+		Auth: sdk.AuthConfig{
+			Mode:   "apikey",
+			APIKey: cfg.Emergent.Token,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating SDK client: %w", err)
+	}
+	return &packClient{sdk: client}, nil
+}
+
+// packState is what the project currently has for a pack.
+type packState struct {
+	installed []templatepacks.InstalledPackItem // assignments of packs with the same name
+	current   *templatepacks.InstalledPackItem  // the active assignment, if any
+	diff      *packs.Diff
+}
+
+// inspect loads the project's installed packs and compiled types and diffs
+// them against pack.
+func (c *packClient) inspect(ctx context.Context, pack *packs.Pack) (*packState, error) {
+	installed, err := c.sdk.TemplatePacks.GetInstalledPacks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing installed packs: %w", err)
+	}
+	state := &packState{}
+	for i := range installed {
+		if installed[i].Name != pack.Name {
+			continue
+		}
+		state.installed = append(state.installed, installed[i])
+		if installed[i].Active && state.current == nil {
+			state.current = &state.installed[len(state.installed)-1]
+		}
+	}
+
+	compiled, err := c.sdk.TemplatePacks.GetCompiledTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting compiled types: %w", err)
+	}
+	state.diff = packs.Compare(pack, packs.FromCompiled(compiled, pack.Name))
+	return state, nil
+}
+
+// install registers the pack and assigns it to a project that does not have
+// it yet.
+func (c *packClient) install(ctx context.Context, pack *packs.Pack, state *packState, dryRun bool) error {
+	if state.current != nil {
+		return fmt.Errorf("pack %q v%s is already installed; use \"specmcp pack upgrade\" to change versions", pack.Name, state.current.Version)
+	}
+	printPackDiff(os.Stdout, pack, state.diff)
+	if len(state.diff.Conflicts) > 0 {
+		return fmt.Errorf("%d type(s) are already defined by other packs; resolve the conflicts before installing", len(state.diff.Conflicts))
+	}
+	if dryRun {
+		fmt.Printf("\nDry run: would register %s v%s and assign it to the project.\n", pack.Name, pack.Version)
+		return nil
+	}
+
+	assignmentID, err := c.registerAndAssign(ctx, pack)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nInstalled %s v%s (assignment %s).\n", pack.Name, pack.Version, assignmentID)
+	return nil
+}
+
+// upgrade assigns a new pack version and unassigns the old one. Removals are
+// refused unless force is set, and the old version stays assigned while
+// objects of removed types still exist so their data remains readable.
+func (c *packClient) upgrade(ctx context.Context, pack *packs.Pack, state *packState, dryRun, force, keepOld bool) error {
+	if state.current == nil {
+		return fmt.Errorf("pack %q is not installed; use \"specmcp pack install\"", pack.Name)
+	}
+	if state.current.Version == pack.Version {
+		if state.diff.Empty() {
+			fmt.Printf("%s v%s is already installed and up to date.\n", pack.Name, pack.Version)
+			return nil
+		}
+		printPackDiff(os.Stdout, pack, state.diff)
+		return fmt.Errorf("installed version equals the pack version (v%s) but the schemas differ; bump the pack version to upgrade", pack.Version)
+	}
+
+	fmt.Printf("Upgrading %s v%s → v%s\n\n", pack.Name, state.current.Version, pack.Version)
+	printPackDiff(os.Stdout, pack, state.diff)
+
+	if len(state.diff.Conflicts) > 0 {
+		return fmt.Errorf("%d type(s) are already defined by other packs; resolve the conflicts before upgrading", len(state.diff.Conflicts))
+	}
+	if state.diff.Removals() && !force {
+		return fmt.Errorf("the upgrade removes types, properties, or relationship endpoints; review the diff and rerun with --force")
+	}
+
+	// Objects of removed types would become unreadable if the old pack were
+	// unassigned, so keep both versions side by side until they are migrated.
+	inUse, err := c.typesInUse(ctx, state.diff.RemovedTypes)
+	if err != nil {
+		return err
+	}
+	unassignOld := !keepOld && len(inUse) == 0
+
+	fmt.Println()
+	if dryRun {
+		fmt.Println("Dry run: would")
+		fmt.Printf("  1. register %s v%s and assign it to the project\n", pack.Name, pack.Version)
+		if unassignOld {
+			fmt.Printf("  2. unassign %s v%s\n", pack.Name, state.current.Version)
+		} else {
+			fmt.Printf("  2. keep %s v%s assigned%s\n", pack.Name, state.current.Version, keepReason(keepOld, inUse))
+		}
+		return nil
+	}
+
+	assignmentID, err := c.registerAndAssign(ctx, pack)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Assigned %s v%s (assignment %s).\n", pack.Name, pack.Version, assignmentID)
+
+	if !unassignOld {
+		fmt.Printf("Kept %s v%s assigned%s.\n", pack.Name, state.current.Version, keepReason(keepOld, inUse))
+		return nil
+	}
+	if err := c.sdk.TemplatePacks.DeleteAssignment(ctx, state.current.ID); err != nil {
+		return fmt.Errorf("new version is assigned, but unassigning v%s failed: %w", state.current.Version, err)
+	}
+	fmt.Printf("Unassigned %s v%s.\n", pack.Name, state.current.Version)
+	return nil
+}
+
+// keepReason explains why the old pack version stays assigned.
+func keepReason(keepOld bool, inUse map[string]int) string {
+	if keepOld {
+		return " (--keep-old)"
+	}
+	parts := make([]string, 0, len(inUse))
+	for name, n := range inUse {
+		parts = append(parts, fmt.Sprintf("%s: %d", name, n))
+	}
+	return fmt.Sprintf(" because removed types still have objects (%s); migrate them, then unassign it", strings.Join(parts, ", "))
+}
+
+// typesInUse counts the objects of each type and returns the non-zero counts.
+func (c *packClient) typesInUse(ctx context.Context, typeNames []string) (map[string]int, error) {
+	inUse := make(map[string]int)
+	for _, name := range typeNames {
+		n, err := c.sdk.Graph.CountObjects(ctx, &graph.CountObjectsOptions{Type: name})
+		if err != nil {
+			return nil, fmt.Errorf("counting %s objects: %w", name, err)
+		}
+		if n > 0 {
+			inUse[name] = n
+		}
+	}
+	return inUse, nil
+}
+
+// registerAndAssign registers the pack (reusing an already registered pack
+// with the same name and version) and assigns it to the project.
+func (c *packClient) registerAndAssign(ctx context.Context, pack *packs.Pack) (string, error) {
+	packID := ""
+	available, err := c.sdk.TemplatePacks.GetAvailablePacks(ctx)
+	if err != nil {
+		return "", fmt.Errorf("listing available packs: %w", err)
+	}
+	for _, a := range available {
+		if a.Name == pack.Name && a.Version == pack.Version {
+			packID = a.ID
+			break
+		}
+	}
+	if packID == "" {
+		created, err := c.sdk.TemplatePacks.CreatePack(ctx, pack.CreateRequest())
+		if err != nil {
+			return "", fmt.Errorf("registering pack: %w", err)
+		}
+		packID = created.ID
+	}
+
+	assignment, err := c.sdk.TemplatePacks.AssignPack(ctx, &templatepacks.AssignPackRequest{
+		TemplatePackID: packID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("assigning pack %s: %w", packID, err)
+	}
+	return assignment.ID, nil
+}
+
+// printPackStatus prints the embedded and installed versions of a pack.
+func printPackStatus(w io.Writer, pack *packs.Pack, state *packState, asJSON bool) error {
+	status := "not installed"
+	switch {
+	case state.current == nil:
+	case state.current.Version != pack.Version:
+		status = "upgrade available"
+	case state.diff.Empty():
+		status = "up to date"
+	default:
+		status = "schemas differ"
+	}
+
+	if asJSON {
+		installed := make([]map[string]any, 0, len(state.installed))
+		for _, p := range state.installed {
+			installed = append(installed, map[string]any{
+				"assignment_id": p.ID,
+				"pack_id":       p.TemplatePackID,
+				"version":       p.Version,
+				"active":        p.Active,
+				"installed_at":  p.InstalledAt,
+			})
+		}
+		return writeJSON(w, map[string]any{
+			"pack":      pack.Name,
+			"version":   pack.Version,
+			"status":    status,
+			"installed": installed,
+			"in_sync":   state.diff.Empty(),
+		})
+	}
+
+	fmt.Fprintf(w, "Pack:      %s v%s (%d object types, %d relationship types)\n",
+		pack.Name, pack.Version, len(pack.ObjectTypes), len(pack.RelationshipTypes))
+	if len(state.installed) == 0 {
+		fmt.Fprintln(w, "Installed: none")
+	}
+	for i, p := range state.installed {
+		label := "Installed:"
+		if i > 0 {
+			label = ""
+		}
+		active := "active"
+		if !p.Active {
+			active = "inactive"
+		}
+		fmt.Fprintf(w, "%-10s %s v%s (%s, since %s)\n", label, p.Name, p.Version, active, p.InstalledAt.Format("2006-01-02"))
+	}
+	fmt.Fprintf(w, "Status:    %s\n", status)
+	if status != "up to date" && status != "not installed" {
+		fmt.Fprintln(w, "\nRun \"specmcp pack diff\" for details, \"specmcp pack upgrade --dry-run\" to preview the upgrade.")
+	}
+	return nil
+}
+
+// printPackDiff prints a human-readable diff.
+func printPackDiff(w io.Writer, pack *packs.Pack, d *packs.Diff) {
+	if d.Empty() {
+		fmt.Fprintf(w, "No differences: the project matches %s v%s.\n", pack.Name, pack.Version)
+		return
+	}
+	list := func(sign, label string, names []string) {
+		for _, n := range names {
+			fmt.Fprintf(w, "  %s %s %s\n", sign, label, n)
+		}
+	}
+
+	fmt.Fprintf(w, "Differences between %s v%s and the project:\n", pack.Name, pack.Version)
+	list("+", "type", d.AddedTypes)
+	list("-", "type", d.RemovedTypes)
+	for _, tc := range d.ChangedTypes {
+		fmt.Fprintf(w, "  ~ type %s\n", tc.Name)
+		list("    +", "property", tc.AddedProperties)
+		list("    -", "property", tc.RemovedProperties)
+		for _, pc := range tc.ChangedProperties {
+			fmt.Fprintf(w, "      ~ property %s (%s)\n", pc.Name, strings.Join(pc.Fields, ", "))
+		}
+	}
+	list("+", "relationship", d.AddedRelationships)
+	list("-", "relationship", d.RemovedRelationships)
+	for _, rc := range d.ChangedRelationships {
+		fmt.Fprintf(w, "  ~ relationship %s\n", rc.Name)
+		list("    +", "source", rc.AddedSources)
+		list("    -", "source", rc.RemovedSources)
+		list("    +", "target", rc.AddedTargets)
+		list("    -", "target", rc.RemovedTargets)
+	}
+	list("!", "conflict", d.Conflicts)
+}
+
+// writeJSON prints v as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -5,6 +5,7 @@ go 1.25.5
 require (
 	github.com/BurntSushi/toml v1.6.0
 	github.com/emergent-company/emergent/apps/server-go/pkg/sdk v0.14.3
+	github.com/expr-lang/expr v1.17.8
 )
 
 require github.com/stretchr/testify v1.11.1 // indirect
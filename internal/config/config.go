@@ -11,22 +11,29 @@ import (
 // Config holds all configuration for the SpecMCP server.
 // Precedence: environment variables > config file > defaults.
 type Config struct {
-	Emergent  EmergentConfig  `toml:"emergent"`
-	Server    ServerConfig    `toml:"server"`
-	Transport TransportConfig `toml:"transport"`
-	Log       LogConfig       `toml:"log"`
-	Janitor   JanitorConfig   `toml:"janitor"`
+	Emergent    EmergentConfig    `toml:"emergent"`
+	Server      ServerConfig      `toml:"server"`
+	Transport   TransportConfig   `toml:"transport"`
+	Log         LogConfig         `toml:"log"`
+	Janitor     JanitorConfig     `toml:"janitor"`
+	Idempotency IdempotencyConfig `toml:"idempotency"`
+	Lint        LintConfig        `toml:"lint"`
+	Review      ReviewConfig      `toml:"review"`
+	Workflow    WorkflowConfig    `toml:"workflow"`
+	Guards      GuardsConfig      `toml:"guards"`
 }
 
 // EmergentConfig holds Emergent connection details.
 type EmergentConfig struct {
-	URL                    string `toml:"url"`
-	Token                  string `toml:"token"`                     // Project-scoped token (emt_*) or standalone API key.
-	AdminToken             string `toml:"admin_token"`               // Admin token for server-side operations (janitor, health checks) in HTTP mode.
-	ProjectID              string `toml:"project_id"`                // Optional: explicit project ID (X-Project-ID header).
-	MaxRetries             int    `toml:"max_retries"`               // Maximum number of retry attempts for failed requests (default: 5, -1 = infinite).
-	LongOutageIntervalMins int    `toml:"long_outage_interval_mins"` // After many failures, switch to this interval in minutes (default: 5).
-	LongOutageThreshold    int    `toml:"long_outage_threshold"`     // Number of consecutive failures before switching to long outage mode (default: 20).
+	URL                    string   `toml:"url"`
+	Token                  string   `toml:"token"`                     // Project-scoped token (emt_*) or standalone API key.
+	AdminToken             string   `toml:"admin_token"`               // Admin token for server-side operations (janitor, health checks) in HTTP mode.
+	ProjectID              string   `toml:"project_id"`                // Optional: explicit project ID (X-Project-ID header).
+	AllowedProjects        []string `toml:"allowed_projects"`          // Project IDs HTTP clients may select per request via X-Project-ID or ?project= ("*" allows any).
+	MaxRetries             int      `toml:"max_retries"`               // Maximum number of retry attempts for failed requests (default: 5, -1 = infinite).
+	LongOutageIntervalMins int      `toml:"long_outage_interval_mins"` // After many failures, switch to this interval in minutes (default: 5).
+	LongOutageThreshold    int      `toml:"long_outage_threshold"`     // Number of consecutive failures before switching to long outage mode (default: 20).
+	MaxListItems           int      `toml:"max_list_items"`            // Hard cap on items collected when a listing follows pagination (default: 5000).
 }
 
 // ServerConfig holds MCP server metadata.
@@ -65,6 +72,52 @@ type JanitorConfig struct {
 	ImprovementThresholds []string `toml:"improvement_thresholds"` // Severity levels that trigger improvements: ["critical", "warning"]
 }
 
+// IdempotencyConfig holds settings for replaying tool calls that carry an idempotency key.
+type IdempotencyConfig struct {
+	WindowMinutes int `toml:"window_minutes"` // How long to remember results by key (0 disables replay; graph-level dedup still applies)
+}
+
+// LintConfig holds settings for the requirement quality linter.
+type LintConfig struct {
+	Enabled    bool     `toml:"enabled"`     // Lint Requirements in spec_mark_ready and spec_verify
+	Severity   string   `toml:"severity"`    // Guard severity in spec_mark_ready: suggestion, warning, soft_block, hard_block
+	EARS       bool     `toml:"ears"`        // Require descriptions to follow an EARS template
+	VagueTerms []string `toml:"vague_terms"` // Extra terms to flag as vague, on top of the built-in list
+}
+
+// ReviewConfig holds the review policy spec_mark_ready enforces. A
+// Constitution's review_policy overrides it per artifact type.
+type ReviewConfig struct {
+	Policy   []string `toml:"policy"`   // Rules like "Proposal: 1 human" or "Design: 2"
+	Severity string   `toml:"severity"` // Guard severity when a rule is not met: suggestion, warning, soft_block, hard_block
+}
+
+// WorkflowConfig defines the stages of a change's workflow. Empty uses the
+// default Proposal → Spec → Design → Tasks chain; a Constitution's
+// workflow_stages replaces it.
+type WorkflowConfig struct {
+	Stages []string `toml:"stages"` // Stages like "design?: design after specify"
+}
+
+// GuardsConfig overrides the compiled-in severity of guards and adds custom
+// rules. A Constitution's guard_policy overrides it per guard and app, and
+// its guard_rules replace rules of the same name.
+type GuardsConfig struct {
+	Overrides []string          `toml:"overrides"` // Overrides like "patterns_seeded: warning" or "context_discovery@billing-api: off"
+	Rules     []GuardRuleConfig `toml:"rules"`
+}
+
+// GuardRuleConfig is a custom guard: an expr-lang expression over the guard
+// facts that must be true on the operations listed in On.
+type GuardRuleConfig struct {
+	Name     string   `toml:"name"`
+	On       []string `toml:"on"`       // new_change, artifact, mark_ready, archive
+	Expr     string   `toml:"expr"`     // e.g. "all(designs, {len(.file_changes ?? []) > 0})"
+	Severity string   `toml:"severity"` // suggestion, warning (default), soft_block, hard_block
+	Message  string   `toml:"message"`
+	Remedy   string   `toml:"remedy"`
+}
+
 // Load creates a Config by reading from a TOML config file and environment
 // variables. Precedence: environment variables > config file > defaults.
 //
@@ -84,6 +137,7 @@ func Load(configPath string) (*Config, error) {
 			MaxRetries:             5,  // Default to 5 retries (more aggressive)
 			LongOutageIntervalMins: 5,  // After many failures, wait 5 minutes between retries
 			LongOutageThreshold:    20, // Switch to long outage mode after 20 consecutive failures
+			MaxListItems:           5000,
 		},
 		Server: ServerConfig{
 			Name:    "specmcp",
@@ -107,6 +161,16 @@ func Load(configPath string) (*Config, error) {
 			CreateImprovements:    false,                           // Don't auto-create improvements by default
 			ImprovementThresholds: []string{"critical", "warning"}, // When enabled, create improvements for critical and warning issues
 		},
+		Idempotency: IdempotencyConfig{
+			WindowMinutes: 60, // Remember results for an hour
+		},
+		Lint: LintConfig{
+			Enabled:  true,      // Lint requirements by default
+			Severity: "warning", // Advise without blocking
+		},
+		Review: ReviewConfig{
+			Severity: "hard_block", // Unapproved artifacts cannot be marked ready
+		},
 	}
 
 	// Layer config file values on top of defaults
@@ -177,6 +241,10 @@ func (c *Config) applyEnv() {
 	envOverride("EMERGENT_API_KEY", &c.Emergent.Token) // legacy alias
 	envOverride("EMERGENT_ADMIN_TOKEN", &c.Emergent.AdminToken)
 	envOverride("EMERGENT_PROJECT_ID", &c.Emergent.ProjectID)
+	if v := os.Getenv("EMERGENT_ALLOWED_PROJECTS"); v != "" {
+		// Comma-separated list of project IDs, e.g. "proj-a,proj-b" or "*"
+		c.Emergent.AllowedProjects = splitAndTrim(v)
+	}
 
 	// Emergent retry configuration
 	if v := os.Getenv("EMERGENT_MAX_RETRIES"); v != "" {
@@ -185,6 +253,12 @@ func (c *Config) applyEnv() {
 			c.Emergent.MaxRetries = retries
 		}
 	}
+	if v := os.Getenv("EMERGENT_MAX_LIST_ITEMS"); v != "" {
+		var n int
+		if _, err := fmt.Sscanf(v, "%d", &n); err == nil && n > 0 {
+			c.Emergent.MaxListItems = n
+		}
+	}
 	if v := os.Getenv("EMERGENT_LONG_OUTAGE_INTERVAL_MINS"); v != "" {
 		var mins int
 		if _, err := fmt.Sscanf(v, "%d", &mins); err == nil && mins > 0 {
@@ -244,6 +318,48 @@ func (c *Config) applyEnv() {
 			c.Janitor.ImprovementThresholds = parts
 		}
 	}
+
+	// Idempotency
+	if v := os.Getenv("SPECMCP_IDEMPOTENCY_WINDOW_MINUTES"); v != "" {
+		var mins int
+		if _, err := fmt.Sscanf(v, "%d", &mins); err == nil && mins >= 0 {
+			c.Idempotency.WindowMinutes = mins
+		}
+	}
+
+	// Lint
+	if v := os.Getenv("SPECMCP_LINT_ENABLED"); v != "" {
+		c.Lint.Enabled = (v == "true" || v == "1")
+	}
+	envOverride("SPECMCP_LINT_SEVERITY", &c.Lint.Severity)
+	if v := os.Getenv("SPECMCP_LINT_EARS"); v != "" {
+		c.Lint.EARS = (v == "true" || v == "1")
+	}
+
+	// Review
+	if v := os.Getenv("SPECMCP_REVIEW_POLICY"); v != "" {
+		// Comma-separated rules, e.g. "Proposal: 1 human,Design: 1 human"
+		c.Review.Policy = splitAndTrim(v)
+	}
+	envOverride("SPECMCP_REVIEW_SEVERITY", &c.Review.Severity)
+
+	// Workflow
+	if v := os.Getenv("SPECMCP_WORKFLOW_STAGES"); v != "" {
+		// Semicolon-separated stages, since a stage lists types with commas
+		var stages []string
+		for _, st := range strings.Split(v, ";") {
+			if st = strings.TrimSpace(st); st != "" {
+				stages = append(stages, st)
+			}
+		}
+		c.Workflow.Stages = stages
+	}
+
+	// Guards
+	if v := os.Getenv("SPECMCP_GUARD_OVERRIDES"); v != "" {
+		// Comma-separated overrides, e.g. "patterns_seeded: warning,context_discovery@billing-api: off"
+		c.Guards.Overrides = splitAndTrim(v)
+	}
 }
 
 // Validate checks that required fields are present.
@@ -264,9 +380,30 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid transport mode: %q (must be \"stdio\" or \"http\")", c.Transport.Mode)
 	}
 
+	if !validSeverity(c.Lint.Severity) {
+		return fmt.Errorf("invalid lint severity: %q (must be \"suggestion\", \"warning\", \"soft_block\", or \"hard_block\")", c.Lint.Severity)
+	}
+	for i, term := range c.Lint.VagueTerms {
+		if strings.TrimSpace(term) == "" {
+			return fmt.Errorf("invalid lint vague_terms: entry %d is empty", i+1)
+		}
+	}
+	if !validSeverity(c.Review.Severity) {
+		return fmt.Errorf("invalid review severity: %q (must be \"suggestion\", \"warning\", \"soft_block\", or \"hard_block\")", c.Review.Severity)
+	}
+
 	return nil
 }
 
+// validSeverity reports whether s names a guard severity.
+func validSeverity(s string) bool {
+	switch strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "-", "_") {
+	case "suggestion", "warning", "soft_block", "hard_block":
+		return true
+	}
+	return false
+}
+
 // envOverride sets *dst to the value of the named env var, if it is non-empty.
 func envOverride(key string, dst *string) {
 	if v := os.Getenv(key); v != "" {
@@ -1,6 +1,12 @@
 package content
 
-import "github.com/emergent-company/specmcp/internal/mcp"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emergent-company/specmcp/internal/guards"
+	"github.com/emergent-company/specmcp/internal/mcp"
+)
 
 // --- specmcp://entity-model resource ---
 
@@ -31,8 +37,14 @@ func (r *EntityModelResource) Read() (*mcp.ResourcesReadResult, error) {
 
 // --- specmcp://guardrails resource ---
 
-// GuardrailsResource exposes the guardrail rules as a reference resource.
-type GuardrailsResource struct{}
+// GuardrailsResource exposes the guardrail rules as a reference resource,
+// followed by the effective guard policy: each built-in guard's compiled-in
+// severity and the configured overrides.
+type GuardrailsResource struct {
+	Guards []guards.GuardInfo // Built-in guards (nil = those of the default workflow)
+	Policy *guards.GuardPolicy
+	Rules  []*guards.Rule // Configured custom rules
+}
 
 func (r *GuardrailsResource) Definition() mcp.ResourceDefinition {
 	return mcp.ResourceDefinition{
@@ -49,12 +61,63 @@ func (r *GuardrailsResource) Read() (*mcp.ResourcesReadResult, error) {
 			{
 				URI:      "specmcp://guardrails",
 				MimeType: "text/markdown",
-				Text:     guardrailsContent,
+				Text:     guardrailsContent + effectivePolicy(r.Guards, r.Policy, r.Rules),
 			},
 		},
 	}, nil
 }
 
+// effectivePolicy renders the guard policy section of the guardrails
+// resource.
+func effectivePolicy(builtins []guards.GuardInfo, policy *guards.GuardPolicy, rules []*guards.Rule) string {
+	if builtins == nil {
+		builtins = guards.BuiltinGuards(nil)
+	}
+	var sb strings.Builder
+	sb.WriteString("\n## Effective Guard Policy\n\n")
+	sb.WriteString("Severities after the [guards] overrides of specmcp.toml. A Constitution's guard_policy is applied on top per project, and overrides naming an app (guard@app) apply to changes and artifacts scoped to it.\n\n")
+	sb.WriteString("| Guard | Runs on | Default | Effective |\n")
+	sb.WriteString("|-------|---------|---------|-----------|\n")
+	known := make(map[string]bool, len(builtins))
+	for _, g := range builtins {
+		known[g.Name] = true
+		def := g.Severity.String()
+		if g.ConfiguredBy != "" {
+			def += " (" + g.ConfiguredBy + ")"
+		}
+		effective := "off"
+		if policy.Enabled(g.Name, nil) {
+			effective = policy.Severity(g.Name, nil, g.Severity).String()
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", g.Name, g.RunsOn, def, effective)
+	}
+
+	var scoped []string
+	if policy != nil {
+		for _, o := range policy.Overrides {
+			if o.App != "" || !known[o.Guard] {
+				scoped = append(scoped, o.String())
+			}
+		}
+	}
+	if len(scoped) > 0 {
+		sb.WriteString("\nOther overrides (per app, or for guards not listed above):\n\n")
+		for _, o := range scoped {
+			fmt.Fprintf(&sb, "- %s\n", o)
+		}
+	}
+
+	if len(rules) > 0 {
+		sb.WriteString("\n### Configured Custom Rules\n\n")
+		sb.WriteString("| Rule | Runs on | Severity | Expression |\n")
+		sb.WriteString("|------|---------|----------|------------|\n")
+		for _, r := range rules {
+			fmt.Fprintf(&sb, "| %s | %s | %s | %s |\n", r.Name, strings.Join(r.On, ", "), policy.Severity(r.Name, nil, r.Severity), strings.ReplaceAll(r.Expr, "|", "\\|"))
+		}
+	}
+	return sb.String()
+}
+
 // --- specmcp://tool-reference resource ---
 
 // ToolReferenceResource exposes a quick-reference card for all 27 tools.
@@ -138,12 +201,14 @@ func (r *WorkflowResource) Read() (*mcp.ResourcesReadResult, error) {
 
 const entityModelContent = `# SpecMCP Entity Model
 
-## Entity Types (18)
+## Entity Types (21)
 
 ### Change
 Top-level container for a feature, bug fix, or refactoring effort.
-- **Properties**: name (string, required), status (string: active/archived), base_commit (string), tags ([]string)
+- **Properties**: name (string, required), status (string: active/archived), base_commit (string), branch_id (string, set by spec_new with branch=true), tags ([]string)
 - **Relationships**:
+  - depends_on_change → Change (must be archived first; no cycles)
+  - scoped_to_app → App (set by spec_new apps; selects per-app guard overrides)
   - has_proposal → Proposal (1:1)
   - has_spec → Spec (1:N)
   - has_design → Design (1:1)
@@ -158,15 +223,23 @@ Captures the intent of a change — why it exists.
 
 ### Spec
 Domain-specific specification container holding requirements.
-- **Properties**: name (string, required), domain (string), purpose (string), delta_type (string), status (string: draft/ready), tags ([]string)
+- **Properties**: name (string, required), domain (string), purpose (string), delta_type (string: added/modified/removed/renamed), renamed_from (string), status (string: draft/ready), tags ([]string)
 - **Relationships**:
   - has_requirement → Requirement (1:N)
+  - merged_into → LivingSpec (set when the change is archived)
 
 ### Requirement
 Specific behavior the system must have.
-- **Properties**: name (string, required), description (string, required), strength (string: MUST/SHOULD/MAY), delta_type (string), status (string: draft/ready), tags ([]string)
+- **Properties**: name (string, required), description (string, required), strength (string: MUST/SHOULD/MAY), delta_type (string: added/modified/removed/renamed), renamed_from (string, the old name for renamed), status (string: draft/ready), tags ([]string)
 - **Relationships**:
   - has_scenario → Scenario (1:N)
+  - supersedes → Requirement (the living spec version this one replaced)
+
+### LivingSpec
+Canonical, change-independent specification of one domain, built by merging the delta Specs of archived changes. One per domain (keyed by domain).
+- **Properties**: name (string), domain (string, required), purpose (string), status (string: active/removed), revision (int), last_change (string), tags ([]string)
+- **Relationships**:
+  - includes_requirement → Requirement (the domain's current requirements; edge records change, revision, delta)
 
 ### Scenario
 Concrete example of a requirement in BDD format.
@@ -214,11 +287,26 @@ Reusable implementation convention.
 
 ### Constitution
 Project-wide principles and guardrails.
-- **Properties**: name (string, required), version (string, required), principles (string), guardrails ([]string), testing_requirements (string), security_requirements (string), patterns_required ([]string), patterns_forbidden ([]string), tags ([]string)
+- **Properties**: name (string, required), version (string, required), principles (string), guardrails ([]string), testing_requirements (string), security_requirements (string), patterns_required ([]string), patterns_forbidden ([]string), review_policy ([]string, e.g. "Proposal: 1 human"), workflow_stages ([]string, e.g. "design?: design after specify"), guard_policy ([]string, e.g. "context_discovery@billing-api: off"), guard_rules ([]object: name, on, expr, severity, message, remedy), tags ([]string)
 - **Relationships**:
   - requires_pattern → Pattern
   - forbids_pattern → Pattern
 
+### Review
+A review request or decision on a workflow artifact. Only each reviewer's latest Review counts; a decision made before the artifact was edited is stale (artifact_digest, which covers the content but not status or transitions, no longer matches).
+- **Properties**: name (string, required), status (string: requested/approved/rejected, required), reviewer (string, required), reviewer_type (string: human/ai), artifact_type (string), artifact_digest (string), comment (string), requested_by (string), requested_at (time), decided_at (time), tags ([]string)
+- **Relationships**:
+  - reviews → Proposal/Spec/Requirement/Scenario/Design
+  - reviewed_by → Agent/Actor
+
+### Comment
+Feedback on any entity. A comment without reply_to starts a thread; replies link to their parent. Resolved state lives on the thread's first comment, and unresolved threads on a workflow artifact soft-block spec_mark_ready.
+- **Properties**: name (string, required), body (string, required), author (string, required), author_type (string: human/ai), reply_to (string, canonical ID of the parent comment), resolved (bool), resolved_by (string), resolved_at (time), created_at (time), tags ([]string)
+- **Relationships**:
+  - comments_on → any entity (replies point at the thread's entity too)
+  - reply_to → Comment
+  - authored_by → Agent/Actor
+
 ### TestCase
 Links scenarios to executable tests.
 - **Properties**: name (string, required), test_file (string), test_function (string), test_framework (string), status (string), last_run_at (time), coverage_percent (float), tags ([]string)
@@ -258,6 +346,7 @@ Tracks synchronization state between graph and codebase.
 
 | Relationship | Source | Target | Bidirectional? |
 |-------------|--------|--------|----------------|
+| depends_on_change | Change | Change | No |
 | has_proposal | Change | Proposal | No |
 | has_spec | Change | Spec | No |
 | has_design | Change | Design | No |
@@ -288,8 +377,16 @@ Tracks synchronization state between graph and codebase.
 | uses_component | UIComponent | UIComponent | No |
 | nested_in | UIComponent | UIComponent | No |
 | available_in | Context | Action | No |
+| reviews | Review | Proposal/Spec/Requirement/Scenario/Design | No |
+| reviewed_by | Review | Agent/Actor | No |
+| comments_on | Comment | any entity | No |
+| reply_to | Comment | Comment | No |
+| authored_by | Comment | Agent/Actor | No |
 | navigates_to | Context | Context | No |
 | owned_by | Context | Actor | No |
+| merged_into | Spec | LivingSpec | No |
+| includes_requirement | LivingSpec | Requirement | No |
+| supersedes | Requirement | Requirement | No |
 
 ## Tagging Conventions
 
@@ -311,6 +408,19 @@ Tags use namespaced conventions:
 | in_progress | Task, GraphSync |
 | completed | Task, GraphSync |
 | blocked | Task |
+
+## Status Transitions
+
+Statuses change only along these transitions (spec_transition, or the tool that owns the transition):
+
+| Entity | Allowed transitions | Soft guards (force=true overrides) |
+|--------|---------------------|------------------------------------|
+| Change | active → archived, archived → active | archiving: all tasks completed |
+| Task | pending → in_progress/blocked, in_progress → completed/blocked, blocked → pending | completing: all subtasks completed |
+| Improvement | proposed → planned/deferred/rejected, planned → in_progress/deferred, in_progress → completed/deferred, deferred → proposed | planned: has tasks; completed: all tasks completed |
+| Proposal, Spec, Requirement, Scenario, Design | draft → ready, ready → draft | ready: children ready |
+
+Every transition is appended to the entity's **transitions** property: from, to, at, reason, forced, and via (the tool that made it).
 `
 
 const guardrailsContent = `# SpecMCP Guardrails Reference
@@ -345,18 +455,82 @@ Each guard returns a result with one of four severity levels.
 
 These guards check **readiness**, not just existence. Use ` + "`spec_mark_ready`" + ` to mark artifacts as ready before progressing.
 
+The artifact guards are generated from the project's workflow stages: one <stage>_prerequisites guard per stage with requirements. With the default workflow:
+
 | # | Guard | Severity | Checks |
 |---|-------|----------|--------|
-| 1 | proposal_before_spec | HARD_BLOCK | Change has a **ready** Proposal before adding Spec/Requirement/Scenario |
-| 2 | spec_before_design | HARD_BLOCK | Change has **ready** Proposal + all Specs **ready** before adding Design |
-| 3 | design_before_tasks | HARD_BLOCK | Change has a **ready** Design before adding Tasks |
+| 1 | specify_prerequisites | HARD_BLOCK | Change has a **ready** Proposal before adding Spec/Requirement/Scenario |
+| 2 | design_prerequisites | HARD_BLOCK | Change has **ready** Proposal + all Specs **ready** before adding Design |
+| 3 | implement_prerequisites | HARD_BLOCK | Change has a **ready** Design before adding Tasks |
+
+### Workflow Stages
+
+A workflow is an ordered list of stages, each written as "<name>[?]: [artifact types] [after <stage>, ...]". The default is:
+
+- propose: proposal
+- specify: spec, requirement, scenario, scenario_step after propose
+- design: design after propose, specify
+- implement: task after design
+
+A stage is complete when it has artifacts and all are ready (specs including their requirements and scenarios; tasks completed). A trailing "?" makes a stage optional: it is skipped while it has no artifacts, so it does not hold up later stages. Artifacts tagged "stage:<name>" belong to that stage whatever their type, which lets a tag-only stage such as "threat-model?: after specify" collect e.g. a Design tagged stage:threat-model. Stages may only require stages defined before them, and only the stages listed after "after" are checked.
+
+The workflow comes from the Constitution's workflow_stages, else the [workflow] stages of specmcp.toml, else the default. For small fixes, make design optional and let tasks follow the specs: "design?: design after specify" and "implement: task after specify, design".
 
 ### Archive Guards (run on spec_archive)
 
 | # | Guard | Severity | Checks |
 |---|-------|----------|--------|
-| 1 | artifact_completeness | SOFT_BLOCK | Change has proposal, specs, design, and tasks |
+| 1 | artifact_completeness | SOFT_BLOCK | Every non-optional workflow stage has artifacts (by default proposal, specs, design, and tasks) |
 | 2 | task_completion | SOFT_BLOCK | All tasks have status=completed |
+| 3 | dependencies_archived | SOFT_BLOCK | Every change this one depends on (depends_on_change) is archived |
+
+### Mark Ready Guards (run on spec_mark_ready)
+
+| # | Guard | Severity | Checks |
+|---|-------|----------|--------|
+| 1 | requirement_quality | WARNING (lint.severity) | Requirements only. Description uses one RFC 2119 keyword matching strength, has no vague terms ("fast", "user-friendly", "etc.") or non-testable phrasing ("as soon as possible", "be able to"), and with lint.ears follows an EARS template |
+| 2 | review_approval | HARD_BLOCK (review.severity) | Artifact types named in the review policy. No outstanding rejection, and at least the required number of current (non-stale) approvals, from humans when the rule says human |
+| 3 | open_comments | SOFT_BLOCK | No unresolved comment threads on the artifact (spec_resolve_comment) |
+
+The linter is configured in the [lint] section of specmcp.toml: enabled, severity (suggestion, warning, soft_block, hard_block), ears, and extra vague_terms. Each issue comes back in lint_issues with a suggestion, plus a suggested_rewrite of the whole description.
+
+The review policy is a list of rules of the form "<ArtifactType>: <count> [human]", from the [review] section of specmcp.toml (policy, severity) and the Constitution's review_policy, which can raise the configured rule for the types it names but not lower it: the higher approval count applies, and human stays required once either requires it. Reviews are requested with spec_request_review and decided with spec_approve_review or spec_reject_review.
+
+## Guard Policy
+
+The severities above are compiled-in defaults. A guard policy overrides them per guard, with entries of the form "<guard>[@<app>]: <severity|off>":
+
+- "patterns_seeded: warning" — patterns no longer soft-block spec_new
+- "context_discovery@billing-api: off" — no UI-discovery suggestion for changes scoped to the billing-api App
+- "design_prerequisites: soft_block" — a design may be added before the specs are ready with force=true
+
+Overrides come from the [guards] overrides of specmcp.toml and the Constitution's guard_policy, which wins per guard and app. Only the config can turn off or downgrade a guard that hard-blocks by default (kebab_case_name, constitution_required, the stage guards, review_approval); a Constitution override that tries is rejected by constitution_create and fails the guarded tools until removed. An override naming an app applies to changes scoped to it (spec_new apps, stored as scoped_to_app) and to artifacts scoped to it; otherwise the global override, if any, applies. When a change spans several apps, the strictest severity among them wins, and the guard is skipped only if every app turns it off. Skipped guards are reported as disabled. An override must name a built-in guard of the project's workflow or a custom rule; an unknown name (e.g. a typo) is rejected at startup and by constitution_create.
+
+## Custom Rules
+
+Teams can add their own guards without writing Go. A rule is an expr-lang (https://expr-lang.org) expression that must be true, with:
+
+- name: the guard name (lowercase words joined by underscores); the guard policy can override its severity like any other guard
+- on: the operations it runs on — new_change (spec_new), artifact (spec_artifact, spec_generate_tasks), mark_ready (spec_mark_ready), archive (spec_archive)
+- expr: the expression
+- severity: suggestion, warning (default), soft_block, or hard_block when the expression is false
+- message and remedy: shown when it fails
+
+Rules come from [[guards.rules]] in specmcp.toml and the Constitution's guard_rules, whose rules replace configured rules of the same name. They run after the built-in guards of each operation.
+
+Expressions see these facts:
+
+- operation: the operation being guarded
+- change: the change's properties (name, status, tags, ...), apps, and links; on new_change only name, tags, and apps; empty on mark_ready
+- artifact: on artifact, the content being added with its type (e.g. "Design") and tags; on mark_ready, the artifact with its properties and links
+- counts: specs, tasks, completed_tasks, pending_tasks, patterns, contexts, components, open_comments, approvals, human_approvals
+- proposals, specs, requirements, scenarios, designs, tasks: the change's artifacts, each with id, type, name, status, tags, and its properties
+
+linked(entity, "<relationship>") returns the names of the entities an entity links to, e.g. linked(#, "executed_by") for a scenario's Actors. Properties an entity does not have are nil, so default them where needed: len(.file_changes ?? []). A rule that fails to evaluate reports a WARNING instead of blocking. Examples:
+
+- A payments change needs an auditor scenario (on: archive): !("domain:payments" in change.tags) || any(scenarios, {"auditor" in linked(#, "executed_by")})
+- Designs must list file_changes (on: mark_ready): artifact.type != "Design" || len(artifact.file_changes ?? []) > 0
+- No tasks without a ready design (on: artifact): artifact.type != "Task" || all(designs, {.status == "ready"})
 
 ## Verification Dimensions (spec_verify)
 
@@ -370,6 +544,7 @@ The spec_verify tool performs deeper analysis across three dimensions:
 
 ### 2. Correctness
 - Requirements map to scenarios (coverage check)
+- Requirement wording passes the requirement quality linter, with suggested rewrites as remedies
 - Design references applicable patterns
 - Tasks implement requirements
 
@@ -384,6 +559,12 @@ Guards receive a populated GuardContext containing:
 - Project-level state: HasConstitution, HasPatterns, ContextCount, ComponentCount
 - Change-level state: ChangeName, ArtifactType, HasProposal, HasSpec, HasDesign, HasTasks, TaskCount, CompletedTasks
 - Readiness state: ProposalReady, AllSpecsReady, DesignReady
+- Workflow: Workflow (stage definitions), Artifacts (the change's proposals, specs, designs, and tasks with readiness and tags), ArtifactTags
+- Requirement under review (spec_mark_ready): RequirementName, RequirementDescription, RequirementStrength
+- Review state (spec_mark_ready): Approvals, HumanApprovals, RejectedBy, PendingReviewers
+- Comment state (spec_mark_ready): OpenComments
+- Guard policy: Policy (severity overrides), Apps (the Apps the change or artifact is scoped to)
+- Custom rules: Rules and the Facts they are evaluated over, built from ChangeTags, ArtifactID, ArtifactProperties, and the change's artifacts; FactsTruncated is set when the change is too large to load them all, and every rule then fails with WARNING instead of being evaluated
 
 The context is populated once via graph queries, then shared across all guards to avoid N+1 query patterns.
 `
@@ -485,15 +666,23 @@ SpecMCP enforces guardrails at three points:
 ### Artifact Guards (spec_artifact)
 | Guard | Severity | What it checks |
 |-------|----------|----------------|
-| proposal_before_spec | HARD_BLOCK | Proposal required before specs |
-| spec_before_design | HARD_BLOCK | Specs required before design |
-| design_before_tasks | HARD_BLOCK | Design required before tasks |
+| specify_prerequisites | HARD_BLOCK | Proposal required before specs (default workflow) |
+| design_prerequisites | HARD_BLOCK | Specs required before design (default workflow) |
+| implement_prerequisites | HARD_BLOCK | Design required before tasks (default workflow) |
 
 ### Archive Guards (spec_archive)
 | Guard | Severity | What it checks |
 |-------|----------|----------------|
-| artifact_completeness | SOFT_BLOCK | All core artifacts should exist |
+| artifact_completeness | SOFT_BLOCK | Every required workflow stage should have artifacts |
 | task_completion | SOFT_BLOCK | All tasks should be completed |
+| dependencies_archived | SOFT_BLOCK | Changes this one depends on should be archived first |
+
+### Mark Ready Guards (spec_mark_ready)
+| Guard | Severity | What it checks |
+|-------|----------|----------------|
+| requirement_quality | WARNING (configurable) | Requirement wording is testable and matches its strength |
+| review_approval | HARD_BLOCK (configurable) | Artifact has the approvals the review policy requires and no outstanding rejection |
+| open_comments | SOFT_BLOCK | No unresolved comment threads on the artifact |
 
 **Severity levels**:
 - **HARD_BLOCK**: Cannot proceed. Fix the issue first.
@@ -501,19 +690,31 @@ SpecMCP enforces guardrails at three points:
 - **WARNING**: Advisory — action recommended but not required.
 - **SUGGESTION**: Informational tip.
 
+Every severity above is a default: the [guards] overrides of specmcp.toml and a Constitution's guard_policy can change it or turn a guard off, for the whole project or per app (e.g. "context_discovery@billing-api: off"). Custom rules written as expressions ([[guards.rules]] or the Constitution's guard_rules) run alongside these guards. specmcp://guardrails lists the effective policy.
+
 ## Tools Reference
 
-### Workflow (7 tools)
+### Workflow (17 tools)
 - **spec_new** — Create a new change container
 - **spec_artifact** — Add any artifact type to a change (18 types supported)
 - **spec_batch_artifact** — Add multiple artifacts in one call
 - **spec_archive** — Archive a completed change
+- **spec_unarchive** — Reopen an archived change (requires a reason, reports archive guards that no longer pass)
 - **spec_verify** — Verify completeness, correctness, and coherence
 - **spec_mark_ready** — Mark a workflow artifact as ready (with cascading validation)
+- **spec_mark_draft** — Move a ready artifact back to draft (reopens ready parents, reports design/tasks built on it)
+- **spec_transition** — Move a Change, Task, Improvement, or workflow artifact to another status through its state machine (records the transition history)
+- **spec_update_artifact** — Edit a workflow artifact in place (resets readiness, reports stale stages)
+- **spec_delete_artifact** — Delete a workflow artifact and the subtree it owns (dry_run previews)
+- **spec_set_change_dependencies** — Add or remove the changes a change depends on (cycles rejected)
+- **spec_import_markdown** — Import a markdown change directory (proposal.md, design.md, tasks.md, specs/) idempotently
+- **spec_export_markdown** — Render a change as a markdown directory or a PR description
+- **spec_export_gherkin** — Export specs as Gherkin .feature files (variants as Scenario Outlines)
+- **spec_import_gherkin** — Import Gherkin .feature files into a change's specs, requirements, and scenarios
 - **spec_status** — Get readiness status and next steps for a change
 
-### Query (11 tools)
-- **spec_list_changes** — List all changes (filter by status)
+### Query (14 tools)
+- **spec_list_changes** — List all changes (filter by status, or in dependency order)
 - **spec_get_change** — Get full change details with artifacts
 - **spec_get_app** — Get an app with all relationships
 - **spec_get_data_model** — Get a data model with provider/consumers
@@ -524,14 +725,27 @@ SpecMCP enforces guardrails at three points:
 - **spec_get_patterns** — Get all patterns (filter by type)
 - **spec_impact_analysis** — Analyze what's affected by a change
 - **spec_search** — Search across all entity types
+- **spec_get_living_spec** — Get the current merged spec of a domain (or list all living specs)
+- **spec_history** — List the versions of an entity and the changes that produced them
+- **spec_diff** — Property-level diff between two versions, or two changes' views, of an entity
 
 ### Task Management (5 tools)
 - **spec_generate_tasks** — Auto-generate tasks from a design
 - **spec_get_available_tasks** — Get tasks ready for work (dependencies met)
 - **spec_assign_task** — Assign a task to an agent
-- **spec_complete_task** — Mark a task as done with verification
+- **spec_complete_task** — Mark an in-progress task as done with verification
 - **spec_get_critical_path** — Find the longest dependency chain
 
+### Review (3 tools)
+- **spec_request_review** — Ask Agents or Actors to review a workflow artifact
+- **spec_approve_review** — Approve a workflow artifact as a reviewer
+- **spec_reject_review** — Reject a workflow artifact as a reviewer (comment required)
+
+### Comments (3 tools)
+- **spec_add_comment** — Comment on any entity, or reply to a comment
+- **spec_list_comments** — List an entity's comment threads with replies
+- **spec_resolve_comment** — Resolve or reopen a comment thread
+
 ### Patterns (3 tools)
 - **spec_suggest_patterns** — Suggest applicable patterns for a change
 - **spec_apply_pattern** — Link a pattern to a change
@@ -566,6 +780,7 @@ SpecMCP enforces guardrails at three points:
 
 4. **Define the change** (follow the artifact workflow):
    - Proposal → mark ready → Specs → Requirements → Scenarios → mark ready (bottom-up) → Design → mark ready → Tasks
+   - Projects can define their own stages (workflow_stages on the Constitution or [workflow] in specmcp.toml), e.g. making design optional for small fixes
    - Use ` + "`spec_status`" + ` to check readiness and see next steps at any stage
 
 5. **Implement**:
@@ -580,7 +795,7 @@ SpecMCP enforces guardrails at three points:
 
 const workflowContent = `# SpecMCP Artifact Workflow Reference
 
-Every change follows a structured artifact progression, each stage building on the previous:
+Every change follows a structured artifact progression, each stage building on the previous. The stages below are the default workflow; a project can redefine them with workflow_stages on the Constitution or [workflow] stages in specmcp.toml (see specmcp://guardrails):
 
 ## Readiness Gating
 
@@ -590,6 +805,8 @@ Workflow progression is gated on **readiness**, not just existence. Each workflo
 - Use ` + "`spec_mark_ready`" + ` to mark an artifact as **ready**
 - Readiness cascades: a Spec can't be ready unless all its Requirements are ready; a Requirement can't be ready unless all its Scenarios are ready
 - Adding a child to a ready parent (e.g., a new Requirement to a ready Spec) automatically reverts the parent to **draft**
+- Use ` + "`spec_mark_draft`" + ` to reopen a ready artifact for rework; its ready parents return to **draft** too
+- Editing an artifact with ` + "`spec_update_artifact`" + ` returns it and any ready parents to **draft**; don't re-create an artifact to change it
 - Use ` + "`spec_status`" + ` to see overall readiness and next steps
 
 ## Proposal (Why)
@@ -605,6 +822,14 @@ Define **what** the change does. Each spec is a domain-specific container.
 - Requirements contain: Scenarios (Given/When/Then BDD format)
 - Mark ready **bottom-up**: Scenarios → Requirements → Specs
 
+## Living Specs (Current Truth)
+Specs in a change are **deltas**. On ` + "`spec_archive`" + ` they are merged into one living spec per domain (the Spec's domain, or its name if unset):
+- **added** / **modified** (or no delta_type): the requirement is included, replacing a current requirement of the same name
+- **removed**: the current requirement of that name is dropped; a removed Spec drops the whole domain
+- **renamed**: replaces the current requirement named in renamed_from
+- Replaced requirements are linked with supersedes; each merge bumps the living spec's revision
+- Read the result with ` + "`spec_get_living_spec`" + ` instead of reading every archived change
+
 ## Design (How)
 Describes **how** specs will be implemented.
 - Fields: approach, decisions, data_flow, file_changes, scoped_to_apps
@@ -635,48 +860,172 @@ The workflow order is enforced by readiness-based guardrails:
 
 const toolReferenceContent = `# SpecMCP Tool Quick Reference
 
+## Common Parameters
+
+### idempotency_key
+Optional on every tool that changes the graph (spec_new, spec_artifact, spec_batch_artifact, spec_update_artifact, spec_delete_artifact, spec_mark_ready, spec_mark_draft, spec_transition, spec_archive, spec_unarchive, spec_set_change_dependencies, spec_import_markdown, spec_import_gherkin, spec_generate_tasks, spec_assign_task, spec_complete_task, spec_request_review, spec_approve_review, spec_reject_review, spec_add_comment, spec_resolve_comment, improvement_create, spec_create_constitution, spec_seed_patterns, spec_apply_pattern, spec_sync, spec_janitor_run). Can also be sent in the tools/call _meta object.
+- Retrying a call with the same key and arguments returns the original result instead of running the tool again
+- A key is bound to the arguments it was first sent with; reusing it for a different call is rejected
+- Results are remembered for a configurable window (idempotency.window_minutes, default 60)
+- Objects created under a key are labelled in the graph, so retries after a restart still reuse them and their relationships
+
+### expected_version
+Optional on tools that update entities (spec_mark_ready, spec_mark_draft, spec_transition, spec_update_artifact, spec_archive, spec_unarchive, spec_assign_task, spec_complete_task, and spec_artifact content when updating an existing entity).
+- The update only applies if the entity is still at that version; otherwise a version conflict is reported with the current version
+- Without it, the tool guards the update with the version it just read, and on conflict re-reads and retries (up to 3 times) if the update still makes sense
+
+### truncated
+Returned by tools that list across the whole project (spec_list_changes, spec_suggest_patterns, spec_janitor_run).
+- These tools follow pagination until every page is read or the list cap is reached (emergent.max_list_items, default 5000)
+- truncated=true means more entities exist than were returned or considered
+
 ## Workflow Tools
 
 ### spec_new
 Create a new change container.
 - **Required**: name (string, kebab-case)
-- **Optional**: intent (string), tags ([]string), force (bool)
+- **Optional**: intent (string), tags ([]string), force (bool), branch (bool), depends_on ([]string, change IDs or names), apps ([]string, App IDs or names)
 - **Guards**: kebab_case_name, constitution_required, patterns_seeded, context_discovery, component_discovery
+- **Apps**: links the change to each App with scoped_to_app; guard overrides for those apps (guard@app) then apply to the change. Guards turned off by the policy are listed in disabled_guards
+- **Branch**: with branch=true, creates an Emergent branch named change/<name> and records its branch_id on the Change. Every tool that works on the change or its artifacts then writes to the branch, including shared entities it creates or updates (Context, UIComponent, DataModel, ...), so the main graph is untouched until spec_archive merges the branch
 
 ### spec_artifact
 Add an artifact to an existing change. Supports 16 artifact types.
 - **Required**: change_name (string), artifact_type (string)
 - **artifact_type values**: proposal, spec, requirement, scenario, scenario_step, design, task, actor, agent, pattern, constitution, test_case, api_contract, context, ui_component, action
 - **Additional params**: vary by artifact_type (see tool's input schema)
-- **Guards**: proposal_before_spec, spec_before_design, design_before_tasks
+- **Guards**: the <stage>_prerequisites guards of the workflow (default: specify_prerequisites, design_prerequisites, implement_prerequisites); a stage:<name> tag puts the artifact in that stage
+- **Rollback**: for spec, if creating any Requirement or Scenario fails, the whole hierarchy is deleted and the error reports what was rolled back
 
 ### spec_archive
 Archive a completed change.
 - **Required**: change_name (string)
-- **Optional**: force (bool)
-- **Guards**: artifact_completeness, task_completion
+- **Optional**: force (bool), reason (string), expected_version (int)
+- **Guards**: artifact_completeness, task_completion, dependencies_archived
+- **Records**: archived_from (the status to restore) and archived_at on the Change
+- **Branch merge**: for a change created with branch=true, previews merging its branch into the main graph; any conflicts block archiving (force does not override) and are listed with their conflicting property paths. Otherwise the branch is merged once the change is archived and branch_merged_at recorded
+- **Living specs**: once the change is archived, merges its Specs into the living spec of each domain (see Living Specs)
+- **Order**: the status is written first, with merge_pending set until both merges are done; a conflicting or rejected archive merges nothing. If a merge fails the change stays archived, and calling spec_archive again finishes the merges
+- **Returns**: branch_merge (added, fast_forwards, unchanged), living_specs (per domain: revision, added, modified, removed, renamed, warnings), advisories
+
+### spec_unarchive
+Reopen an archived change, restoring the status it had before archiving.
+- **Required**: change_id (string), reason (string)
+- **Optional**: expected_version (int)
+- **Records**: unarchived_at and unarchive_reason on the Change
+- **Reverse guards**: re-runs the archive guards against the current state
+- **Living specs**: merges into living specs are kept, not reverted; they are listed as living_spec_contributions (spec, living_spec_id, revision)
+- **Returns**: status, ready_to_archive, archive_issues (guards that would now fail on spec_archive), living_spec_contributions
 
 ### spec_verify
 Verify change completeness, correctness, and coherence.
 - **Required**: change_name (string)
+- **Requirement lint**: correctness reports each Requirement wording issue with a suggested fix
 - **Returns**: structured report with issues by dimension and severity
 
 ### spec_mark_ready
 Mark a workflow artifact (Proposal, Spec, Requirement, Scenario, Design) as ready.
 - **Required**: entity_id (string)
+- **Optional**: expected_version (int), force (bool, overrides soft blocks), reason (string)
 - **Cascading validation**: For Specs, all Requirements must be ready. For Requirements, all Scenarios must be ready.
-- **Returns**: success confirmation, or blockers list with unready children (id, type, name, status)
+- **Requirement lint**: requirement_quality guard flags missing or inconsistent RFC 2119 keywords, vague terms, non-testable phrasing, and optionally non-EARS wording
+- **Review policy**: review_approval guard requires the approvals configured for the artifact type and blocks on an outstanding rejection
+- **Open comments**: open_comments guard soft-blocks while comment threads on the artifact are unresolved
+- **Returns**: success confirmation with any lint_issues, suggested_rewrite, and reviews summary, or blockers list with unready children (id, type, name, status)
+
+### spec_mark_draft
+Move a ready workflow artifact (Proposal, Spec, Requirement, Scenario, Design) back to draft.
+- **Required**: entity_id (string)
+- **Optional**: expected_version (int), reason (string)
+- **Reverse cascade**: ready parents (Scenario → Requirement → Spec) return to draft as well
+- **Refused** for artifacts of archived changes
+- **Returns**: reverted_parents, built_on_top (artifacts of the workflow stages that require the reopened artifact's stage, directly or indirectly)
+
+### spec_transition
+Move a Change, Task, Improvement, or workflow artifact to another status through its state machine (see Status Transitions in specmcp://entity-model).
+- **Required**: entity_id (string), to_status (string)
+- **Optional**: force (bool, overrides soft guards), reason (string), expected_version (int)
+- **Owned transitions**: moves with side effects go to their tool with the same force and reason: ready → spec_mark_ready, draft → spec_mark_draft, archiving → spec_archive, unarchiving → spec_unarchive (reason required), completing a Task → spec_complete_task. Starting a Task needs an agent, so use spec_assign_task
+- **History**: every transition, whichever tool makes it, is appended to the entity's transitions property (from, to, at, reason, forced, via)
+- **Returns**: from_status, status, version, transitions
+
+### spec_update_artifact
+Edit an existing workflow artifact (Proposal, Spec, Requirement, Scenario, Design) instead of creating a duplicate.
+- **Required**: entity_id (string), properties (object; only the given properties change, status is not allowed)
+- **Optional**: expected_version (int)
+- **Readiness reset**: a ready artifact returns to draft; ready parents (Scenario → Requirement → Spec) are reset too
+- **Refused** for artifacts of archived changes
+- **Returns**: updated properties, was_ready, reverted_parents, stale_stages (artifacts of the workflow stages that require the edited artifact's stage, directly or indirectly)
+
+### spec_delete_artifact
+Delete a workflow artifact (Proposal, Spec, Requirement, Scenario, ScenarioStep, Design, Task) and the subtree it owns.
+- **Required**: entity_id (string)
+- **Optional**: dry_run (bool), force (bool)
+- **Subtree**: Spec → Requirements → Scenarios → ScenarioSteps; Task → subtasks. Every relationship of a deleted object goes too, including blocks edges
+- **Guards**: ready artifacts and in-progress/completed tasks need force=true; artifacts of archived changes cannot be deleted
+- **Order**: bottom-up (each object's relationships, then the object); ready parents of the root return to draft
+- **Returns**: objects (id, type, name, status, depth), blocks_edges, protected; with dry_run the relationship count, otherwise deleted_objects, deleted_relationships, reverted_parents
+
+### spec_set_change_dependencies
+Declare the changes a change builds on, e.g. add-roles after add-auth.
+- **Required**: change_id (string)
+- **Optional**: add ([]string), remove ([]string) — change IDs or names
+- **Cycles**: an addition that would make a change depend on itself, directly or transitively, is rejected with the dependency chain
+- **Refused** for archived changes
+- **Returns**: depends_on (name, status), dependents, removed, dangling_dependencies (depends_on_change relationships to deleted objects, which are ignored), and truncated when there are too many changes or dependencies to read in full, in which case adding dependencies is refused because cycles cannot be ruled out
+
+### spec_import_markdown
+Import a markdown change directory into a Change and its artifacts.
+- **Required**: files (object: path within the change directory → content; the tool never reads files), name (string)
+- **Optional**: dry_run (bool)
+- **Layout**: proposal.md (## Why → intent, ## Impact → impact, the rest → scope), design.md (## Decisions, ## Data Flow, ## File Changes, the rest → approach), tasks.md, specs/<capability>/spec.md
+- **Specs**: ## ADDED/MODIFIED/REMOVED/RENAMED Requirements set delta_type; ### Requirement: blocks take strength from their SHALL/MUST/SHOULD/MAY; #### Scenario: blocks read - **GIVEN/WHEN/THEN/AND** lines
+- **Tasks**: ## N. Section → parent task N; - [ ] N.M / - [x] N.M → subtask N.M, pending or completed
+- **Idempotent**: specs, requirements, and scenarios match by name, tasks by number, under their parent; unchanged entities are not rewritten, changed ready artifacts return to draft with a transition history entry, and adding or changing a child returns its ready parents to draft; entities missing from the markdown are kept
+- **Task status**: a changed checkbox goes through the task transition table; a move it refuses (e.g. completed back to pending) keeps the stored status and is reported in warnings
+- **Atomic creates**: if the import fails partway, the entities it created are deleted
+- **Refused** for archived changes
+- **Returns**: change_id, counts (created, updated, unchanged per entity type), reverted_parents, warnings
+- **CLI**: specmcp import [--dry-run] [--name n] <dir> reads the directory locally and names the change after it by default
+
+### spec_export_markdown
+Render a change as markdown for review or readers without an MCP client.
+- **Required**: change_id (string) — ID or name
+- **Optional**: format ("tree" default, or "pr")
+- **tree**: the layout spec_import_markdown reads; re-importing it reproduces proposal, design, specs, requirements (strength spelled out as **Strength**: when the wording doesn't imply it), scenarios, and task numbers and statuses
+- **pr**: one document with Why / What Changes / Impact, each spec's requirements with strength and scenarios, the design, and the task checklist
+- **Returns**: files (path → content), or markdown (pr); the tool never writes files
+- **CLI**: specmcp export [--pr] [--out path] <change> writes the content locally
+
+### spec_export_gherkin
+Export specs as Gherkin .feature files for Cucumber.
+- **Required**: spec_id or change_id (ID or name) — one spec, or every spec of the change
+- **Mapping**: Spec → Feature (purpose as description), Requirement → Rule, Scenario → Scenario (Given, When, ScenarioSteps as And after When, Then, and_also as And after Then)
+- **Variants**: a scenario with variant_of variants becomes a Scenario Outline; the Examples table has a row per scenario (base first) and a column for each field that differs
+- **Tags**: Tags → @tags; executed_by actors → @actor:<name>; a strength the description's wording doesn't imply → @strength:<level> on the Rule
+- **Returns**: files (name → content); the tool never writes files
+
+### spec_import_gherkin
+Import Gherkin .feature files into a change.
+- **Required**: change_id (ID or name), files (object: file name → .feature content; the tool never reads files)
+- **Optional**: dry_run (bool)
+- **Mapping**: the reverse of spec_export_gherkin; scenarios outside a Rule go to a requirement named after the Feature; Outline rows after the first become variants linked by variant_of
+- **Actors**: @actor:<name> links executed_by to an existing Actor; unknown actors are reported in warnings
+- **Idempotent**: specs, requirements, and scenarios match by name, steps by sequence; unchanged entities are not rewritten, and changed or added artifacts return their ready parents (and themselves) to draft
+- **Atomic creates**: if the import fails partway, the entities it created are deleted
+- **Not supported**: Background sections and step data tables are skipped
+- **Returns**: counts (created, updated, unchanged per entity type), reverted_parents, warnings
 
 ### spec_status
 Get readiness status and next steps for a change.
 - **Required**: change_id (string)
-- **Returns**: workflow stage, per-artifact readiness summaries, prioritized next_steps, ready_to_archive boolean
+- **Returns**: workflow stage and per-stage progress (status pending/in_progress/complete/skipped) from the project's workflow, per-artifact readiness summaries with open_comments (unresolved comment threads), prioritized next_steps, ready_to_archive boolean, total open_comments (with open_comments_truncated when the change is too large to load every comment, making the counts lower bounds)
 
 ## Query Tools
 
 ### spec_list_changes
-- **Optional**: status (string: active/archived)
-- **Returns**: list of changes with summary
+- **Optional**: status (string: active/archived), limit (int, caps the listing below the server's list cap), order (string: dependency)
+- **Returns**: list of changes with summary, truncated flag; with order=dependency, each change follows the changes it depends on and lists depends_on and waiting_on (dependencies not yet archived), plus dangling_dependencies for depends_on_change relationships to deleted objects, which are ignored; truncated is also set when the dependency graph could not be read in full
 
 ### spec_get_change
 - **Required**: change_name (string)
@@ -706,11 +1055,27 @@ Get readiness status and next steps for a change.
 - **Required**: change_name (string)
 - **Returns**: affected entities, relationship chains, risk assessment
 
+### spec_get_living_spec
+- **Optional**: domain (string; omit to list all living specs), include_history (bool), exclude_scenarios (bool)
+- **Returns**: living spec with revision, last_change, current requirements (with the change and revision each came from) and their scenarios; with include_history, the merges that produced each revision
+
+### spec_history
+- **Required**: entity_id (string; any version ID or the canonical ID)
+- **Optional**: include_properties (bool)
+- **Returns**: versions oldest first, each with created_at, change_summary, and the changes linked to that exact version (change_creates, change_modifies, change_references)
+
+### spec_diff
+- **Required**: entity_id (string)
+- **Optional**: from_version / from_change, to_version / to_change (a change ID or name selects the newest version that change is linked to)
+- **Defaults**: to is the latest version, from is the version before to, so to_change alone shows what that change did to a shared entity such as a DataModel
+- **Returns**: properties (path, op added/removed/changed, from, to; nested objects diffed by dotted path), labels_added, labels_removed
+
 ## Task Management Tools
 
 ### spec_generate_tasks
 - **Required**: change_name (string)
 - **Returns**: auto-generated tasks based on design analysis
+- **Rollback**: if any task or relationship fails to create, everything created by the call is deleted and the error reports what was rolled back
 
 ### spec_get_available_tasks
 - **Required**: change_name (string)
@@ -718,22 +1083,60 @@ Get readiness status and next steps for a change.
 
 ### spec_assign_task
 - **Required**: task_id (string), agent_name (string)
+- **Optional**: expected_version (int)
 - **Returns**: updated task with assignment
 
 ### spec_complete_task
 - **Required**: task_id (string)
-- **Optional**: verification_notes (string), artifacts ([]string)
+- **Optional**: verification_notes (string), artifacts ([]string), force (bool, completes despite open subtasks or a pending task), reason (string), expected_version (int)
+- **Only** in_progress tasks can be completed without force; assign a pending task first, or force it
 - **Returns**: updated task with completion timestamp
 
 ### spec_get_critical_path
 - **Required**: change_name (string)
 - **Returns**: longest dependency chain with total complexity
 
+## Review Tools
+
+### spec_request_review
+- **Required**: entity_id (string), reviewers ([]string, Agent or Actor names)
+- **Optional**: requested_by (string), comment (string)
+- **Returns**: requested reviews; reviewers with a pending request are listed in already_pending
+
+### spec_approve_review
+- **Required**: entity_id (string)
+- **Optional**: reviewer (string, default: the Agent or Actor whose subject is your Emergent user), comment (string)
+- **Identity**: a reviewer bound to an Emergent user (subject) can only decide with that user's token, and a human reviewer must be bound; AI agents without a subject can be named by anyone. The deciding user is recorded as decided_by
+- **Human approvals**: only approvals by a human reviewer with decided_by set count toward "human" review rules, once per Emergent user. Only one Agent or Actor may be bound to a given subject
+- **Returns**: the review and the artifact's review summary (approvals, human_approvals, approved_by, rejected_by, pending, stale)
+
+### spec_reject_review
+- **Required**: entity_id (string), comment (string)
+- **Optional**: reviewer (string, same identity rules as spec_approve_review)
+- **Returns**: the review and the artifact's review summary
+
+## Comment Tools
+
+### spec_add_comment
+- **Required**: author (string, Agent or Actor name), body (string), and entity_id (string) or reply_to (string, comment ID)
+- **Optional**: tags ([]string)
+- **Returns**: comment_id and the entity's open_threads count
+
+### spec_list_comments
+- **Required**: entity_id (string)
+- **Optional**: unresolved_only (bool)
+- **Returns**: threads (first comment with replies, oldest first), open_threads, total comments
+
+### spec_resolve_comment
+- **Required**: comment_id (string, any comment in the thread)
+- **Optional**: resolved_by (string), reopen (bool)
+- **Returns**: thread_id, resolved state, and the entity's open_threads count
+
 ## Pattern Tools
 
 ### spec_suggest_patterns
 - **Required**: change_name (string)
-- **Returns**: patterns that may apply based on change analysis
+- **Returns**: patterns that may apply based on change analysis, truncated flag if the similar-entity scan hit the list cap
 
 ### spec_apply_pattern
 - **Required**: change_name (string), pattern_name (string)
@@ -748,7 +1151,7 @@ Get readiness status and next steps for a change.
 ### spec_create_constitution
 Create or update the project constitution (no change_id required).
 - **Required**: name (string), version (string), principles (string)
-- **Optional**: guardrails ([]string), testing_requirements (string), security_requirements (string), patterns_required ([]string), patterns_forbidden ([]string)
+- **Optional**: guardrails ([]string), testing_requirements (string), security_requirements (string), patterns_required ([]string), patterns_forbidden ([]string), review_policy ([]string), workflow_stages ([]string), guard_policy ([]string), guard_rules ([]object)
 - **Returns**: constitution entity with linked patterns
 
 ### spec_validate_constitution
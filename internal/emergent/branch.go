@@ -0,0 +1,144 @@
+package emergent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/branches"
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+// MainBranch is the merge target for a change branch that has no parent
+// branch recorded: the project's default branch.
+const MainBranch = "main"
+
+// ErrNotOnBranch is returned when an object outside the active branch is
+// updated and cannot be copied onto it.
+var ErrNotOnBranch = errors.New("object is not on the active branch")
+
+// branchContextKey is the context key type for the active Emergent branch.
+type branchContextKey struct{}
+
+// branchKey is the context key for the active Emergent branch.
+var branchKey = branchContextKey{}
+
+// WithBranch returns a context that routes writes and listings to the given
+// Emergent branch. Objects and relationships created through a Client with
+// this context land on the branch, listings are scoped to it, and updates to
+// objects outside it are copied onto it first (see UpdateObject). An empty
+// branchID leaves ctx unchanged.
+func WithBranch(ctx context.Context, branchID string) context.Context {
+	if branchID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, branchKey, branchID)
+}
+
+// WithoutBranch returns a context that targets the main branch even if ctx
+// carries a branch.
+func WithoutBranch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, branchKey, "")
+}
+
+// BranchFrom extracts the active Emergent branch from the context.
+// Returns empty string for the main branch.
+func BranchFrom(ctx context.Context) string {
+	if v, ok := ctx.Value(branchKey).(string); ok {
+		return v
+	}
+	return ""
+}
+
+// ObjectBranch returns the branch a graph object lives on, or empty string
+// for the main branch.
+func ObjectBranch(obj *graph.GraphObject) string {
+	if obj == nil || obj.BranchID == nil {
+		return ""
+	}
+	return *obj.BranchID
+}
+
+// branchRef returns the active branch as a request field, or nil for main.
+func branchRef(ctx context.Context) *string {
+	if b := BranchFrom(ctx); b != "" {
+		return &b
+	}
+	return nil
+}
+
+// withObjectsBranch scopes object listing options to the active branch
+// unless they name one already.
+func withObjectsBranch(ctx context.Context, opts *graph.ListObjectsOptions) *graph.ListObjectsOptions {
+	branch := BranchFrom(ctx)
+	if branch == "" || opts == nil || opts.BranchID != "" {
+		return opts
+	}
+	scoped := *opts
+	scoped.BranchID = branch
+	return &scoped
+}
+
+// withRelationshipsBranch scopes relationship listing options to the active
+// branch unless they name one already.
+func withRelationshipsBranch(ctx context.Context, opts *graph.ListRelationshipsOptions) *graph.ListRelationshipsOptions {
+	branch := BranchFrom(ctx)
+	if branch == "" || opts == nil || opts.BranchID != "" {
+		return opts
+	}
+	scoped := *opts
+	scoped.BranchID = branch
+	return &scoped
+}
+
+// CreateBranch creates an Emergent branch off the main branch.
+func (c *Client) CreateBranch(ctx context.Context, name string) (*branches.Branch, error) {
+	branch, err := c.sdk.Branches.Create(ctx, &branches.CreateBranchRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("creating branch %q: %w", name, err)
+	}
+	c.logger.Debug("created branch", "name", name, "id", branch.ID)
+	return branch, nil
+}
+
+// MergeBranch merges a branch into its parent (MainBranch if it has none).
+// With execute false the merge is only previewed, which reports conflicts
+// without applying anything.
+func (c *Client) MergeBranch(ctx context.Context, branchID string, execute bool) (*graph.BranchMergeResponse, error) {
+	branch, err := c.sdk.Branches.Get(ctx, branchID)
+	if err != nil {
+		return nil, fmt.Errorf("getting branch %s: %w", branchID, err)
+	}
+	target := MainBranch
+	if branch.ParentBranchID != nil && *branch.ParentBranchID != "" {
+		target = *branch.ParentBranchID
+	}
+	resp, err := c.sdk.Graph.MergeBranch(ctx, target, &graph.BranchMergeRequest{
+		SourceBranchID: branch.ID,
+		Execute:        execute,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("merging branch %q into %s: %w", branch.Name, target, err)
+	}
+	return resp, nil
+}
+
+// forkToBranch copies an object from another branch onto the active branch
+// with props applied, by upserting it on its (type, key). Objects without a
+// key cannot be matched across branches and are refused with ErrNotOnBranch.
+func (c *Client) forkToBranch(ctx context.Context, current *graph.GraphObject, props map[string]any, labels []string) (*graph.GraphObject, error) {
+	if current.Key == nil || *current.Key == "" {
+		return nil, fmt.Errorf("%w: %s %s has no key to copy it by", ErrNotOnBranch, current.Type, current.ID)
+	}
+	merged := maps.Clone(current.Properties)
+	if merged == nil {
+		merged = make(map[string]any, len(props))
+	}
+	maps.Copy(merged, props)
+	if labels == nil {
+		labels = current.Labels
+	}
+	c.logger.Debug("copying object onto branch", "type", current.Type, "id", current.ID, "branch", BranchFrom(ctx))
+	return c.UpsertObject(ctx, current.Type, current.Key, merged, labels)
+}
@@ -10,6 +10,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"sort"
 	"time"
 
 	sdk "github.com/emergent-company/emergent/apps/server-go/pkg/sdk"
@@ -37,13 +38,43 @@ func TokenFrom(ctx context.Context) string {
 	return ""
 }
 
+// projectContextKey is the context key type for the Emergent project ID.
+type projectContextKey struct{}
+
+// projectKey is the context key for the Emergent project ID.
+var projectKey = projectContextKey{}
+
+// WithProjectID returns a context carrying the given Emergent project ID.
+// ClientFactory.ClientFor uses it to target that project, which lets one
+// server work with several projects when standalone API keys are used.
+func WithProjectID(ctx context.Context, projectID string) context.Context {
+	if projectID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, projectKey, projectID)
+}
+
+// ProjectIDFrom extracts the Emergent project ID from the context.
+// Returns empty string if no project ID is present.
+func ProjectIDFrom(ctx context.Context) string {
+	if v, ok := ctx.Value(projectKey).(string); ok {
+		return v
+	}
+	return ""
+}
+
 // Client wraps the Emergent SDK with domain-specific operations for SpecMCP.
 type Client struct {
 	sdk                    *sdk.Client
 	logger                 *slog.Logger
-	maxRetries             int // Maximum retry attempts for failed requests
-	longOutageIntervalMins int // After many failures, switch to this interval in minutes
-	longOutageThreshold    int // Number of consecutive failures before switching to long outage mode
+	maxRetries             int          // Maximum retry attempts for failed requests
+	longOutageIntervalMins int          // After many failures, switch to this interval in minutes
+	longOutageThreshold    int          // Number of consecutive failures before switching to long outage mode
+	listCap                int          // Hard cap on items returned by ListAll* helpers (0 = DefaultListCap)
+	workflowStages         []string     // Configured workflow stage definitions (nil = default workflow)
+	guardPolicy            []string     // Configured guard severity overrides
+	guardRules             []GuardRule  // Configured custom guard rules
+	schema                 *schemaEntry // Shared schema check for this client's project (nil = unchecked)
 }
 
 // ClientFactory creates per-request Emergent clients. It holds the shared
@@ -60,9 +91,14 @@ type ClientFactory struct {
 	adminToken             string // Optional: fallback token for server-side operations in HTTP mode
 	httpClient             *http.Client
 	logger                 *slog.Logger
-	maxRetries             int // Maximum retry attempts for failed requests
-	longOutageIntervalMins int // After many failures, switch to this interval in minutes
-	longOutageThreshold    int // Number of consecutive failures before switching to long outage mode
+	maxRetries             int         // Maximum retry attempts for failed requests
+	longOutageIntervalMins int         // After many failures, switch to this interval in minutes
+	longOutageThreshold    int         // Number of consecutive failures before switching to long outage mode
+	listCap                int         // Hard cap on items returned by ListAll* helpers (0 = DefaultListCap)
+	workflowStages         []string    // Configured workflow stage definitions (nil = default workflow)
+	guardPolicy            []string    // Configured guard severity overrides
+	guardRules             []GuardRule // Configured custom guard rules
+	schemas                schemaCache
 }
 
 // NewClientFactory creates a factory for per-request Emergent clients.
@@ -112,6 +148,7 @@ func NewClientFactory(serverURL string, adminToken string, maxRetries int, longO
 
 // ClientFor creates an Emergent client using the auth token from the context.
 // If no token is in context and adminToken is configured, uses the admin token.
+// The project ID comes from the context (see WithProjectID) or EMERGENT_PROJECT_ID.
 // Each call creates a lightweight SDK client (~28 allocations, zero I/O) that
 // shares the factory's connection pool. Returns an error if no token is available.
 func (f *ClientFactory) ClientFor(ctx context.Context) (*Client, error) {
@@ -126,8 +163,12 @@ func (f *ClientFactory) ClientFor(ctx context.Context) (*Client, error) {
 		}
 	}
 
-	// Check for project ID in environment for standalone API keys
-	projectID := os.Getenv("EMERGENT_PROJECT_ID")
+	// Project ID for standalone API keys: per-request from context (HTTP
+	// X-Project-ID header or stdio config), falling back to the environment.
+	projectID := ProjectIDFrom(ctx)
+	if projectID == "" {
+		projectID = os.Getenv("EMERGENT_PROJECT_ID")
+	}
 
 	sdkClient, err := sdk.New(sdk.Config{
 		ServerURL: f.serverURL,
@@ -148,9 +189,50 @@ func (f *ClientFactory) ClientFor(ctx context.Context) (*Client, error) {
 		maxRetries:             f.maxRetries,
 		longOutageIntervalMins: f.longOutageIntervalMins,
 		longOutageThreshold:    f.longOutageThreshold,
+		listCap:                f.listCap,
+		workflowStages:         f.workflowStages,
+		guardPolicy:            f.guardPolicy,
+		guardRules:             f.guardRules,
+		schema:                 f.schemas.entry(projectID, token),
 	}, nil
 }
 
+// SetWorkflowStages sets the workflow stage definitions (see
+// guards.ParseWorkflow) of clients created by this factory. A Constitution's
+// workflow_stages takes precedence.
+func (f *ClientFactory) SetWorkflowStages(stages []string) {
+	f.workflowStages = stages
+}
+
+// WorkflowStages returns the configured workflow stage definitions, or nil.
+func (c *Client) WorkflowStages() []string {
+	return c.workflowStages
+}
+
+// SetGuardPolicy sets the guard severity overrides (see
+// guards.ParseGuardPolicy) of clients created by this factory. A
+// Constitution's guard_policy takes precedence per guard and app.
+func (f *ClientFactory) SetGuardPolicy(overrides []string) {
+	f.guardPolicy = overrides
+}
+
+// GuardPolicy returns the configured guard severity overrides, or nil.
+func (c *Client) GuardPolicy() []string {
+	return c.guardPolicy
+}
+
+// SetGuardRules sets the custom guard rules (see guards.CompileRule) of
+// clients created by this factory. A Constitution's guard_rules replace
+// rules of the same name.
+func (f *ClientFactory) SetGuardRules(rules []GuardRule) {
+	f.guardRules = rules
+}
+
+// GuardRules returns the configured custom guard rules, or nil.
+func (c *Client) GuardRules() []GuardRule {
+	return c.guardRules
+}
+
 // NewClient creates a Client with a fixed auth token. Use this for CLI tools
 // (like the seed script) that operate with a single known token rather than
 // per-request tokens from HTTP headers.
@@ -342,7 +424,26 @@ func (c *Client) withRetry(ctx context.Context, operation string, fn func() erro
 }
 
 // CreateObject creates a graph object with the given type, key, properties, and labels.
+// It fails with a SchemaError if the project's template pack lacks the type.
+// If the context carries an idempotency key (see WithIdempotencyKey), an object
+// created by an earlier attempt of the same call is returned instead of a duplicate.
 func (c *Client) CreateObject(ctx context.Context, typeName string, key *string, props map[string]any, labels []string) (*graph.GraphObject, error) {
+	if err := c.requireSchema(ctx, typeName); err != nil {
+		return nil, err
+	}
+	if label := idempotencyLabel(ctx, typeName); label != "" {
+		existing, err := c.findByIdempotencyLabel(ctx, typeName, label)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			c.logger.Debug("reusing object from idempotent replay", "type", typeName, "id", existing.ID, "label", label)
+			markReused(ctx, existing)
+			return existing, nil
+		}
+		labels = append(append([]string(nil), labels...), label)
+	}
+
 	var obj *graph.GraphObject
 	err := c.withRetry(ctx, fmt.Sprintf("create %s object", typeName), func() error {
 		var createErr error
@@ -351,12 +452,16 @@ func (c *Client) CreateObject(ctx context.Context, typeName string, key *string,
 			Key:        key,
 			Properties: props,
 			Labels:     labels,
+			BranchID:   branchRef(ctx),
 		})
 		return createErr
 	})
 	if err != nil {
 		return nil, err
 	}
+	if uow := unitOfWorkFrom(ctx); uow != nil {
+		uow.record("object", typeName, obj.ID)
+	}
 	c.logger.Debug("created object", "type", typeName, "id", obj.ID, "key", key)
 	return obj, nil
 }
@@ -392,8 +497,39 @@ func (c *Client) GetObjects(ctx context.Context, ids []string) ([]*graph.GraphOb
 	return objs, nil
 }
 
+// GetObjectHistory returns every version of a graph object, oldest first.
+// id may be any version's ID or the canonical ID.
+func (c *Client) GetObjectHistory(ctx context.Context, id string) ([]*graph.GraphObject, error) {
+	var versions []*graph.GraphObject
+	err := c.withRetry(ctx, fmt.Sprintf("get history of %s", id), func() error {
+		resp, histErr := c.sdk.Graph.GetObjectHistory(ctx, id)
+		if histErr != nil {
+			return histErr
+		}
+		versions = resp.Versions
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	return versions, nil
+}
+
 // UpdateObject updates a graph object's properties and/or labels.
+// It is last-writer-wins; use UpdateObjectIfVersion to guard against
+// concurrent updates. If the context carries a branch (see WithBranch) and
+// the object lives elsewhere, the update is applied to a copy on the branch.
 func (c *Client) UpdateObject(ctx context.Context, id string, props map[string]any, labels []string) (*graph.GraphObject, error) {
+	if branch := BranchFrom(ctx); branch != "" {
+		current, err := c.GetObject(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if ObjectBranch(current) != branch {
+			return c.forkToBranch(ctx, current, props, labels)
+		}
+	}
 	var obj *graph.GraphObject
 	err := c.withRetry(ctx, fmt.Sprintf("update object %s", id), func() error {
 		req := &graph.UpdateObjectRequest{
@@ -414,6 +550,32 @@ func (c *Client) UpdateObject(ctx context.Context, id string, props map[string]a
 	return obj, nil
 }
 
+// UpdateObjectIfVersion updates a graph object only if its current version
+// equals expectedVersion, returning a *VersionConflictError otherwise.
+// An expectedVersion of 0 skips the check and behaves like UpdateObject.
+//
+// The SDK has no server-side precondition, so the check is a read followed
+// by a write. GetObject resolves any version or canonical ID to the current
+// head, which keeps the race window to a single round trip.
+func (c *Client) UpdateObjectIfVersion(ctx context.Context, id string, expectedVersion int, props map[string]any, labels []string) (*graph.GraphObject, error) {
+	if expectedVersion > 0 {
+		current, err := c.GetObject(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if current.Version != expectedVersion {
+			return nil, &VersionConflictError{
+				ID:              current.ID,
+				CanonicalID:     current.CanonicalID,
+				ExpectedVersion: expectedVersion,
+				ActualVersion:   current.Version,
+			}
+		}
+		id = current.ID
+	}
+	return c.UpdateObject(ctx, id, props, labels)
+}
+
 // DeleteObject soft-deletes a graph object.
 func (c *Client) DeleteObject(ctx context.Context, id string) error {
 	if err := c.sdk.Graph.DeleteObject(ctx, id); err != nil {
@@ -422,11 +584,12 @@ func (c *Client) DeleteObject(ctx context.Context, id string) error {
 	return nil
 }
 
-// ListObjects lists objects with filtering options.
+// ListObjects lists objects with filtering options. It returns a single page;
+// use ListAllObjects or AllObjects when the complete listing is needed.
 func (c *Client) ListObjects(ctx context.Context, opts *graph.ListObjectsOptions) ([]*graph.GraphObject, error) {
 	var items []*graph.GraphObject
 	err := c.withRetry(ctx, "list objects", func() error {
-		resp, listErr := c.sdk.Graph.ListObjects(ctx, opts)
+		resp, listErr := c.sdk.Graph.ListObjects(ctx, withObjectsBranch(ctx, opts))
 		if listErr != nil {
 			return listErr
 		}
@@ -443,7 +606,8 @@ func (c *Client) ListObjects(ctx context.Context, opts *graph.ListObjectsOptions
 // Uses the native SDK CountObjects endpoint (server-side count, no data transfer).
 func (c *Client) CountObjects(ctx context.Context, typeName string) (int, error) {
 	count, err := c.sdk.Graph.CountObjects(ctx, &graph.CountObjectsOptions{
-		Type: typeName,
+		Type:     typeName,
+		BranchID: BranchFrom(ctx),
 	})
 	if err != nil {
 		return 0, fmt.Errorf("counting objects: %w", err)
@@ -459,6 +623,7 @@ func (c *Client) UpsertObject(ctx context.Context, typeName string, key *string,
 		Key:        key,
 		Properties: props,
 		Labels:     labels,
+		BranchID:   branchRef(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("upserting %s object: %w", typeName, err)
@@ -471,15 +636,22 @@ func (c *Client) UpsertObject(ctx context.Context, typeName string, key *string,
 // Returns nil, nil if not found.
 // When multiple objects share the same type+key (duplicates from before dedup was added),
 // this returns the one with the smallest ID (string sort) for determinism.
+// On a branch (see WithBranch), the branch's copy wins over the main branch's.
 func (c *Client) FindByTypeAndKey(ctx context.Context, typeName, key string) (*graph.GraphObject, error) {
-	items, err := c.ListObjects(ctx, &graph.ListObjectsOptions{
+	opts := &graph.ListObjectsOptions{
 		Type:  typeName,
 		Key:   key,
 		Limit: 50, // Fetch enough to find all duplicates
-	})
+	}
+	items, err := c.ListObjects(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
+	if len(items) == 0 && BranchFrom(ctx) != "" {
+		if items, err = c.ListObjects(WithoutBranch(ctx), opts); err != nil {
+			return nil, err
+		}
+	}
 	if len(items) == 0 {
 		return nil, nil
 	}
@@ -497,7 +669,24 @@ func (c *Client) FindByTypeAndKey(ctx context.Context, typeName, key string) (*g
 }
 
 // CreateRelationship creates a relationship between two objects.
+// It fails with a SchemaError if the project's template pack lacks the type.
+// On an idempotent replay (see WithIdempotencyKey) where an endpoint is an
+// object from the earlier attempt, an existing relationship is returned
+// instead of a duplicate.
 func (c *Client) CreateRelationship(ctx context.Context, relType, srcID, dstID string, props map[string]any) (*graph.GraphRelationship, error) {
+	if err := c.requireSchema(ctx, relType); err != nil {
+		return nil, err
+	}
+	if reusedAny(ctx, srcID, dstID) {
+		existing, err := c.FindRelationshipByEdges(ctx, relType, srcID, NewIDSet(dstID, ""))
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			c.logger.Debug("reusing relationship from idempotent replay", "type", relType, "id", existing.ID)
+			return existing, nil
+		}
+	}
 	var rel *graph.GraphRelationship
 	err := c.withRetry(ctx, fmt.Sprintf("create %s relationship", relType), func() error {
 		var createErr error
@@ -506,21 +695,26 @@ func (c *Client) CreateRelationship(ctx context.Context, relType, srcID, dstID s
 			SrcID:      srcID,
 			DstID:      dstID,
 			Properties: props,
+			BranchID:   branchRef(ctx),
 		})
 		return createErr
 	})
 	if err != nil {
 		return nil, err
 	}
+	if uow := unitOfWorkFrom(ctx); uow != nil {
+		uow.record("relationship", relType, rel.ID)
+	}
 	c.logger.Debug("created relationship", "type", relType, "src", srcID, "dst", dstID, "id", rel.ID)
 	return rel, nil
 }
 
-// ListRelationships lists relationships with filtering options.
+// ListRelationships lists relationships with filtering options. It returns a
+// single page; use ListAllRelationships or AllRelationships for all of them.
 func (c *Client) ListRelationships(ctx context.Context, opts *graph.ListRelationshipsOptions) ([]*graph.GraphRelationship, error) {
 	var items []*graph.GraphRelationship
 	err := c.withRetry(ctx, "list relationships", func() error {
-		resp, listErr := c.sdk.Graph.ListRelationships(ctx, opts)
+		resp, listErr := c.sdk.Graph.ListRelationships(ctx, withRelationshipsBranch(ctx, opts))
 		if listErr != nil {
 			return listErr
 		}
@@ -560,6 +754,11 @@ func (c *Client) ExpandGraph(ctx context.Context, req *graph.GraphExpandRequest)
 
 // FTSSearch performs a full-text search across graph objects.
 func (c *Client) FTSSearch(ctx context.Context, opts *graph.FTSSearchOptions) (*graph.SearchResponse, error) {
+	if opts != nil && opts.BranchID == "" {
+		scoped := *opts
+		scoped.BranchID = BranchFrom(ctx)
+		opts = &scoped
+	}
 	var resp *graph.SearchResponse
 	err := c.withRetry(ctx, "FTS search", func() error {
 		var searchErr error
@@ -0,0 +1,176 @@
+package emergent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+// --- Comment ---
+
+// CreateComment creates a Comment on an entity and links it to the entity
+// (comments_on), the comment it replies to (reply_to), and its author's
+// Agent or Actor (authored_by). parentID and authorID may be empty.
+func (c *Client) CreateComment(ctx context.Context, targetID, parentID, authorID string, cm *Comment) (*Comment, error) {
+	props, err := toProps(cm)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := c.CreateObject(ctx, TypeComment, nil, props, cm.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("creating Comment: %w", err)
+	}
+	if _, err := c.CreateRelationship(ctx, RelCommentsOn, obj.ID, targetID, nil); err != nil {
+		return nil, fmt.Errorf("linking Comment to entity: %w", err)
+	}
+	if parentID != "" {
+		if _, err := c.CreateRelationship(ctx, RelReplyTo, obj.ID, parentID, nil); err != nil {
+			return nil, fmt.Errorf("linking Comment to parent: %w", err)
+		}
+	}
+	if authorID != "" {
+		if _, err := c.CreateRelationship(ctx, RelAuthoredBy, obj.ID, authorID, nil); err != nil {
+			return nil, fmt.Errorf("linking Comment to author: %w", err)
+		}
+	}
+	return commentFromObject(obj)
+}
+
+// GetComment fetches a Comment by ID.
+func (c *Client) GetComment(ctx context.Context, id string) (*Comment, error) {
+	obj, err := c.GetObject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if obj.Type != TypeComment {
+		return nil, fmt.Errorf("entity %s is a %s, not a Comment", id, obj.Type)
+	}
+	return commentFromObject(obj)
+}
+
+// UpdateComment sets properties on a Comment and returns the new version.
+func (c *Client) UpdateComment(ctx context.Context, id string, props map[string]any) (*Comment, error) {
+	obj, err := c.UpdateObject(ctx, id, props, nil)
+	if err != nil {
+		return nil, err
+	}
+	return commentFromObject(obj)
+}
+
+// CommentTarget returns the ID of the entity a Comment is on.
+func (c *Client) CommentTarget(ctx context.Context, commentID string) (string, error) {
+	edges, err := c.GetObjectEdges(ctx, commentID, &graph.GetObjectEdgesOptions{
+		Types:     []string{RelCommentsOn},
+		Direction: "outgoing",
+	})
+	if err != nil {
+		return "", fmt.Errorf("getting entity of comment %s: %w", commentID, err)
+	}
+	if len(edges.Outgoing) == 0 {
+		return "", fmt.Errorf("comment %s is not on any entity", commentID)
+	}
+	return edges.Outgoing[0].DstID, nil
+}
+
+// ListComments returns the Comments on an entity, replies included, oldest
+// first.
+func (c *Client) ListComments(ctx context.Context, targetID string) ([]*Comment, error) {
+	edges, err := c.GetObjectEdges(ctx, targetID, &graph.GetObjectEdgesOptions{
+		Types:     []string{RelCommentsOn},
+		Direction: "incoming",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting comments on %s: %w", targetID, err)
+	}
+	ids := make([]string, 0, len(edges.Incoming))
+	for _, rel := range edges.Incoming {
+		ids = append(ids, rel.SrcID)
+	}
+	objs, err := c.GetObjects(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("getting comments on %s: %w", targetID, err)
+	}
+	sort.SliceStable(objs, func(i, j int) bool { return objs[i].CreatedAt.Before(objs[j].CreatedAt) })
+
+	seen := make(map[string]bool, len(objs))
+	comments := make([]*Comment, 0, len(objs))
+	for _, obj := range objs {
+		if obj.Type != TypeComment || seen[obj.CanonicalID] {
+			continue
+		}
+		seen[obj.CanonicalID] = true
+		cm, err := commentFromObject(obj)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, cm)
+	}
+	return comments, nil
+}
+
+func commentFromObject(obj *graph.GraphObject) (*Comment, error) {
+	cm, err := fromProps[Comment](obj)
+	if err != nil {
+		return nil, err
+	}
+	cm.ID = obj.ID
+	cm.CanonicalID = obj.CanonicalID
+	cm.BranchID = ObjectBranch(obj)
+	return cm, nil
+}
+
+// CommentThread is a thread's first comment and its replies, oldest first.
+type CommentThread struct {
+	*Comment
+	Replies []*Comment `json:"replies,omitempty"`
+}
+
+// ThreadRoot returns the first comment of cm's thread among comments. A
+// reply whose parent is missing starts its own thread.
+func ThreadRoot(comments []*Comment, cm *Comment) *Comment {
+	byCanonical := make(map[string]*Comment, len(comments))
+	for _, other := range comments {
+		byCanonical[other.CanonicalID] = other
+	}
+	root := cm
+	for hops := 0; root.ReplyTo != "" && hops < len(comments); hops++ {
+		parent, ok := byCanonical[root.ReplyTo]
+		if !ok {
+			break
+		}
+		root = parent
+	}
+	return root
+}
+
+// BuildThreads groups comments (oldest first) into threads.
+func BuildThreads(comments []*Comment) []*CommentThread {
+	var threads []*CommentThread
+	byRoot := make(map[string]*CommentThread)
+	for _, cm := range comments {
+		root := ThreadRoot(comments, cm)
+		thread, ok := byRoot[root.CanonicalID]
+		if !ok {
+			thread = &CommentThread{Comment: root}
+			byRoot[root.CanonicalID] = thread
+			threads = append(threads, thread)
+		}
+		if cm != root {
+			thread.Replies = append(thread.Replies, cm)
+		}
+	}
+	return threads
+}
+
+// OpenThreads counts the unresolved threads among comments.
+func OpenThreads(comments []*Comment) int {
+	open := 0
+	for _, thread := range BuildThreads(comments) {
+		if !thread.Resolved {
+			open++
+		}
+	}
+	return open
+}
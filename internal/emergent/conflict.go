@@ -0,0 +1,53 @@
+package emergent
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrVersionConflict is matched (via errors.Is) by every *VersionConflictError.
+var ErrVersionConflict = errors.New("version conflict")
+
+// MaxConflictRetries is how many times RetryOnConflict re-reads an entity and
+// retries an update before giving up.
+const MaxConflictRetries = 3
+
+// VersionConflictError is returned when an update's expected version does not
+// match the entity's current version, i.e. someone else updated it first.
+type VersionConflictError struct {
+	ID              string `json:"id"`
+	CanonicalID     string `json:"canonical_id,omitempty"`
+	ExpectedVersion int    `json:"expected_version"`
+	ActualVersion   int    `json:"actual_version"`
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("version conflict on %s: expected version %d, current version is %d; re-read the entity and retry with expected_version=%d",
+		e.ID, e.ExpectedVersion, e.ActualVersion, e.ActualVersion)
+}
+
+// Is makes errors.Is(err, ErrVersionConflict) match.
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+// RetryOnConflict runs update at expectedVersion. If it fails with a version
+// conflict, reload re-reads the entity, re-validates it against the fresh
+// state, and returns the version to retry at, or an error to stop (for
+// example because another writer already made the same change).
+//
+// Pass a nil reload when the caller pinned the version explicitly: the
+// conflict is then returned as-is so it can be reported.
+func RetryOnConflict(expectedVersion int, update func(version int) error, reload func() (int, error)) error {
+	version := expectedVersion
+	for attempt := 0; ; attempt++ {
+		err := update(version)
+		if err == nil || !errors.Is(err, ErrVersionConflict) || reload == nil || attempt >= MaxConflictRetries {
+			return err
+		}
+		version, err = reload()
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package emergent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	sdkerrors "github.com/emergent-company/emergent/apps/server-go/pkg/sdk/errors"
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+// ErrDependencyCycle is returned when a depends_on_change relationship would
+// make a Change depend on itself, directly or through other Changes.
+var ErrDependencyCycle = errors.New("change dependency cycle")
+
+// ErrChangeGraphTruncated is returned when adding a dependency to a
+// ChangeGraph that could not be read in full, so a cycle may go unseen.
+var ErrChangeGraphTruncated = errors.New("change graph truncated")
+
+// ChangeGraph is the depends_on_change graph over every Change, keyed by
+// canonical ID so relationships written against any version line up.
+type ChangeGraph struct {
+	Changes   map[string]*Change  // canonical ID → Change
+	DependsOn map[string][]string // canonical ID → canonical IDs it depends on
+
+	// Dangling lists the depends_on_change relationships left out because
+	// an endpoint no longer exists, as "<relationship ID>: <src> → <dst>".
+	Dangling []string
+
+	// Truncated is set when the Changes or relationships hit the listing
+	// cap, so the graph is incomplete.
+	Truncated bool
+}
+
+// LoadChangeGraph reads every Change and depends_on_change relationship.
+// Relationships to a deleted object are skipped and reported in Dangling.
+func (c *Client) LoadChangeGraph(ctx context.Context) (*ChangeGraph, error) {
+	changes, changesTruncated, err := c.ListChanges(ctx, "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing changes: %w", err)
+	}
+	g := &ChangeGraph{
+		Changes:   make(map[string]*Change, len(changes)),
+		DependsOn: make(map[string][]string),
+		Truncated: changesTruncated,
+	}
+	canonical := make(map[string]string, 2*len(changes))
+	for _, ch := range changes {
+		g.Changes[ch.CanonicalID] = ch
+		canonical[ch.ID] = ch.CanonicalID
+		canonical[ch.CanonicalID] = ch.CanonicalID
+	}
+
+	rels, relsTruncated, err := c.ListAllRelationships(ctx, &graph.ListRelationshipsOptions{Type: RelDependsOnChange}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing change dependencies: %w", err)
+	}
+	g.Truncated = g.Truncated || relsTruncated
+	// Relationships may point at an older version; GetObject resolves it
+	// to the head, whose canonical ID is in the index. An object that no
+	// longer exists resolves to "".
+	resolve := func(id string) (string, error) {
+		if cid, ok := canonical[id]; ok {
+			return cid, nil
+		}
+		obj, err := c.GetObject(ctx, id)
+		if sdkerrors.IsNotFound(err) {
+			canonical[id] = ""
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("resolving change %s: %w", id, err)
+		}
+		canonical[id] = obj.CanonicalID
+		return obj.CanonicalID, nil
+	}
+	for _, rel := range rels {
+		src, err := resolve(rel.SrcID)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := resolve(rel.DstID)
+		if err != nil {
+			return nil, err
+		}
+		if src == "" || dst == "" {
+			c.logger.Warn("skipping dangling change dependency", "relationship", rel.ID, "src", rel.SrcID, "dst", rel.DstID)
+			g.Dangling = append(g.Dangling, fmt.Sprintf("%s: %s → %s", rel.ID, rel.SrcID, rel.DstID))
+			continue
+		}
+		g.DependsOn[src] = append(g.DependsOn[src], dst)
+	}
+	return g, nil
+}
+
+// Path returns a chain of canonical IDs from → … → to along depends_on_change
+// relationships, or nil if to is not reachable from from.
+func (g *ChangeGraph) Path(from, to string) []string {
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == to {
+			var path []string
+			for ; id != ""; id = prev[id] {
+				path = append([]string{id}, path...)
+			}
+			return path
+		}
+		for _, dep := range g.DependsOn[id] {
+			if _, seen := prev[dep]; !seen {
+				prev[dep] = id
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// Name returns the name of the Change with the given canonical ID, falling
+// back to the ID for Changes outside the graph.
+func (g *ChangeGraph) Name(id string) string {
+	if ch, ok := g.Changes[id]; ok {
+		return ch.Name
+	}
+	return id
+}
+
+// Names maps Name over ids.
+func (g *ChangeGraph) Names(ids []string) []string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = g.Name(id)
+	}
+	return names
+}
+
+// Order returns every Change's canonical ID with each Change after the
+// Changes it depends on. Independent Changes are ordered by name. Changes
+// caught in a cycle, which AddChangeDependency prevents, come last.
+func (g *ChangeGraph) Order() []string {
+	ids := make([]string, 0, len(g.Changes))
+	for id := range g.Changes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return g.Name(ids[i]) < g.Name(ids[j]) })
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(ids))
+	order := make([]string, 0, len(ids))
+	var cyclic []string
+	var visit func(id string) bool
+	visit = func(id string) bool {
+		switch state[id] {
+		case visiting:
+			return false
+		case done:
+			return true
+		}
+		state[id] = visiting
+		deps := append([]string(nil), g.DependsOn[id]...)
+		sort.Slice(deps, func(i, j int) bool { return g.Name(deps[i]) < g.Name(deps[j]) })
+		ok := true
+		for _, dep := range deps {
+			if _, known := g.Changes[dep]; known && !visit(dep) {
+				ok = false
+			}
+		}
+		state[id] = done
+		if ok {
+			order = append(order, id)
+		} else {
+			cyclic = append(cyclic, id)
+		}
+		return ok
+	}
+	for _, id := range ids {
+		visit(id)
+	}
+	return append(order, cyclic...)
+}
+
+// AddChangeDependency records that change depends on dependsOn. It is a no-op
+// if the dependency already exists, and fails with ErrDependencyCycle if
+// dependsOn already depends on change, or with ErrChangeGraphTruncated if g
+// is incomplete. g is updated to match.
+func (c *Client) AddChangeDependency(ctx context.Context, g *ChangeGraph, change, dependsOn *Change) error {
+	if change.CanonicalID == dependsOn.CanonicalID {
+		return fmt.Errorf("%w: change %q cannot depend on itself", ErrDependencyCycle, change.Name)
+	}
+	for _, dep := range g.DependsOn[change.CanonicalID] {
+		if dep == dependsOn.CanonicalID {
+			return nil
+		}
+	}
+	if g.Truncated {
+		return fmt.Errorf("%w: too many changes or dependencies to check %s → %s for cycles", ErrChangeGraphTruncated, change.Name, dependsOn.Name)
+	}
+	if path := g.Path(dependsOn.CanonicalID, change.CanonicalID); path != nil {
+		return fmt.Errorf("%w: %s already depends on %s (%s)", ErrDependencyCycle,
+			dependsOn.Name, change.Name, strings.Join(g.Names(path), " → "))
+	}
+	if _, err := c.CreateRelationship(ctx, RelDependsOnChange, change.ID, dependsOn.ID, nil); err != nil {
+		return fmt.Errorf("linking %s to %s: %w", change.Name, dependsOn.Name, err)
+	}
+	g.DependsOn[change.CanonicalID] = append(g.DependsOn[change.CanonicalID], dependsOn.CanonicalID)
+	return nil
+}
+
+// RemoveChangeDependency deletes every depends_on_change relationship from
+// change to dependsOn and reports how many were removed.
+func (c *Client) RemoveChangeDependency(ctx context.Context, g *ChangeGraph, change, dependsOn *Change) (int, error) {
+	edges, err := c.GetObjectEdges(ctx, change.ID, &graph.GetObjectEdgesOptions{
+		Type:      RelDependsOnChange,
+		Direction: "outgoing",
+	})
+	if err != nil {
+		return 0, fmt.Errorf("getting dependencies of %s: %w", change.Name, err)
+	}
+	targets := NewIDSet(dependsOn.ID, dependsOn.CanonicalID)
+	removed := 0
+	for _, e := range edges.Outgoing {
+		if !targets[e.DstID] {
+			// The relationship may point at an older version.
+			dst, err := c.GetObject(ctx, e.DstID)
+			if err != nil || dst.CanonicalID != dependsOn.CanonicalID {
+				continue
+			}
+		}
+		if err := c.DeleteRelationship(ctx, e.ID); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	deps := g.DependsOn[change.CanonicalID][:0]
+	for _, dep := range g.DependsOn[change.CanonicalID] {
+		if dep != dependsOn.CanonicalID {
+			deps = append(deps, dep)
+		}
+	}
+	g.DependsOn[change.CanonicalID] = deps
+	return removed, nil
+}
+
+// ResolveChange finds a Change by ID or name.
+func (c *Client) ResolveChange(ctx context.Context, ref string) (*Change, error) {
+	if ch, err := c.FindChange(ctx, ref); err != nil || ch != nil {
+		return ch, err
+	}
+	obj, err := c.GetObject(ctx, ref)
+	if err != nil || obj.Type != TypeChange {
+		return nil, fmt.Errorf("change %q not found", ref)
+	}
+	ch, err := fromProps[Change](obj)
+	if err != nil {
+		return nil, err
+	}
+	ch.ID = obj.ID
+	ch.CanonicalID = obj.CanonicalID
+	ch.Version = obj.Version
+	return ch, nil
+}
@@ -73,12 +73,18 @@ func (c *Client) GetChange(ctx context.Context, id string) (*Change, error) {
 	if err != nil {
 		return nil, err
 	}
+	return ChangeFromObject(obj)
+}
+
+// ChangeFromObject converts a Change graph object.
+func ChangeFromObject(obj *graph.GraphObject) (*Change, error) {
 	result, err := fromProps[Change](obj)
 	if err != nil {
 		return nil, err
 	}
 	result.ID = obj.ID
 	result.CanonicalID = obj.CanonicalID
+	result.Version = obj.Version
 	return result, nil
 }
 
@@ -97,11 +103,14 @@ func (c *Client) FindChange(ctx context.Context, name string) (*Change, error) {
 	}
 	result.ID = obj.ID
 	result.CanonicalID = obj.CanonicalID
+	result.Version = obj.Version
 	return result, nil
 }
 
 // ListChanges lists all Change entities, optionally filtered by status.
-func (c *Client) ListChanges(ctx context.Context, status string) ([]*Change, error) {
+// It follows pagination up to capItems changes (0 uses the client's list cap);
+// truncated reports whether more changes exist beyond the cap.
+func (c *Client) ListChanges(ctx context.Context, status string, capItems int) (changes []*Change, truncated bool, err error) {
 	opts := &graph.ListObjectsOptions{
 		Type:  TypeChange,
 		Limit: 100,
@@ -111,21 +120,22 @@ func (c *Client) ListChanges(ctx context.Context, status string) ([]*Change, err
 			{Path: "status", Op: "eq", Value: status},
 		}
 	}
-	objs, err := c.ListObjects(ctx, opts)
+	objs, truncated, err := c.ListAllObjects(ctx, opts, capItems)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
-	changes := make([]*Change, 0, len(objs))
+	changes = make([]*Change, 0, len(objs))
 	for _, obj := range objs {
 		ch, err := fromProps[Change](obj)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 		ch.ID = obj.ID
 		ch.CanonicalID = obj.CanonicalID
+		ch.Version = obj.Version
 		changes = append(changes, ch)
 	}
-	return changes, nil
+	return changes, truncated, nil
 }
 
 // --- Proposal ---
@@ -294,22 +304,31 @@ func (c *Client) GetTask(ctx context.Context, id string) (*Task, error) {
 	if err != nil {
 		return nil, err
 	}
+	return TaskFromObject(obj)
+}
+
+// TaskFromObject converts a Task graph object.
+func TaskFromObject(obj *graph.GraphObject) (*Task, error) {
 	result, err := fromProps[Task](obj)
 	if err != nil {
 		return nil, err
 	}
 	result.ID = obj.ID
 	result.CanonicalID = obj.CanonicalID
+	result.Version = obj.Version
+	result.BranchID = ObjectBranch(obj)
 	return result, nil
 }
 
 // UpdateTaskStatus updates a task's status and timestamps.
-func (c *Client) UpdateTaskStatus(ctx context.Context, taskID, status string, props map[string]any) (*Task, error) {
+// If expectedVersion is non-zero, the update fails with a *VersionConflictError
+// when the task has been updated since that version was read.
+func (c *Client) UpdateTaskStatus(ctx context.Context, taskID, status string, props map[string]any, expectedVersion int) (*Task, error) {
 	if props == nil {
 		props = make(map[string]any)
 	}
 	props["status"] = status
-	obj, err := c.UpdateObject(ctx, taskID, props, nil)
+	obj, err := c.UpdateObjectIfVersion(ctx, taskID, expectedVersion, props, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -319,16 +338,17 @@ func (c *Client) UpdateTaskStatus(ctx context.Context, taskID, status string, pr
 	}
 	result.ID = obj.ID
 	result.CanonicalID = obj.CanonicalID
+	result.Version = obj.Version
 	return result, nil
 }
 
 // ListTasks lists tasks for a change by expanding the has_task relationship.
 func (c *Client) ListTasks(ctx context.Context, changeID string) ([]*Task, error) {
-	rels, err := c.ListRelationships(ctx, &graph.ListRelationshipsOptions{
+	rels, _, err := c.ListAllRelationships(ctx, &graph.ListRelationshipsOptions{
 		Type:  RelHasTask,
 		SrcID: changeID,
 		Limit: 200,
-	})
+	}, 0)
 	if err != nil {
 		return nil, err
 	}
@@ -509,19 +529,26 @@ func (c *Client) HasRelationship(ctx context.Context, relType, srcID, dstID stri
 // if any edge's DstID matches any ID in dstIDs (an IDSet covering both
 // version-specific and canonical IDs of the destination object).
 func (c *Client) HasRelationshipByEdges(ctx context.Context, relType string, srcID string, dstIDs IDSet) (bool, error) {
+	rel, err := c.FindRelationshipByEdges(ctx, relType, srcID, dstIDs)
+	return rel != nil, err
+}
+
+// FindRelationshipByEdges is HasRelationshipByEdges returning the matching
+// relationship, or nil if there is none.
+func (c *Client) FindRelationshipByEdges(ctx context.Context, relType string, srcID string, dstIDs IDSet) (*graph.GraphRelationship, error) {
 	edges, err := c.GetObjectEdges(ctx, srcID, &graph.GetObjectEdgesOptions{
 		Types:     []string{relType},
 		Direction: "outgoing",
 	})
 	if err != nil {
-		return false, fmt.Errorf("getting edges for %s: %w", srcID, err)
+		return nil, fmt.Errorf("getting edges for %s: %w", srcID, err)
 	}
 	for _, rel := range edges.Outgoing {
 		if dstIDs[rel.DstID] {
-			return true, nil
+			return rel, nil
 		}
 	}
-	return false, nil
+	return nil, nil
 }
 
 // --- Agent ---
@@ -530,9 +557,9 @@ func (c *Client) HasRelationshipByEdges(ctx context.Context, relType string, src
 // This ensures system agents (like janitor) exist in the graph.
 func (c *Client) GetOrCreateAgent(ctx context.Context, agent *Agent) (*Agent, error) {
 	// Try to find existing agent by listing all and matching name.
-	existing, err := c.ListObjects(ctx, &graph.ListObjectsOptions{
+	existing, _, err := c.ListAllObjects(ctx, &graph.ListObjectsOptions{
 		Type: TypeAgent,
-	})
+	}, 0)
 	if err != nil {
 		return nil, err
 	}
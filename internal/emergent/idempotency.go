@@ -0,0 +1,114 @@
+package emergent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+// IdempotencyLabelPrefix marks objects created under an idempotency key.
+// The full label is "idempotency:<key-hash>:<type>:<n>", where n counts the
+// objects of that type created so far in the same call. A retried call creates
+// objects in the same order, so each CreateObject maps to the same label and
+// finds the object written by the original attempt. Relationships carry no
+// label; CreateRelationship instead looks for an existing edge when either
+// endpoint was reused this way.
+const IdempotencyLabelPrefix = "idempotency:"
+
+// idempotencyContextKey is the context key type for the idempotency scope of
+// a tool call. It is distinct from contextKey so the two values never collide.
+type idempotencyContextKey struct{}
+
+// idempotencyKey is the context key for the idempotency scope of a tool call.
+var idempotencyKey = idempotencyContextKey{}
+
+// idempotencyScope tracks the objects created under one idempotency key.
+type idempotencyScope struct {
+	hash   string
+	mu     sync.Mutex
+	counts map[string]int  // type name → objects created so far
+	reused map[string]bool // IDs of objects found from an earlier attempt
+}
+
+// next returns the label for the next object of the given type.
+func (s *idempotencyScope) next(typeName string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counts[typeName]++
+	return fmt.Sprintf("%s%s:%s:%d", IdempotencyLabelPrefix, s.hash, typeName, s.counts[typeName])
+}
+
+// WithIdempotencyKey returns a context carrying the given idempotency key.
+// Client.CreateObject uses it to return objects created by an earlier attempt
+// of the same call instead of creating duplicates.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	if key == "" {
+		return ctx
+	}
+	sum := sha256.Sum256([]byte(key))
+	return context.WithValue(ctx, idempotencyKey, &idempotencyScope{
+		hash:   hex.EncodeToString(sum[:8]),
+		counts: make(map[string]int),
+		reused: make(map[string]bool),
+	})
+}
+
+// idempotencyLabel returns the label for the next object of typeName created
+// under the context's idempotency key, or "" if there is none.
+func idempotencyLabel(ctx context.Context, typeName string) string {
+	scope, ok := ctx.Value(idempotencyKey).(*idempotencyScope)
+	if !ok {
+		return ""
+	}
+	return scope.next(typeName)
+}
+
+// markReused records that obj was written by an earlier attempt of the call
+// in ctx.
+func markReused(ctx context.Context, obj *graph.GraphObject) {
+	scope, ok := ctx.Value(idempotencyKey).(*idempotencyScope)
+	if !ok {
+		return
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	scope.reused[obj.ID] = true
+	scope.reused[obj.CanonicalID] = true
+}
+
+// reusedAny reports whether any of ids is an object markReused recorded.
+func reusedAny(ctx context.Context, ids ...string) bool {
+	scope, ok := ctx.Value(idempotencyKey).(*idempotencyScope)
+	if !ok {
+		return false
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	for _, id := range ids {
+		if scope.reused[id] {
+			return true
+		}
+	}
+	return false
+}
+
+// findByIdempotencyLabel returns the object of typeName carrying label,
+// or nil if the original attempt never created it.
+func (c *Client) findByIdempotencyLabel(ctx context.Context, typeName, label string) (*graph.GraphObject, error) {
+	items, err := c.ListObjects(ctx, &graph.ListObjectsOptions{
+		Type:  typeName,
+		Label: label,
+		Limit: 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return items[0], nil
+}
@@ -0,0 +1,174 @@
+package emergent
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+// DefaultListCap is the default hard cap on items returned by the ListAll*
+// helpers. It keeps a runaway listing from exhausting memory on huge projects.
+const DefaultListCap = 5000
+
+// listPageSize is the page size used when the caller's options set no Limit.
+const listPageSize = 100
+
+// ErrListTruncated is yielded as the final element of an All* iterator when
+// the cap was reached while more pages remained.
+var ErrListTruncated = errors.New("listing truncated at cap")
+
+// SetListCap sets the hard cap on items returned by the ListAll* helpers of
+// clients created by this factory. Zero or negative uses DefaultListCap.
+func (f *ClientFactory) SetListCap(n int) {
+	f.listCap = n
+}
+
+// effectiveCap resolves a per-call cap against the client's configured cap.
+func (c *Client) effectiveCap(capItems int) int {
+	if capItems > 0 {
+		return capItems
+	}
+	if c.listCap > 0 {
+		return c.listCap
+	}
+	return DefaultListCap
+}
+
+// AllObjects returns an iterator over every object matching opts, following
+// pagination cursors. opts.Limit, if set, is used as the page size. At most
+// capItems objects are yielded (0 uses the client's configured cap); if more
+// remain, the final element is (nil, ErrListTruncated). Any other error ends
+// the iteration after being yielded.
+//
+//	for obj, err := range client.AllObjects(ctx, opts, 0) { ... }
+func (c *Client) AllObjects(ctx context.Context, opts *graph.ListObjectsOptions, capItems int) iter.Seq2[*graph.GraphObject, error] {
+	return func(yield func(*graph.GraphObject, error) bool) {
+		page := graph.ListObjectsOptions{}
+		if opts != nil {
+			page = *opts
+		}
+		if page.BranchID == "" {
+			page.BranchID = BranchFrom(ctx)
+		}
+		if page.Limit <= 0 {
+			page.Limit = listPageSize
+		}
+		limit := c.effectiveCap(capItems)
+
+		count := 0
+		for {
+			var resp *graph.SearchObjectsResponse
+			err := c.withRetry(ctx, "list objects page", func() error {
+				var listErr error
+				resp, listErr = c.sdk.Graph.ListObjects(ctx, &page)
+				return listErr
+			})
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, item := range resp.Items {
+				if count >= limit {
+					yield(nil, ErrListTruncated)
+					return
+				}
+				if !yield(item, nil) {
+					return
+				}
+				count++
+			}
+			if resp.NextCursor == nil || *resp.NextCursor == "" || len(resp.Items) == 0 {
+				return
+			}
+			if count >= limit {
+				yield(nil, ErrListTruncated)
+				return
+			}
+			page.Cursor = *resp.NextCursor
+		}
+	}
+}
+
+// ListAllObjects collects AllObjects into a slice. truncated reports whether
+// the cap was hit with more objects remaining.
+func (c *Client) ListAllObjects(ctx context.Context, opts *graph.ListObjectsOptions, capItems int) (items []*graph.GraphObject, truncated bool, err error) {
+	for obj, err := range c.AllObjects(ctx, opts, capItems) {
+		if errors.Is(err, ErrListTruncated) {
+			c.logger.Warn("object listing truncated at cap", "cap", c.effectiveCap(capItems))
+			return items, true, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, obj)
+	}
+	return items, false, nil
+}
+
+// AllRelationships returns an iterator over every relationship matching opts,
+// following pagination cursors, with the same cap semantics as AllObjects.
+func (c *Client) AllRelationships(ctx context.Context, opts *graph.ListRelationshipsOptions, capItems int) iter.Seq2[*graph.GraphRelationship, error] {
+	return func(yield func(*graph.GraphRelationship, error) bool) {
+		page := graph.ListRelationshipsOptions{}
+		if opts != nil {
+			page = *opts
+		}
+		if page.BranchID == "" {
+			page.BranchID = BranchFrom(ctx)
+		}
+		if page.Limit <= 0 {
+			page.Limit = listPageSize
+		}
+		limit := c.effectiveCap(capItems)
+
+		count := 0
+		for {
+			var resp *graph.SearchRelationshipsResponse
+			err := c.withRetry(ctx, "list relationships page", func() error {
+				var listErr error
+				resp, listErr = c.sdk.Graph.ListRelationships(ctx, &page)
+				return listErr
+			})
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, item := range resp.Items {
+				if count >= limit {
+					yield(nil, ErrListTruncated)
+					return
+				}
+				if !yield(item, nil) {
+					return
+				}
+				count++
+			}
+			if resp.NextCursor == nil || *resp.NextCursor == "" || len(resp.Items) == 0 {
+				return
+			}
+			if count >= limit {
+				yield(nil, ErrListTruncated)
+				return
+			}
+			page.Cursor = *resp.NextCursor
+		}
+	}
+}
+
+// ListAllRelationships collects AllRelationships into a slice. truncated
+// reports whether the cap was hit with more relationships remaining.
+func (c *Client) ListAllRelationships(ctx context.Context, opts *graph.ListRelationshipsOptions, capItems int) (items []*graph.GraphRelationship, truncated bool, err error) {
+	for rel, err := range c.AllRelationships(ctx, opts, capItems) {
+		if errors.Is(err, ErrListTruncated) {
+			c.logger.Warn("relationship listing truncated at cap", "cap", c.effectiveCap(capItems))
+			return items, true, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, rel)
+	}
+	return items, false, nil
+}
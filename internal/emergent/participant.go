@@ -0,0 +1,148 @@
+package emergent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sdkerrors "github.com/emergent-company/emergent/apps/server-go/pkg/sdk/errors"
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+// Participant is an Agent or Actor acting on the graph, e.g. as a reviewer
+// or comment author.
+type Participant struct {
+	ID      string
+	Name    string
+	Type    string // human or ai
+	Subject string // Emergent user the participant is bound to, if any
+}
+
+// FindParticipant finds the Agent or Actor named name, or returns nil if
+// neither exists. Actors are human roles; an Agent is human only when its
+// type says so.
+func (c *Client) FindParticipant(ctx context.Context, name string) (*Participant, error) {
+	agent, err := c.FindByTypeAndKey(ctx, TypeAgent, name)
+	if err != nil {
+		return nil, fmt.Errorf("looking up agent %q: %w", name, err)
+	}
+	if agent != nil {
+		p := participantFromObject(agent)
+		p.Name = name
+		return p, nil
+	}
+	actor, err := c.FindByTypeAndKey(ctx, TypeActor, name)
+	if err != nil {
+		return nil, fmt.Errorf("looking up actor %q: %w", name, err)
+	}
+	if actor != nil {
+		p := participantFromObject(actor)
+		p.Name = name
+		return p, nil
+	}
+	return nil, nil
+}
+
+// ErrSubjectBound is returned when an Agent or Actor would be bound to an
+// Emergent user subject another participant is already bound to.
+var ErrSubjectBound = errors.New("subject already bound")
+
+// CheckSubjectUnbound returns ErrSubjectBound if subject is bound to an Agent
+// or Actor other than the one of type typeName named name. Each user must
+// map to a single participant, or one person could approve twice.
+func (c *Client) CheckSubjectUnbound(ctx context.Context, subject, typeName, name string) error {
+	if subject == "" {
+		return nil
+	}
+	for _, t := range []string{TypeAgent, TypeActor} {
+		opts := &graph.ListObjectsOptions{
+			Type:  t,
+			Limit: 2,
+			PropertyFilters: []graph.PropertyFilter{
+				{Path: "subject", Op: "eq", Value: subject},
+			},
+		}
+		objs, err := c.ListObjects(ctx, opts)
+		if err == nil && BranchFrom(ctx) != "" {
+			var main []*graph.GraphObject
+			main, err = c.ListObjects(WithoutBranch(ctx), opts)
+			objs = append(objs, main...)
+		}
+		if err != nil {
+			return fmt.Errorf("looking up %s bound to %s: %w", t, subject, err)
+		}
+		for _, obj := range objs {
+			other, _ := obj.Properties["name"].(string)
+			if t != typeName || other != name {
+				return fmt.Errorf("%w: %s %q is bound to %s", ErrSubjectBound, t, other, subject)
+			}
+		}
+	}
+	return nil
+}
+
+// FindParticipantBySubject finds the Agent or Actor bound to the Emergent
+// user subject, or returns nil if there is none. Agents are preferred.
+// Several participants of one type bound to the subject is ErrSubjectBound.
+func (c *Client) FindParticipantBySubject(ctx context.Context, subject string) (*Participant, error) {
+	if subject == "" {
+		return nil, nil
+	}
+	for _, typeName := range []string{TypeAgent, TypeActor} {
+		opts := &graph.ListObjectsOptions{
+			Type:  typeName,
+			Limit: 2,
+			PropertyFilters: []graph.PropertyFilter{
+				{Path: "subject", Op: "eq", Value: subject},
+			},
+		}
+		objs, err := c.ListObjects(ctx, opts)
+		if err == nil && len(objs) == 0 && BranchFrom(ctx) != "" {
+			objs, err = c.ListObjects(WithoutBranch(ctx), opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("looking up %s bound to %s: %w", typeName, subject, err)
+		}
+		if len(objs) > 1 {
+			return nil, fmt.Errorf("%w: several %ss are bound to %s", ErrSubjectBound, typeName, subject)
+		}
+		if len(objs) > 0 {
+			return participantFromObject(objs[0]), nil
+		}
+	}
+	return nil, nil
+}
+
+func participantFromObject(obj *graph.GraphObject) *Participant {
+	name, _ := obj.Properties["name"].(string)
+	subject, _ := obj.Properties["subject"].(string)
+	kind := "human"
+	if obj.Type == TypeAgent {
+		if t, _ := obj.Properties["type"].(string); t != "human" {
+			kind = "ai"
+		}
+	}
+	return &Participant{ID: obj.ID, Name: name, Type: kind, Subject: subject}
+}
+
+// Caller is the Emergent user behind the request's token.
+type Caller struct {
+	Subject string // user ID
+	Email   string
+}
+
+// Caller returns the Emergent user the request's token belongs to, or nil
+// if the token is not tied to a user (e.g. a project API key).
+func (c *Client) Caller(ctx context.Context) (*Caller, error) {
+	profile, err := c.sdk.Users.GetProfile(ctx)
+	if sdkerrors.IsUnauthorized(err) || sdkerrors.IsForbidden(err) || sdkerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("identifying caller: %w", err)
+	}
+	if profile.ID == "" {
+		return nil, nil
+	}
+	return &Caller{Subject: profile.ID, Email: profile.Email}, nil
+}
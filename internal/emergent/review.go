@@ -0,0 +1,160 @@
+package emergent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+)
+
+// --- Review ---
+
+// bookkeepingProps are the properties the workflow writes on an artifact
+// without editing its content: readiness and its transition history.
+var bookkeepingProps = map[string]bool{
+	"status":      true,
+	"transitions": true,
+}
+
+// ArtifactDigest fingerprints a workflow artifact's content, ignoring its
+// bookkeeping properties, so a Review can tell whether the artifact was
+// edited after the decision.
+func ArtifactDigest(props map[string]any) string {
+	content := make(map[string]any, len(props))
+	for k, v := range props {
+		if !bookkeepingProps[k] {
+			content[k] = v
+		}
+	}
+	b, _ := json.Marshal(content) // map keys are sorted
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+// CreateReview creates a Review and links it to the artifact (reviews) and
+// the reviewer's Agent or Actor (reviewed_by).
+func (c *Client) CreateReview(ctx context.Context, artifactID, reviewerID string, r *Review) (*Review, error) {
+	props, err := toProps(r)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := c.CreateObject(ctx, TypeReview, nil, props, r.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("creating Review: %w", err)
+	}
+	if _, err := c.CreateRelationship(ctx, RelReviews, obj.ID, artifactID, nil); err != nil {
+		return nil, fmt.Errorf("linking Review to artifact: %w", err)
+	}
+	if reviewerID != "" {
+		if _, err := c.CreateRelationship(ctx, RelReviewedBy, obj.ID, reviewerID, nil); err != nil {
+			return nil, fmt.Errorf("linking Review to reviewer: %w", err)
+		}
+	}
+	return reviewFromObject(obj)
+}
+
+// UpdateReview sets properties on a Review and returns the new version.
+func (c *Client) UpdateReview(ctx context.Context, id string, props map[string]any) (*Review, error) {
+	obj, err := c.UpdateObject(ctx, id, props, nil)
+	if err != nil {
+		return nil, err
+	}
+	return reviewFromObject(obj)
+}
+
+// ListReviews returns the Reviews of an artifact, oldest first.
+func (c *Client) ListReviews(ctx context.Context, artifactID string) ([]*Review, error) {
+	edges, err := c.GetObjectEdges(ctx, artifactID, &graph.GetObjectEdgesOptions{
+		Types:     []string{RelReviews},
+		Direction: "incoming",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting reviews of %s: %w", artifactID, err)
+	}
+	ids := make([]string, 0, len(edges.Incoming))
+	for _, rel := range edges.Incoming {
+		ids = append(ids, rel.SrcID)
+	}
+	objs, err := c.GetObjects(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("getting reviews of %s: %w", artifactID, err)
+	}
+	sort.SliceStable(objs, func(i, j int) bool { return objs[i].CreatedAt.Before(objs[j].CreatedAt) })
+
+	seen := make(map[string]bool, len(objs))
+	reviews := make([]*Review, 0, len(objs))
+	for _, obj := range objs {
+		if obj.Type != TypeReview || seen[obj.CanonicalID] {
+			continue
+		}
+		seen[obj.CanonicalID] = true
+		r, err := reviewFromObject(obj)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, r)
+	}
+	return reviews, nil
+}
+
+func reviewFromObject(obj *graph.GraphObject) (*Review, error) {
+	r, err := fromProps[Review](obj)
+	if err != nil {
+		return nil, err
+	}
+	r.ID = obj.ID
+	r.CanonicalID = obj.CanonicalID
+	return r, nil
+}
+
+// ReviewSummary is the state of an artifact's reviews: each reviewer's
+// latest Review counts, and decisions on earlier content are stale.
+type ReviewSummary struct {
+	Approvals      int      `json:"approvals"`
+	HumanApprovals int      `json:"human_approvals"`
+	ApprovedBy     []string `json:"approved_by,omitempty"`
+	RejectedBy     []string `json:"rejected_by,omitempty"`
+	Pending        []string `json:"pending,omitempty"` // requested, not yet decided
+	Stale          []string `json:"stale,omitempty"`   // decided on content that has since changed
+}
+
+// SummarizeReviews summarizes reviews (oldest first) against the artifact's
+// current digest.
+func SummarizeReviews(reviews []*Review, digest string) ReviewSummary {
+	latest := make(map[string]*Review)
+	var order []string
+	for _, r := range reviews {
+		if _, ok := latest[r.Reviewer]; !ok {
+			order = append(order, r.Reviewer)
+		}
+		latest[r.Reviewer] = r
+	}
+
+	var s ReviewSummary
+	humans := make(map[string]bool)
+	for _, name := range order {
+		r := latest[name]
+		switch {
+		case r.Status == ReviewRequested:
+			s.Pending = append(s.Pending, name)
+		case r.ArtifactDigest != digest:
+			s.Stale = append(s.Stale, name)
+		case r.Status == ReviewApproved:
+			s.Approvals++
+			// A human approval needs the bound user's own token, and
+			// counts once per user however many participants they decided as.
+			if r.ReviewerType == "human" && r.DecidedBy != "" && !humans[r.DecidedBy] {
+				humans[r.DecidedBy] = true
+				s.HumanApprovals++
+			}
+			s.ApprovedBy = append(s.ApprovedBy, name)
+		case r.Status == ReviewRejected:
+			s.RejectedBy = append(s.RejectedBy, name)
+		}
+	}
+	return s
+}
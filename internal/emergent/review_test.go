@@ -0,0 +1,120 @@
+package emergent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSummarizeReviews(t *testing.T) {
+	const digest = "current"
+	review := func(reviewer, status, artifactDigest, reviewerType, decidedBy string) *Review {
+		return &Review{
+			Reviewer:       reviewer,
+			Status:         status,
+			ArtifactDigest: artifactDigest,
+			ReviewerType:   reviewerType,
+			DecidedBy:      decidedBy,
+		}
+	}
+	tests := []struct {
+		name    string
+		reviews []*Review
+		want    ReviewSummary
+	}{
+		{
+			name: "no reviews",
+		},
+		{
+			name: "ai approval",
+			reviews: []*Review{
+				review("bot", ReviewApproved, digest, "ai", ""),
+			},
+			want: ReviewSummary{Approvals: 1, ApprovedBy: []string{"bot"}},
+		},
+		{
+			name: "human approval by bound user",
+			reviews: []*Review{
+				review("alice", ReviewApproved, digest, "human", "user-1"),
+			},
+			want: ReviewSummary{Approvals: 1, HumanApprovals: 1, ApprovedBy: []string{"alice"}},
+		},
+		{
+			name: "human approval without deciding user",
+			reviews: []*Review{
+				review("alice", ReviewApproved, digest, "human", ""),
+			},
+			want: ReviewSummary{Approvals: 1, ApprovedBy: []string{"alice"}},
+		},
+		{
+			name: "pending",
+			reviews: []*Review{
+				review("alice", ReviewRequested, "", "human", ""),
+			},
+			want: ReviewSummary{Pending: []string{"alice"}},
+		},
+		{
+			name: "stale approval",
+			reviews: []*Review{
+				review("alice", ReviewApproved, "earlier", "human", "user-1"),
+			},
+			want: ReviewSummary{Stale: []string{"alice"}},
+		},
+		{
+			name: "latest decision wins",
+			reviews: []*Review{
+				review("alice", ReviewApproved, digest, "human", "user-1"),
+				review("alice", ReviewRejected, digest, "human", "user-1"),
+			},
+			want: ReviewSummary{RejectedBy: []string{"alice"}},
+		},
+		{
+			name: "new request after approval",
+			reviews: []*Review{
+				review("alice", ReviewApproved, digest, "human", "user-1"),
+				review("alice", ReviewRequested, "", "human", ""),
+			},
+			want: ReviewSummary{Pending: []string{"alice"}},
+		},
+		{
+			name: "two participants with one subject",
+			reviews: []*Review{
+				review("alice", ReviewApproved, digest, "human", "user-1"),
+				review("alice-admin", ReviewApproved, digest, "human", "user-1"),
+			},
+			want: ReviewSummary{Approvals: 2, HumanApprovals: 1, ApprovedBy: []string{"alice", "alice-admin"}},
+		},
+		{
+			name: "two subjects",
+			reviews: []*Review{
+				review("alice", ReviewApproved, digest, "human", "user-1"),
+				review("bob", ReviewApproved, digest, "human", "user-2"),
+			},
+			want: ReviewSummary{Approvals: 2, HumanApprovals: 2, ApprovedBy: []string{"alice", "bob"}},
+		},
+		{
+			name: "mixed",
+			reviews: []*Review{
+				review("alice", ReviewApproved, digest, "human", "user-1"),
+				review("bob", ReviewApproved, "earlier", "human", "user-2"),
+				review("carol", ReviewRequested, "", "human", ""),
+				review("bot", ReviewRejected, digest, "ai", ""),
+			},
+			want: ReviewSummary{
+				Approvals:      1,
+				HumanApprovals: 1,
+				ApprovedBy:     []string{"alice"},
+				RejectedBy:     []string{"bot"},
+				Pending:        []string{"carol"},
+				Stale:          []string{"bob"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SummarizeReviews(tt.reviews, digest)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SummarizeReviews() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
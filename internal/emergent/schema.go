@@ -0,0 +1,251 @@
+package emergent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RequiredPackName and RequiredPackVersion identify the template pack that
+// defines every type and relationship constant in this package.
+const (
+	RequiredPackName    = "SpecMCP"
+	RequiredPackVersion = "2.10.0"
+)
+
+// ObjectTypes lists every Type* constant. Keep in sync with the constants.
+var ObjectTypes = []string{
+	TypeApp, TypeDataModel,
+	TypeChange, TypeProposal, TypeSpec, TypeRequirement, TypeScenario, TypeScenarioStep, TypeDesign, TypeTask,
+	TypeLivingSpec,
+	TypeContext, TypeUIComponent, TypeAction, TypeAPIContract, TypeTestCase,
+	TypeActor, TypeAgent, TypePattern, TypeConstitution, TypeGraphSync, TypeMaintenanceIssue, TypeImprovement,
+	TypeReview, TypeComment,
+}
+
+// RelationshipTypes lists every Rel* constant. Keep in sync with the constants.
+var RelationshipTypes = []string{
+	RelHasProposal, RelHasSpec, RelHasDesign, RelHasTask, RelHasRequirement, RelHasScenario, RelHasStep, RelHasSubtask,
+	RelBelongsToApp, RelScopedToApp, RelDependsOnApp, RelProvidesModel, RelConsumesModel, RelExposesAPI,
+	RelUsesPattern, RelExtendsPattern, RelRequiresPattern, RelForbidsPattern,
+	RelComposedOf, RelUsesComponent, RelNestedIn, RelOccursIn, RelPerforms, RelAvailableIn, RelNavigatesTo,
+	RelHasContract, RelImplementsContract, RelTestedBy, RelTests,
+	RelInheritsFrom, RelExecutedBy, RelAssignedTo, RelOwnedBy, RelGovernedBy,
+	RelVariantOf, RelBlocks, RelBlockedBy, RelImplements,
+	RelChangeCreates, RelChangeModifies, RelChangeReferences, RelDependsOnChange,
+	RelMergedInto, RelIncludesRequirement, RelSupersedes,
+	RelReviews, RelReviewedBy,
+	RelCommentsOn, RelReplyTo, RelAuthoredBy,
+	RelAffectsEntity, RelParentIssue, RelResolvedByChange, RelProposedBy,
+}
+
+// schemaRecheckInterval is how long an incompatible or failed schema check
+// is trusted before it is run again. Compatible results are kept for the
+// life of the process.
+const schemaRecheckInterval = 5 * time.Minute
+
+// ErrSchemaIncompatible is wrapped by SchemaError.
+var ErrSchemaIncompatible = errors.New("project schema is incompatible")
+
+// SchemaReport is the result of comparing the project's compiled types with
+// the types SpecMCP uses.
+type SchemaReport struct {
+	ProjectID            string    `json:"project_id,omitempty"`
+	InstalledPacks       []string  `json:"installed_packs"` // "name vX.Y.Z"
+	MissingTypes         []string  `json:"missing_types,omitempty"`
+	MissingRelationships []string  `json:"missing_relationships,omitempty"`
+	CheckedAt            time.Time `json:"checked_at"`
+}
+
+// Compatible reports whether every type and relationship exists.
+func (r *SchemaReport) Compatible() bool {
+	return len(r.MissingTypes) == 0 && len(r.MissingRelationships) == 0
+}
+
+// missing reports whether a type or relationship name is missing.
+func (r *SchemaReport) missing(name string) bool {
+	return slices.Contains(r.MissingTypes, name) || slices.Contains(r.MissingRelationships, name)
+}
+
+// SchemaError is returned when an operation needs a type or relationship
+// that the project's template pack does not define.
+type SchemaError struct {
+	Name   string // the missing type or relationship
+	Report *SchemaReport
+}
+
+func (e *SchemaError) Error() string {
+	installed := "none"
+	if len(e.Report.InstalledPacks) > 0 {
+		installed = strings.Join(e.Report.InstalledPacks, ", ")
+	}
+	kind := "object type"
+	if slices.Contains(RelationshipTypes, e.Name) {
+		kind = "relationship type"
+	}
+	return fmt.Sprintf("project schema has no %s %q (installed packs: %s). Install or upgrade the %s template pack to v%s: run \"specmcp pack upgrade\" (or \"specmcp pack install\")",
+		kind, e.Name, installed, RequiredPackName, RequiredPackVersion)
+}
+
+func (e *SchemaError) Unwrap() error { return ErrSchemaIncompatible }
+
+// CheckSchema compares the project's compiled types with ObjectTypes and
+// RelationshipTypes.
+func (c *Client) CheckSchema(ctx context.Context) (*SchemaReport, error) {
+	compiled, err := c.sdk.TemplatePacks.GetCompiledTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting compiled types: %w", err)
+	}
+	report := &SchemaReport{
+		ProjectID:      ProjectIDFrom(ctx),
+		InstalledPacks: []string{},
+		CheckedAt:      time.Now(),
+	}
+
+	installed, err := c.sdk.TemplatePacks.GetInstalledPacks(ctx)
+	if err == nil {
+		for _, p := range installed {
+			if p.Active {
+				report.InstalledPacks = append(report.InstalledPacks, p.Name+" v"+p.Version)
+			}
+		}
+	}
+
+	have := make(map[string]bool, len(compiled.ObjectTypes)+len(compiled.RelationshipTypes))
+	for _, ot := range compiled.ObjectTypes {
+		have[ot.Name] = true
+	}
+	for _, name := range ObjectTypes {
+		if !have[name] {
+			report.MissingTypes = append(report.MissingTypes, name)
+		}
+	}
+	clear(have)
+	for _, rt := range compiled.RelationshipTypes {
+		have[rt.Name] = true
+	}
+	for _, name := range RelationshipTypes {
+		if !have[name] {
+			report.MissingRelationships = append(report.MissingRelationships, name)
+		}
+	}
+	return report, nil
+}
+
+// logSchemaReport writes a structured compatibility report.
+func logSchemaReport(c *Client, report *SchemaReport) {
+	attrs := []any{
+		"project_id", report.ProjectID,
+		"compatible", report.Compatible(),
+		"installed_packs", report.InstalledPacks,
+		"required_pack", RequiredPackName + " v" + RequiredPackVersion,
+	}
+	if report.Compatible() {
+		c.logger.Info("schema compatibility check passed", attrs...)
+		return
+	}
+	attrs = append(attrs,
+		"missing_types", report.MissingTypes,
+		"missing_relationships", report.MissingRelationships,
+		"remedy", "run \"specmcp pack upgrade\"",
+	)
+	c.logger.Warn("schema compatibility check failed", attrs...)
+}
+
+// schemaCache holds one schema check per project, shared by all clients the
+// factory creates for that project.
+type schemaCache struct {
+	mu      sync.Mutex
+	entries map[string]*schemaEntry
+}
+
+// schemaEntry is the cached schema check of one project.
+type schemaEntry struct {
+	mu      sync.Mutex
+	report  *SchemaReport // nil if the check has not run or failed
+	checked time.Time
+}
+
+// entry returns the cache entry for a project. Project-scoped tokens carry
+// their project implicitly, so without an explicit project ID the token
+// identifies the project.
+func (s *schemaCache) entry(projectID, token string) *schemaEntry {
+	key := projectID
+	if key == "" {
+		sum := sha256.Sum256([]byte(token))
+		key = "token:" + hex.EncodeToString(sum[:8])
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries == nil {
+		s.entries = make(map[string]*schemaEntry)
+	}
+	e, ok := s.entries[key]
+	if !ok {
+		e = &schemaEntry{}
+		s.entries[key] = e
+	}
+	return e
+}
+
+// get returns the cached report, running the check first if it has never
+// run or an incompatible or failed result is older than
+// schemaRecheckInterval. A failed check returns nil so callers proceed
+// unchecked; tokens without template pack access must not block tools.
+func (e *schemaEntry) get(ctx context.Context, c *Client) *SchemaReport {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.checked.IsZero() {
+		if e.report != nil && e.report.Compatible() {
+			return e.report
+		}
+		if time.Since(e.checked) < schemaRecheckInterval {
+			return e.report
+		}
+	}
+
+	e.checked = time.Now()
+	report, err := c.CheckSchema(ctx)
+	if err != nil {
+		c.logger.Warn("schema compatibility check skipped", "project_id", ProjectIDFrom(ctx), "error", err)
+		e.report = nil
+		return nil
+	}
+	e.report = report
+	logSchemaReport(c, report)
+	return report
+}
+
+// requireSchema returns a SchemaError if the project lacks the named type or
+// relationship. It runs the project's schema check on first use.
+func (c *Client) requireSchema(ctx context.Context, name string) error {
+	if c.schema == nil {
+		return nil
+	}
+	report := c.schema.get(ctx, c)
+	if report != nil && report.missing(name) {
+		return &SchemaError{Name: name, Report: report}
+	}
+	return nil
+}
+
+// CheckSchema runs the schema compatibility check for the project in ctx and
+// logs the report. Use it at startup; tools otherwise run the check lazily
+// the first time they write to a project.
+func (f *ClientFactory) CheckSchema(ctx context.Context) (*SchemaReport, error) {
+	c, err := f.ClientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := c.schema.get(ctx, c)
+	if report == nil {
+		return nil, fmt.Errorf("schema check failed (see logs)")
+	}
+	return report, nil
+}
@@ -18,6 +18,9 @@ const (
 	TypeDesign       = "Design"
 	TypeTask         = "Task"
 
+	// Living specs: the merged, change-independent truth per domain
+	TypeLivingSpec = "LivingSpec"
+
 	// Implementation entities
 	TypeContext     = "Context"
 	TypeUIComponent = "UIComponent"
@@ -33,6 +36,12 @@ const (
 	TypeGraphSync        = "GraphSync"
 	TypeMaintenanceIssue = "MaintenanceIssue"
 	TypeImprovement      = "Improvement"
+
+	// Reviews: approvals and rejections of workflow artifacts
+	TypeReview = "Review"
+
+	// Comments: feedback threads on any entity
+	TypeComment = "Comment"
 )
 
 // Relationship type constants.
@@ -95,6 +104,23 @@ const (
 	RelChangeModifies   = "change_modifies"   // Change updated this entity (points to new version's ID)
 	RelChangeReferences = "change_references" // Change used this entity as-is (points to current version's ID)
 
+	// Change ordering
+	RelDependsOnChange = "depends_on_change" // Change → Change (must be archived first)
+
+	// Living spec relationships, written when a Change is archived
+	RelMergedInto          = "merged_into"          // Spec → LivingSpec (points to the living spec version the merge produced)
+	RelIncludesRequirement = "includes_requirement" // LivingSpec → Requirement (current requirements of the domain)
+	RelSupersedes          = "supersedes"           // Requirement → Requirement (newer version → version it replaced)
+
+	// Review relationships
+	RelReviews    = "reviews"     // Review → Proposal/Spec/Requirement/Scenario/Design
+	RelReviewedBy = "reviewed_by" // Review → Agent/Actor (the reviewer)
+
+	// Comment relationships
+	RelCommentsOn = "comments_on" // Comment → any entity (replies point at the thread's entity too)
+	RelReplyTo    = "reply_to"    // Comment → Comment (the comment replied to)
+	RelAuthoredBy = "authored_by" // Comment → Agent/Actor (the author)
+
 	// Maintenance relationships
 	RelAffectsEntity    = "affects_entity"     // MaintenanceIssue → Entity (links to entities with problems)
 	RelParentIssue      = "parent_issue"       // MaintenanceIssue → MaintenanceIssue (groups related issues)
@@ -113,6 +139,35 @@ const (
 	StatusProposed   = "proposed" // For Improvement entities
 )
 
+// StatusTransition is one entry of the transition history that Changes,
+// Tasks, Improvements and workflow artifacts keep in their "transitions"
+// property, oldest first.
+type StatusTransition struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	At     string `json:"at"` // RFC 3339
+	Reason string `json:"reason,omitempty"`
+	Forced bool   `json:"forced,omitempty"` // soft guards were overridden
+	Via    string `json:"via,omitempty"`    // tool that made the transition
+}
+
+// Delta types of Spec and Requirement entities. They say how a Change's
+// artifact is merged into the domain's living spec on archive.
+const (
+	DeltaAdded    = "added"
+	DeltaModified = "modified"
+	DeltaRemoved  = "removed"
+	DeltaRenamed  = "renamed" // Requirement/Spec name changed; renamed_from holds the old name
+)
+
+// Review status constants. A Review starts as requested (or directly as a
+// decision) and records the reviewer's approval or rejection.
+const (
+	ReviewRequested = "requested"
+	ReviewApproved  = "approved"
+	ReviewRejected  = "rejected"
+)
+
 // Artifact readiness status constants.
 // Workflow artifacts (Proposal, Spec, Requirement, Scenario, Design) start as
 // draft and must be explicitly marked ready before the next workflow stage can proceed.
@@ -139,10 +194,25 @@ func IsWorkflowArtifactType(typeName string) bool {
 type Change struct {
 	ID          string   `json:"id,omitempty"`
 	CanonicalID string   `json:"-"` // From GraphObject.CanonicalID; not a property
+	Version     int      `json:"-"` // From GraphObject.Version; not a property
 	Name        string   `json:"name"`
 	Status      string   `json:"status"`
 	BaseCommit  string   `json:"base_commit,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+
+	// Emergent branch holding the change's artifacts, set by spec_new when
+	// asked to branch. The Change itself stays on the main branch.
+	BranchID       string `json:"branch_id,omitempty"`
+	BranchMergedAt string `json:"branch_merged_at,omitempty"`
+
+	// Archive audit trail, set by spec_archive and spec_unarchive.
+	ArchivedFrom    string `json:"archived_from,omitempty"` // status before archiving
+	ArchivedAt      string `json:"archived_at,omitempty"`
+	MergePending    bool   `json:"merge_pending,omitempty"` // archived, branch or living spec merge not finished
+	UnarchivedAt    string `json:"unarchived_at,omitempty"`
+	UnarchiveReason string `json:"unarchive_reason,omitempty"`
+
+	Transitions []StatusTransition `json:"transitions,omitempty"`
 }
 
 // Proposal represents the intent of a change.
@@ -154,6 +224,8 @@ type Proposal struct {
 	Scope       string   `json:"scope,omitempty"`
 	Impact      string   `json:"impact,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+
+	Transitions []StatusTransition `json:"transitions,omitempty"`
 }
 
 // Spec represents a domain-specific specification container.
@@ -165,7 +237,10 @@ type Spec struct {
 	Domain      string   `json:"domain,omitempty"`
 	Purpose     string   `json:"purpose,omitempty"`
 	DeltaType   string   `json:"delta_type,omitempty"`
+	RenamedFrom string   `json:"renamed_from,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+
+	Transitions []StatusTransition `json:"transitions,omitempty"`
 }
 
 // Requirement represents a specific behavior the system must have.
@@ -177,6 +252,23 @@ type Requirement struct {
 	Description string   `json:"description"`
 	Strength    string   `json:"strength,omitempty"`
 	DeltaType   string   `json:"delta_type,omitempty"`
+	RenamedFrom string   `json:"renamed_from,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+
+	Transitions []StatusTransition `json:"transitions,omitempty"`
+}
+
+// LivingSpec is the canonical specification of one domain, assembled from
+// the delta Specs of archived Changes.
+type LivingSpec struct {
+	ID          string   `json:"id,omitempty"`
+	CanonicalID string   `json:"-"` // From GraphObject.CanonicalID; not a property
+	Name        string   `json:"name"`
+	Domain      string   `json:"domain"`
+	Purpose     string   `json:"purpose,omitempty"`
+	Status      string   `json:"status,omitempty"` // active or removed
+	Revision    int      `json:"revision"`
+	LastChange  string   `json:"last_change,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
 }
 
@@ -192,6 +284,8 @@ type Scenario struct {
 	Then        string   `json:"then,omitempty"`
 	AndAlso     []string `json:"and_also,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+
+	Transitions []StatusTransition `json:"transitions,omitempty"`
 }
 
 // ScenarioStep represents a step in a complex scenario.
@@ -213,12 +307,16 @@ type Design struct {
 	DataFlow    string   `json:"data_flow,omitempty"`
 	FileChanges []string `json:"file_changes,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+
+	Transitions []StatusTransition `json:"transitions,omitempty"`
 }
 
 // Task represents an implementation task.
 type Task struct {
 	ID                 string     `json:"id,omitempty"`
 	CanonicalID        string     `json:"-"` // From GraphObject.CanonicalID; not a property
+	Version            int        `json:"-"` // From GraphObject.Version; not a property
+	BranchID           string     `json:"-"` // From GraphObject.BranchID; not a property
 	Number             string     `json:"number"`
 	Description        string     `json:"description"`
 	TaskType           string     `json:"task_type,omitempty"`
@@ -231,6 +329,8 @@ type Task struct {
 	VerificationMethod string     `json:"verification_method,omitempty"`
 	VerificationNotes  string     `json:"verification_notes,omitempty"`
 	Tags               []string   `json:"tags,omitempty"`
+
+	Transitions []StatusTransition `json:"transitions,omitempty"`
 }
 
 // Actor represents a user, role, or persona.
@@ -240,6 +340,7 @@ type Actor struct {
 	Name        string   `json:"name"`
 	DisplayName string   `json:"display_name,omitempty"`
 	Description string   `json:"description,omitempty"`
+	Subject     string   `json:"subject,omitempty"` // Emergent user the actor stands for
 	Tags        []string `json:"tags,omitempty"`
 }
 
@@ -251,6 +352,7 @@ type Agent struct {
 	Name                string   `json:"name"`
 	DisplayName         string   `json:"display_name,omitempty"`
 	Type                string   `json:"type"`
+	Subject             string   `json:"subject,omitempty"`    // Emergent user the agent acts as
 	AgentType           string   `json:"agent_type,omitempty"` // coding, maintenance, research, testing, deployment, analysis
 	Active              bool     `json:"active"`
 	Skills              []string `json:"skills,omitempty"`
@@ -276,17 +378,72 @@ type Pattern struct {
 
 // Constitution represents project-wide principles.
 type Constitution struct {
-	ID                   string   `json:"id,omitempty"`
-	CanonicalID          string   `json:"-"` // From GraphObject.CanonicalID; not a property
-	Name                 string   `json:"name"`
-	Version              string   `json:"version"`
-	Principles           string   `json:"principles,omitempty"`
-	Guardrails           []string `json:"guardrails,omitempty"`
-	TestingRequirements  string   `json:"testing_requirements,omitempty"`
-	SecurityRequirements string   `json:"security_requirements,omitempty"`
-	PatternsRequired     []string `json:"patterns_required,omitempty"`
-	PatternsForbidden    []string `json:"patterns_forbidden,omitempty"`
-	Tags                 []string `json:"tags,omitempty"`
+	ID                   string      `json:"id,omitempty"`
+	CanonicalID          string      `json:"-"` // From GraphObject.CanonicalID; not a property
+	Name                 string      `json:"name"`
+	Version              string      `json:"version"`
+	Principles           string      `json:"principles,omitempty"`
+	Guardrails           []string    `json:"guardrails,omitempty"`
+	TestingRequirements  string      `json:"testing_requirements,omitempty"`
+	SecurityRequirements string      `json:"security_requirements,omitempty"`
+	PatternsRequired     []string    `json:"patterns_required,omitempty"`
+	PatternsForbidden    []string    `json:"patterns_forbidden,omitempty"`
+	ReviewPolicy         []string    `json:"review_policy,omitempty"`   // e.g. "Proposal: 1 human"
+	WorkflowStages       []string    `json:"workflow_stages,omitempty"` // e.g. "design?: design after specify"
+	GuardPolicy          []string    `json:"guard_policy,omitempty"`    // e.g. "context_discovery@billing-api: off"
+	GuardRules           []GuardRule `json:"guard_rules,omitempty"`
+	Tags                 []string    `json:"tags,omitempty"`
+}
+
+// GuardRule is a custom guard written as an expression (see
+// guards.CompileRule). The rule fails with Severity, Message, and Remedy
+// when Expr is false on one of the operations in On.
+type GuardRule struct {
+	Name     string   `json:"name"`
+	On       []string `json:"on"` // new_change, artifact, mark_ready, archive
+	Expr     string   `json:"expr"`
+	Severity string   `json:"severity,omitempty"` // Default: warning
+	Message  string   `json:"message"`
+	Remedy   string   `json:"remedy,omitempty"`
+}
+
+// Review is one reviewer's request, approval, or rejection of a workflow
+// artifact. ArtifactDigest fingerprints the artifact content the decision
+// was made on, so edits after an approval make it stale.
+type Review struct {
+	ID             string   `json:"id,omitempty"`
+	CanonicalID    string   `json:"-"` // From GraphObject.CanonicalID; not a property
+	Name           string   `json:"name"`
+	Status         string   `json:"status"` // requested, approved, rejected
+	Reviewer       string   `json:"reviewer"`
+	ReviewerType   string   `json:"reviewer_type,omitempty"` // human or ai
+	ArtifactType   string   `json:"artifact_type,omitempty"`
+	ArtifactDigest string   `json:"artifact_digest,omitempty"`
+	Comment        string   `json:"comment,omitempty"`
+	RequestedBy    string   `json:"requested_by,omitempty"`
+	RequestedAt    string   `json:"requested_at,omitempty"`
+	DecidedAt      string   `json:"decided_at,omitempty"`
+	DecidedBy      string   `json:"decided_by,omitempty"` // authenticated user who decided, if the reviewer is bound to one
+	Tags           []string `json:"tags,omitempty"`
+}
+
+// Comment is feedback on an entity. A comment without ReplyTo starts a
+// thread; replies point at their parent's canonical ID. Only the thread's
+// first comment carries the resolved state.
+type Comment struct {
+	ID          string   `json:"id,omitempty"`
+	CanonicalID string   `json:"-"` // From GraphObject.CanonicalID; not a property
+	BranchID    string   `json:"-"` // From GraphObject.BranchID; not a property
+	Name        string   `json:"name"`
+	Body        string   `json:"body"`
+	Author      string   `json:"author"`
+	AuthorType  string   `json:"author_type,omitempty"` // human or ai
+	ReplyTo     string   `json:"reply_to,omitempty"`    // canonical ID of the parent comment
+	Resolved    bool     `json:"resolved"`
+	ResolvedBy  string   `json:"resolved_by,omitempty"`
+	ResolvedAt  string   `json:"resolved_at,omitempty"`
+	CreatedAt   string   `json:"created_at,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
 }
 
 // TestCase links scenarios to executable tests.
@@ -436,6 +593,8 @@ type Improvement struct {
 	ProposedBy  string     `json:"proposed_by"` // Agent name who proposed this
 	Tags        []string   `json:"tags,omitempty"`
 
+	Transitions []StatusTransition `json:"transitions,omitempty"`
+
 	// Knowledge contribution fields (used for constitution_rule, pattern_proposal, technology_choice, best_practice)
 	TriggerQuote         string                 `json:"trigger_quote,omitempty"`          // Exact user quote that triggered this
 	Evidence             []string               `json:"evidence,omitempty"`               // Files, observations supporting this
@@ -0,0 +1,131 @@
+package emergent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// unitOfWorkContextKey is the context key type for the active UnitOfWork.
+type unitOfWorkContextKey struct{}
+
+// unitOfWorkKey is the context key for the active UnitOfWork.
+var unitOfWorkKey = unitOfWorkContextKey{}
+
+// uowEntry is a single object or relationship created within a UnitOfWork.
+type uowEntry struct {
+	kind string // "object" or "relationship"
+	typ  string
+	id   string
+}
+
+// UnitOfWork records every object and relationship created through a Client
+// while it is attached to the context (see WithUnitOfWork). If the operation
+// fails partway, Rollback deletes them in reverse creation order so no
+// half-built hierarchy is left behind.
+//
+// Objects reused from an idempotent replay are not recorded; they belong to
+// an earlier, successful call.
+type UnitOfWork struct {
+	client  *Client
+	mu      sync.Mutex
+	entries []uowEntry
+}
+
+// RollbackReport describes what a rollback deleted.
+type RollbackReport struct {
+	DeletedObjects       int      `json:"deleted_objects"`
+	DeletedRelationships int      `json:"deleted_relationships"`
+	Failed               []string `json:"failed,omitempty"` // IDs that could not be deleted
+}
+
+// String returns a one-line summary of the rollback.
+func (r *RollbackReport) String() string {
+	msg := fmt.Sprintf("rolled back %d objects and %d relationships", r.DeletedObjects, r.DeletedRelationships)
+	if len(r.Failed) > 0 {
+		msg += fmt.Sprintf("; %d deletions failed, manual cleanup needed: %s", len(r.Failed), strings.Join(r.Failed, ", "))
+	}
+	return msg
+}
+
+// RollbackError is returned by UnitOfWork.Fail. It wraps the error that
+// aborted the operation and carries the rollback report.
+type RollbackError struct {
+	Err    error
+	Report *RollbackReport
+}
+
+func (e *RollbackError) Error() string {
+	return fmt.Sprintf("%v (%s)", e.Err, e.Report)
+}
+
+func (e *RollbackError) Unwrap() error { return e.Err }
+
+// NewUnitOfWork starts a unit of work that deletes through this client on rollback.
+func (c *Client) NewUnitOfWork() *UnitOfWork {
+	return &UnitOfWork{client: c}
+}
+
+// WithUnitOfWork returns a context that records creates into uow.
+func WithUnitOfWork(ctx context.Context, uow *UnitOfWork) context.Context {
+	return context.WithValue(ctx, unitOfWorkKey, uow)
+}
+
+// unitOfWorkFrom returns the UnitOfWork attached to the context, or nil.
+func unitOfWorkFrom(ctx context.Context) *UnitOfWork {
+	uow, _ := ctx.Value(unitOfWorkKey).(*UnitOfWork)
+	return uow
+}
+
+// record appends a created entity to the unit of work.
+func (u *UnitOfWork) record(kind, typ, id string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.entries = append(u.entries, uowEntry{kind: kind, typ: typ, id: id})
+}
+
+// Rollback deletes everything recorded so far in reverse creation order.
+// It keeps going past individual failures and reports them. Rollback runs
+// even if ctx has been cancelled, since a timeout is a common reason to
+// roll back in the first place.
+func (u *UnitOfWork) Rollback(ctx context.Context) *RollbackReport {
+	u.mu.Lock()
+	entries := u.entries
+	u.entries = nil
+	u.mu.Unlock()
+
+	ctx = context.WithoutCancel(ctx)
+	report := &RollbackReport{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		var err error
+		if e.kind == "relationship" {
+			err = u.client.DeleteRelationship(ctx, e.id)
+		} else {
+			err = u.client.DeleteObject(ctx, e.id)
+		}
+		if err != nil {
+			u.client.logger.Warn("rollback delete failed", "kind", e.kind, "type", e.typ, "id", e.id, "error", err)
+			report.Failed = append(report.Failed, e.id)
+			continue
+		}
+		if e.kind == "relationship" {
+			report.DeletedRelationships++
+		} else {
+			report.DeletedObjects++
+		}
+	}
+	u.client.logger.Info("unit of work rolled back",
+		"deleted_objects", report.DeletedObjects,
+		"deleted_relationships", report.DeletedRelationships,
+		"failed", len(report.Failed),
+	)
+	return report
+}
+
+// Fail rolls back the unit of work and returns err wrapped in a
+// RollbackError, so the caller's error message reports what was undone.
+func (u *UnitOfWork) Fail(ctx context.Context, err error) error {
+	return &RollbackError{Err: err, Report: u.Rollback(ctx)}
+}
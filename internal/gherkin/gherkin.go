@@ -0,0 +1,252 @@
+// Package gherkin reads and writes Gherkin .feature files for specs:
+//
+//	@tag
+//	Feature: <spec>
+//	  <purpose>
+//
+//	  @tag
+//	  Rule: <requirement>
+//	    <description>
+//
+//	    @tag
+//	    Scenario: <scenario>
+//	      Given <given>
+//	      When <when>
+//	      And <step>            (steps after When)
+//	      Then <then>
+//	      And <and_also>        (steps after Then)
+//
+// A scenario with variants is written as a Scenario Outline with one
+// Examples row per scenario, the base first; only the fields that differ
+// between them become <placeholders>. Background sections and step data
+// tables are not supported and are skipped when parsing.
+package gherkin
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Feature is one .feature file.
+type Feature struct {
+	Name        string
+	Description string
+	Tags        []string
+	Rules       []*Rule // scenarios outside any Rule go in a Rule with no name
+}
+
+// Rule groups the scenarios of one requirement.
+type Rule struct {
+	Name        string
+	Description string
+	Tags        []string
+	Scenarios   []*Scenario
+}
+
+// Scenario is a Scenario, or a Scenario Outline when it has variants.
+type Scenario struct {
+	Name     string
+	Tags     []string
+	Given    string // multiple Given/And lines are joined with newlines
+	When     string
+	Steps    []string // And/But lines between When and Then
+	Then     string
+	AndAlso  []string // And/But lines after Then
+	Variants []*Scenario
+}
+
+// Render writes a feature file.
+func Render(f *Feature) string {
+	var b strings.Builder
+	writeTags(&b, "", f.Tags)
+	fmt.Fprintf(&b, "Feature: %s\n", oneLine(f.Name))
+	writeDescription(&b, "  ", f.Description)
+	for _, r := range f.Rules {
+		indent := "  "
+		if r.Name != "" {
+			b.WriteString("\n")
+			writeTags(&b, "  ", r.Tags)
+			fmt.Fprintf(&b, "  Rule: %s\n", oneLine(r.Name))
+			writeDescription(&b, "    ", r.Description)
+			indent = "    "
+		}
+		for _, s := range r.Scenarios {
+			b.WriteString("\n")
+			writeScenario(&b, indent, s)
+		}
+	}
+	return b.String()
+}
+
+func writeTags(b *strings.Builder, indent string, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+	parts := make([]string, len(tags))
+	for i, t := range tags {
+		parts[i] = "@" + strings.Join(strings.Fields(t), "_")
+	}
+	b.WriteString(indent + strings.Join(parts, " ") + "\n")
+}
+
+func writeDescription(b *strings.Builder, indent, text string) {
+	if text = strings.TrimSpace(text); text == "" {
+		return
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(indent + strings.TrimSpace(line) + "\n")
+	}
+}
+
+// outline columns for the scenario fields, in table order.
+const (
+	colScenario = "scenario"
+	colGiven    = "given"
+	colWhen     = "when"
+	colThen     = "then"
+	colAndAlso  = "and_" // + 1-based index
+)
+
+func writeScenario(b *strings.Builder, indent string, s *Scenario) {
+	writeTags(b, indent, s.Tags)
+	if len(s.Variants) == 0 {
+		fmt.Fprintf(b, "%sScenario: %s\n", indent, oneLine(s.Name))
+		writeSteps(b, indent+"  ", s.Given, s.When, s.Steps, s.Then, s.AndAlso)
+		return
+	}
+
+	rows := append([]*Scenario{s}, s.Variants...)
+	differs := func(get func(*Scenario) string) bool {
+		for _, r := range rows[1:] {
+			if get(r) != get(rows[0]) {
+				return true
+			}
+		}
+		return false
+	}
+	nAnd := 0
+	for _, r := range rows {
+		nAnd = max(nAnd, len(r.AndAlso))
+	}
+	andAt := func(i int) func(*Scenario) string {
+		return func(r *Scenario) string {
+			if i < len(r.AndAlso) {
+				return r.AndAlso[i]
+			}
+			return ""
+		}
+	}
+
+	// Columns: the scenario name, then one per field that varies.
+	cols := []string{colScenario}
+	getters := []func(*Scenario) string{func(r *Scenario) string { return r.Name }}
+	field := func(col string, get func(*Scenario) string) string {
+		if !differs(get) {
+			return get(rows[0])
+		}
+		cols = append(cols, col)
+		getters = append(getters, get)
+		return "<" + col + ">"
+	}
+	given := field(colGiven, func(r *Scenario) string { return r.Given })
+	when := field(colWhen, func(r *Scenario) string { return r.When })
+	then := field(colThen, func(r *Scenario) string { return r.Then })
+	andAlso := make([]string, nAnd)
+	for i := range andAlso {
+		andAlso[i] = field(fmt.Sprintf("%s%d", colAndAlso, i+1), andAt(i))
+	}
+
+	fmt.Fprintf(b, "%sScenario Outline: %s\n", indent, oneLine(s.Name))
+	writeSteps(b, indent+"  ", given, when, s.Steps, then, andAlso)
+	fmt.Fprintf(b, "\n%s  Examples:\n", indent)
+	table := [][]string{cols}
+	for _, r := range rows {
+		row := make([]string, len(getters))
+		for i, get := range getters {
+			row[i] = escapeCell(get(r))
+		}
+		table = append(table, row)
+	}
+	writeTable(b, indent+"    ", table)
+}
+
+func writeSteps(b *strings.Builder, indent, given, when string, steps []string, then string, andAlso []string) {
+	kw := "Given"
+	for _, line := range splitLines(given) {
+		fmt.Fprintf(b, "%s%s %s\n", indent, kw, line)
+		kw = "And"
+	}
+	if when != "" {
+		fmt.Fprintf(b, "%sWhen %s\n", indent, oneLine(when))
+		for _, s := range steps {
+			fmt.Fprintf(b, "%sAnd %s\n", indent, oneLine(s))
+		}
+	}
+	if then != "" {
+		fmt.Fprintf(b, "%sThen %s\n", indent, oneLine(then))
+		for _, a := range andAlso {
+			if a != "" {
+				fmt.Fprintf(b, "%sAnd %s\n", indent, oneLine(a))
+			}
+		}
+	}
+}
+
+// writeTable writes an aligned Gherkin table.
+func writeTable(b *strings.Builder, indent string, rows [][]string) {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len([]rune(cell)))
+		}
+	}
+	for _, row := range rows {
+		b.WriteString(indent + "|")
+		for i, cell := range row {
+			b.WriteString(" " + cell + strings.Repeat(" ", widths[i]-len([]rune(cell))) + " |")
+		}
+		b.WriteString("\n")
+	}
+}
+
+// splitLines splits text into its non-empty lines.
+func splitLines(text string) []string {
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// oneLine folds text onto a single line.
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+var cellEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\n", `\n`)
+
+func escapeCell(s string) string { return cellEscaper.Replace(s) }
+
+func unescapeCell(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
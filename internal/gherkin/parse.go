@@ -0,0 +1,304 @@
+package gherkin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// step is one parsed step line.
+type step struct {
+	keyword string // Given, When, Then, or And (And, But, and * alike)
+	text    string
+}
+
+// pendingScenario is a scenario being read, before outline expansion.
+type pendingScenario struct {
+	name     string
+	tags     []string
+	outline  bool
+	steps    []step
+	header   []string   // current Examples header
+	examples [][]string // rows, keyed by the header they were read under
+	columns  [][]string // header for each row in examples
+}
+
+// Parse reads a .feature file.
+func Parse(doc string) (*Feature, error) {
+	var (
+		f       *Feature
+		rule    *Rule
+		sc      *pendingScenario
+		tags    []string
+		desc    *[]string // description lines being collected
+		docMark string    // closing delimiter of the doc string being skipped
+		inTable bool      // reading an Examples table
+	)
+	flush := func() {
+		if sc == nil {
+			return
+		}
+		if rule == nil {
+			rule = &Rule{}
+			f.Rules = append(f.Rules, rule)
+		}
+		rule.Scenarios = append(rule.Scenarios, sc.build())
+		sc = nil
+	}
+	endDescription := func() {
+		if desc == nil {
+			return
+		}
+		text := strings.TrimSpace(strings.Join(*desc, "\n"))
+		switch {
+		case rule != nil:
+			rule.Description = text
+		case f != nil:
+			f.Description = text
+		}
+		desc = nil
+	}
+
+	for n, raw := range strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n") {
+		line := strings.TrimSpace(raw)
+		if docMark != "" {
+			if line == docMark {
+				docMark = ""
+			}
+			continue
+		}
+		if desc != nil && !isKeywordLine(line) {
+			if !strings.HasPrefix(line, "#") {
+				*desc = append(*desc, line)
+			}
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") {
+			inTable = inTable && line != ""
+			continue
+		}
+		if strings.HasPrefix(line, `"""`) || strings.HasPrefix(line, "```") {
+			docMark = line[:3]
+			continue
+		}
+		if strings.HasPrefix(line, "|") {
+			if sc != nil && sc.outline && inTable {
+				sc.addRow(splitRow(line))
+			}
+			continue
+		}
+		inTable = false
+		if strings.HasPrefix(line, "@") {
+			for _, t := range strings.Fields(line) {
+				if t = strings.TrimPrefix(t, "@"); t != "" {
+					tags = append(tags, t)
+				}
+			}
+			continue
+		}
+
+		keyword, rest, isBlock := cutKeyword(line)
+		if isBlock {
+			endDescription()
+		}
+		switch keyword {
+		case "Feature":
+			if f != nil {
+				return nil, fmt.Errorf("line %d: more than one Feature", n+1)
+			}
+			f = &Feature{Name: rest, Tags: tags}
+			desc = &[]string{}
+		case "Rule", "Background", "Scenario", "Scenario Outline", "Examples":
+			if f == nil {
+				return nil, fmt.Errorf("line %d: %s before Feature", n+1, keyword)
+			}
+			switch keyword {
+			case "Rule":
+				flush()
+				rule = &Rule{Name: rest, Tags: tags}
+				f.Rules = append(f.Rules, rule)
+				desc = &[]string{}
+			case "Background":
+				flush() // with no open scenario, its steps are dropped
+			case "Scenario", "Scenario Outline":
+				flush()
+				sc = &pendingScenario{name: rest, tags: tags, outline: keyword == "Scenario Outline"}
+			case "Examples":
+				if sc == nil {
+					return nil, fmt.Errorf("line %d: Examples outside a Scenario", n+1)
+				}
+				sc.outline = true // newer Gherkin allows Examples under Scenario
+				sc.header = nil
+				inTable = true
+			}
+		case "Given", "When", "Then", "And":
+			if sc != nil {
+				sc.steps = append(sc.steps, step{keyword: keyword, text: rest})
+			}
+		}
+		// Anything else (scenario descriptions, Background text) is skipped.
+		tags = nil
+	}
+	endDescription()
+	flush()
+	if f == nil {
+		return nil, errors.New("no Feature found")
+	}
+	return f, nil
+}
+
+// cutKeyword splits a line into its Gherkin keyword and the rest. isBlock
+// reports keywords that end a description.
+func cutKeyword(line string) (keyword, rest string, isBlock bool) {
+	blocks := []struct{ prefix, keyword string }{
+		{"Feature:", "Feature"},
+		{"Rule:", "Rule"},
+		{"Background:", "Background"},
+		{"Scenario Outline:", "Scenario Outline"},
+		{"Scenario Template:", "Scenario Outline"},
+		{"Scenario:", "Scenario"},
+		{"Example:", "Scenario"},
+		{"Examples:", "Examples"},
+		{"Scenarios:", "Examples"},
+	}
+	for _, b := range blocks {
+		if r, ok := strings.CutPrefix(line, b.prefix); ok {
+			return b.keyword, strings.TrimSpace(r), true
+		}
+	}
+	steps := []struct{ prefix, keyword string }{
+		{"Given ", "Given"},
+		{"When ", "When"},
+		{"Then ", "Then"},
+		{"And ", "And"},
+		{"But ", "And"},
+		{"* ", "And"},
+	}
+	for _, s := range steps {
+		if r, ok := strings.CutPrefix(line, s.prefix); ok {
+			return s.keyword, strings.TrimSpace(r), false
+		}
+	}
+	return "", line, false
+}
+
+// isKeywordLine reports whether a line ends a free-form description.
+func isKeywordLine(line string) bool {
+	if strings.HasPrefix(line, "@") {
+		return true
+	}
+	_, _, isBlock := cutKeyword(line)
+	return isBlock
+}
+
+// splitRow splits a table row into unescaped cells.
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	var cells []string
+	var cur strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line):
+			cur.WriteByte(line[i])
+			cur.WriteByte(line[i+1])
+			i++
+		case line[i] == '|':
+			cells = append(cells, unescapeCell(strings.TrimSpace(cur.String())))
+			cur.Reset()
+		default:
+			cur.WriteByte(line[i])
+		}
+	}
+	return cells
+}
+
+// addRow records an Examples row; the first row of each table is its header.
+func (p *pendingScenario) addRow(cells []string) {
+	if p.header == nil {
+		p.header = cells
+		return
+	}
+	p.examples = append(p.examples, cells)
+	p.columns = append(p.columns, p.header)
+}
+
+// build turns the parsed steps into a Scenario. An outline becomes its first
+// Examples row, with the other rows as variants. Rows are named by their
+// scenario column, else after the outline and their position.
+func (p *pendingScenario) build() *Scenario {
+	if !p.outline || len(p.examples) == 0 {
+		sc := fromSteps(p.steps)
+		sc.Name, sc.Tags = p.name, p.tags
+		return sc
+	}
+	var base *Scenario
+	for i, row := range p.examples {
+		values := map[string]string{}
+		for j, col := range p.columns[i] {
+			if j < len(row) {
+				values[col] = row[j]
+			}
+		}
+		steps := make([]step, len(p.steps))
+		for j, s := range p.steps {
+			steps[j] = step{keyword: s.keyword, text: substitute(s.text, values)}
+		}
+		sc := fromSteps(steps)
+		sc.Name = values[colScenario]
+		if sc.Name == "" {
+			sc.Name = fmt.Sprintf("%s %d", p.name, i+1)
+		}
+		if base == nil {
+			sc.Tags = p.tags
+			base = sc
+			continue
+		}
+		sc.Steps = nil // steps belong to the base scenario
+		base.Variants = append(base.Variants, sc)
+	}
+	return base
+}
+
+// fromSteps assigns steps to scenario fields by position: Given lines and
+// the And lines after them to Given, the first When to When and the And
+// lines after it to Steps, the first Then to Then and the And lines after it
+// to AndAlso. Empty lines, left by empty Examples cells, are dropped.
+func fromSteps(steps []step) *Scenario {
+	sc := &Scenario{}
+	var given []string
+	phase := "Given"
+	for _, s := range steps {
+		if s.text == "" {
+			continue
+		}
+		kw := s.keyword
+		if kw == "And" {
+			kw = phase
+		} else {
+			phase = kw
+		}
+		switch {
+		case kw == "Given":
+			given = append(given, s.text)
+		case kw == "When" && sc.When == "":
+			sc.When = s.text
+		case kw == "When":
+			sc.Steps = append(sc.Steps, s.text)
+		case kw == "Then" && sc.Then == "":
+			sc.Then = s.text
+		default:
+			sc.AndAlso = append(sc.AndAlso, s.text)
+		}
+	}
+	sc.Given = strings.Join(given, "\n")
+	return sc
+}
+
+// substitute replaces <column> placeholders with row values.
+func substitute(text string, values map[string]string) string {
+	for col, v := range values {
+		text = strings.ReplaceAll(text, "<"+col+">", v)
+	}
+	return strings.TrimSpace(text)
+}
@@ -78,102 +78,17 @@ var KebabCaseName = NewGuardFunc("kebab_case_name", func(_ context.Context, gctx
 	)
 })
 
-// --- Artifact Workflow Guards ---
-// These guards enforce the Proposal → Spec → Design → Tasks dependency chain.
-// Each stage requires the previous stage's artifact to exist AND be marked ready.
-
-// ProposalBeforeSpec ensures a Proposal exists and is ready before Specs can be added.
-var ProposalBeforeSpec = NewGuardFunc("proposal_before_spec", func(_ context.Context, gctx *GuardContext) Result {
-	// Only applies to spec-related artifacts
-	switch gctx.ArtifactType {
-	case "spec", "requirement", "scenario", "scenario_step":
-	default:
-		return Pass("proposal_before_spec")
-	}
-	if !gctx.HasProposal {
-		return Fail("proposal_before_spec", HardBlock,
-			"Change must have a Proposal before adding Specs. The proposal captures the intent (why) before defining the specifications (what).",
-			"Add a proposal first using spec_artifact with artifact_type='proposal'.",
-		)
-	}
-	if !gctx.ProposalReady {
-		return Fail("proposal_before_spec", HardBlock,
-			"Proposal must be marked ready before adding Specs. Review the proposal and mark it ready using spec_mark_ready.",
-			"Use spec_mark_ready with the proposal's entity_id.",
-		)
-	}
-	return Pass("proposal_before_spec")
-})
-
-// SpecBeforeDesign ensures at least one Spec exists and all specs are ready before a Design can be added.
-var SpecBeforeDesign = NewGuardFunc("spec_before_design", func(_ context.Context, gctx *GuardContext) Result {
-	if gctx.ArtifactType != "design" {
-		return Pass("spec_before_design")
-	}
-	if !gctx.HasProposal {
-		return Fail("spec_before_design", HardBlock,
-			"Change must have a Proposal before adding a Design.",
-			"Add a proposal first using spec_artifact with artifact_type='proposal'.",
-		)
-	}
-	if !gctx.ProposalReady {
-		return Fail("spec_before_design", HardBlock,
-			"Proposal must be marked ready before adding a Design.",
-			"Use spec_mark_ready with the proposal's entity_id.",
-		)
-	}
-	if !gctx.HasSpec {
-		return Fail("spec_before_design", HardBlock,
-			"Change must have at least one Spec before adding a Design. Specs define what changes are needed; the design defines how.",
-			"Add specs first using spec_artifact with artifact_type='spec'.",
-		)
-	}
-	if !gctx.AllSpecsReady {
-		return Fail("spec_before_design", HardBlock,
-			"All Specs (including their Requirements and Scenarios) must be marked ready before adding a Design. This ensures the specifications are complete and reviewed.",
-			"Use spec_mark_ready to mark all specs, requirements, and scenarios as ready.",
-		)
-	}
-	return Pass("spec_before_design")
-})
-
-// DesignBeforeTasks ensures a Design exists and is ready before Tasks can be added.
-var DesignBeforeTasks = NewGuardFunc("design_before_tasks", func(_ context.Context, gctx *GuardContext) Result {
-	if gctx.ArtifactType != "task" {
-		return Pass("design_before_tasks")
-	}
-	if !gctx.HasDesign {
-		return Fail("design_before_tasks", HardBlock,
-			"Change must have a Design before adding Tasks. The design defines the technical approach; tasks break it into implementable steps.",
-			"Add a design first using spec_artifact with artifact_type='design'.",
-		)
-	}
-	if !gctx.DesignReady {
-		return Fail("design_before_tasks", HardBlock,
-			"Design must be marked ready before adding Tasks. Review the design and mark it ready using spec_mark_ready.",
-			"Use spec_mark_ready with the design's entity_id.",
-		)
-	}
-	return Pass("design_before_tasks")
-})
-
 // --- Archive Guards ---
 // These guards validate an archive operation.
 
-// ArtifactCompleteness checks that core artifacts (proposal, specs, design, tasks) exist before archiving.
+// ArtifactCompleteness checks that every required stage of the workflow
+// (by default proposal, specs, design, and tasks) has artifacts before archiving.
 var ArtifactCompleteness = NewGuardFunc("artifact_completeness", func(_ context.Context, gctx *GuardContext) Result {
 	var missing []string
-	if !gctx.HasProposal {
-		missing = append(missing, "proposal")
-	}
-	if !gctx.HasSpec {
-		missing = append(missing, "specs")
-	}
-	if !gctx.HasDesign {
-		missing = append(missing, "design")
-	}
-	if !gctx.HasTasks {
-		missing = append(missing, "tasks")
+	for _, s := range gctx.workflow().States(gctx) {
+		if s.Artifacts == 0 && !s.Optional {
+			missing = append(missing, s.Name)
+		}
 	}
 
 	if len(missing) == 0 {
@@ -181,7 +96,7 @@ var ArtifactCompleteness = NewGuardFunc("artifact_completeness", func(_ context.
 	}
 
 	return Fail("artifact_completeness", SoftBlock,
-		"Change is missing artifacts: "+joinComma(missing)+". Archiving an incomplete change may lose context about what was planned vs. implemented.",
+		"Change has no artifacts for stages: "+joinComma(missing)+". Archiving an incomplete change may lose context about what was planned vs. implemented.",
 		"Add the missing artifacts, or use force=true to archive anyway.",
 	)
 })
@@ -201,6 +116,23 @@ var TaskCompletionCheck = NewGuardFunc("task_completion", func(_ context.Context
 	)
 })
 
+// DependenciesArchived ensures the changes this one depends on are archived first.
+var DependenciesArchived = NewGuardFunc("dependencies_archived", func(_ context.Context, gctx *GuardContext) Result {
+	if len(gctx.UnarchivedDependencies) == 0 {
+		if len(gctx.MissingDependencies) > 0 {
+			return Fail("dependencies_archived", Warning,
+				"Change depends on changes that no longer exist: "+joinComma(gctx.MissingDependencies)+". The dependencies were deleted and are ignored.",
+				"No action needed unless the deleted changes held work this change builds on.",
+			)
+		}
+		return Pass("dependencies_archived")
+	}
+	return Fail("dependencies_archived", SoftBlock,
+		"Change depends on changes that are not archived yet: "+joinComma(gctx.UnarchivedDependencies)+". Archiving out of order records this change as current truth before the work it builds on.",
+		"Archive the dependencies first (spec_list_changes with order=dependency shows the order), or use force=true to archive anyway.",
+	)
+})
+
 // --- Guard Sets ---
 // Pre-built guard collections for each operation.
 
@@ -215,13 +147,13 @@ func NewChangeGuards() []Guard {
 	}
 }
 
-// ArtifactGuards returns the guards that run before adding an artifact.
-func ArtifactGuards() []Guard {
-	return []Guard{
-		ProposalBeforeSpec,
-		SpecBeforeDesign,
-		DesignBeforeTasks,
+// ArtifactGuards returns the guards that run before adding an artifact:
+// the stage ordering guards of w, or of the default workflow if w is nil.
+func ArtifactGuards(w *Workflow) []Guard {
+	if w == nil {
+		w = DefaultWorkflow()
 	}
+	return w.ArtifactGuards()
 }
 
 // ArchiveGuards returns the guards that run before archiving a change.
@@ -229,6 +161,7 @@ func ArchiveGuards() []Guard {
 	return []Guard{
 		ArtifactCompleteness,
 		TaskCompletionCheck,
+		DependenciesArchived,
 	}
 }
 
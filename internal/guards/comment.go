@@ -0,0 +1,37 @@
+package guards
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+)
+
+// --- Open Comments ---
+
+// PopulateCommentState fills OpenComments in gctx for an artifact.
+func PopulateCommentState(ctx context.Context, client *emergent.Client, gctx *GuardContext, artifact *graph.GraphObject) error {
+	comments, err := client.ListComments(ctx, artifact.ID)
+	if err != nil {
+		return err
+	}
+	gctx.OpenComments = emergent.OpenThreads(comments)
+	return nil
+}
+
+// OpenComments soft-blocks marking an artifact ready while comment threads
+// on it are unresolved.
+var OpenComments = NewGuardFunc("open_comments", func(_ context.Context, gctx *GuardContext) Result {
+	if gctx.OpenComments == 0 {
+		return Pass("open_comments")
+	}
+	artifact := gctx.ArtifactType
+	if artifact == "" {
+		artifact = "artifact"
+	}
+	return Fail("open_comments", SoftBlock,
+		fmt.Sprintf("%d unresolved comment thread(s) on this %s.", gctx.OpenComments, artifact),
+		"Address the feedback (spec_list_comments), resolve the threads with spec_resolve_comment, or use force=true to override.",
+	)
+})
@@ -67,6 +67,8 @@ type Outcome struct {
 	Blocked bool `json:"blocked"`
 	// Results contains all guard check results (both passed and failed).
 	Results []Result `json:"results"`
+	// Disabled lists guards the guard policy turned off.
+	Disabled []string `json:"disabled,omitempty"`
 }
 
 // HardBlocks returns all hard block results.
@@ -180,9 +182,17 @@ type GuardContext struct {
 	ChangeName string
 	// ArtifactType is the type of artifact being added (for spec_artifact).
 	ArtifactType string
+	// ArtifactTags are the tags of the artifact being added; a stage: tag
+	// puts it in that workflow stage.
+	ArtifactTags []string
 	// Force allows overriding soft blocks.
 	Force bool
 
+	// Policy overrides guard severities; Apps are the names of the Apps the
+	// change or artifact is scoped to, which select per-app overrides.
+	Policy *GuardPolicy
+	Apps   []string
+
 	// Graph state — populated by the runner before executing guards.
 	HasConstitution bool
 	HasPatterns     bool // At least one Pattern entity exists
@@ -200,6 +210,49 @@ type GuardContext struct {
 	PendingTasks    int  // Pending tasks for this change
 	ContextCount    int  // Number of Context entities in the project
 	ComponentCount  int  // Number of UIComponent entities in the project
+
+	UnarchivedDependencies []string // Names of Changes this change depends on that are not archived
+	MissingDependencies    []string // IDs of depends_on_change targets that no longer exist
+
+	// Workflow is the project's stage definition, and Artifacts the change's
+	// proposals, specs, designs, and tasks it is evaluated against.
+	Workflow  *Workflow
+	Artifacts []ChangeArtifact
+
+	// Requirement being marked ready — used by requirement_quality.
+	RequirementName        string
+	RequirementDescription string
+	RequirementStrength    string
+
+	// Reviews of the artifact being marked ready — used by review_approval.
+	// Each reviewer's latest decision on the current content counts.
+	Approvals        int
+	HumanApprovals   int
+	RejectedBy       []string
+	PendingReviewers []string
+
+	// Unresolved comment threads on the artifact being marked ready — used
+	// by open_comments.
+	OpenComments int
+
+	// Custom rules: the tags of a new change, the ID of the artifact being
+	// marked ready, the content of the artifact being added, and the loaded
+	// rules and the facts they are evaluated over (see RuleFacts), which are
+	// incomplete when FactsTruncated is set.
+	ChangeTags         []string
+	ArtifactID         string
+	ArtifactProperties map[string]any
+	Rules              []*Rule
+	Facts              map[string]any
+	FactsTruncated     bool
+}
+
+// workflow returns gctx.Workflow, or the default workflow if unset.
+func (gctx *GuardContext) workflow() *Workflow {
+	if gctx.Workflow == nil {
+		return DefaultWorkflow()
+	}
+	return gctx.Workflow
 }
 
 // GuardFunc is a function-based guard for simple checks.
@@ -243,11 +296,20 @@ func NewRunner() *Runner {
 }
 
 // Run executes the given guards against the context and returns an aggregated outcome.
+// The guard policy in gctx skips disabled guards and replaces the severity of
+// failed ones.
 func (r *Runner) Run(ctx context.Context, gctx *GuardContext, guards []Guard) *Outcome {
 	outcome := &Outcome{}
 
 	for _, g := range guards {
+		if !gctx.Policy.Enabled(g.Name(), gctx.Apps) {
+			outcome.Disabled = append(outcome.Disabled, g.Name())
+			continue
+		}
 		result := g.Check(ctx, gctx)
+		if !result.Passed {
+			result.Severity = gctx.Policy.Severity(result.GuardName, gctx.Apps, result.Severity)
+		}
 		outcome.Results = append(outcome.Results, result)
 
 		if !result.Passed {
@@ -0,0 +1,422 @@
+package guards
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// --- Requirement Quality Lint ---
+// The linter reads a Requirement's description and strength and flags wording
+// that cannot be verified: missing or contradictory RFC 2119 keywords, vague
+// terms, non-testable phrasing, and optionally text that does not follow an
+// EARS template. Each issue carries a suggested rewrite.
+
+// Lint rule identifiers.
+const (
+	LintMissingKeyword   = "missing_keyword"
+	LintLowercaseKeyword = "lowercase_keyword"
+	LintMissingStrength  = "missing_strength"
+	LintStrengthMismatch = "strength_mismatch"
+	LintMixedKeywords    = "mixed_keywords"
+	LintVagueTerm        = "vague_term"
+	LintUntestable       = "untestable"
+	LintEARS             = "ears_template"
+)
+
+// LintIssue is one problem found in a requirement's wording.
+type LintIssue struct {
+	Rule       string `json:"rule"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"` // rewritten description or concrete fix
+}
+
+// LintOptions configures the requirement quality linter.
+type LintOptions struct {
+	// Severity of the requirement_quality guard when any issue is found.
+	Severity Severity
+	// EARS requires descriptions to follow an EARS template.
+	EARS bool
+	// VagueTerms are flagged in addition to the built-in list. Surrounding
+	// whitespace is ignored and blank entries are skipped.
+	VagueTerms []string
+}
+
+// extraTerms returns the configured vague terms that are not blank, trimmed.
+func (o LintOptions) extraTerms() []string {
+	out := make([]string, 0, len(o.VagueTerms))
+	for _, term := range o.VagueTerms {
+		if term = strings.TrimSpace(term); term != "" {
+			out = append(out, term)
+		}
+	}
+	return out
+}
+
+// DefaultLintOptions returns the linter defaults: WARNING, no EARS check.
+func DefaultLintOptions() LintOptions {
+	return LintOptions{Severity: Warning}
+}
+
+// ParseSeverity reads a severity name: suggestion, warning, soft_block, or
+// hard_block (case-insensitive, hyphens allowed).
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), "-", "_") {
+	case "suggestion":
+		return Suggestion, nil
+	case "warning":
+		return Warning, nil
+	case "soft_block":
+		return SoftBlock, nil
+	case "hard_block":
+		return HardBlock, nil
+	default:
+		return Warning, fmt.Errorf("unknown severity %q (want suggestion, warning, soft_block, or hard_block)", s)
+	}
+}
+
+var (
+	// rfcKeywordRe matches RFC 2119 keywords as written in requirements.
+	rfcKeywordRe = regexp.MustCompile(`\b(MUST NOT|SHALL NOT|SHOULD NOT|MUST|SHALL|REQUIRED|SHOULD|RECOMMENDED|MAY|OPTIONAL)\b`)
+	// lowerKeywordRe matches the same keywords written in lowercase.
+	lowerKeywordRe = regexp.MustCompile(`\b(must not|shall not|should not|must|shall|should|may)\b`)
+	// earsRe matches the EARS templates: ubiquitous ("The <system> shall
+	// <response>"), event-driven (When), state-driven (While), unwanted
+	// behaviour (If ... then), optional feature (Where), and combinations.
+	earsRe = regexp.MustCompile(`(?i)^(?:(?:when|while|where|if)\b[^,]+,\s*(?:then\s+)?)*the\s+[^,]+?\s+(?:shall|must|should|may)\b(?:\s+not)?\s+\S`)
+)
+
+// vagueTerms maps terms that cannot be measured to the placeholder a rewrite
+// puts in their place. Words with common precise uses ("support", "large",
+// "secure") are left out; projects that want them add them as vague_terms.
+var vagueTerms = map[string]string{
+	"fast":             "within <N> ms",
+	"quick":            "within <N> ms",
+	"quickly":          "within <N> ms",
+	"slow":             "in more than <N> ms",
+	"responsive":       "responding within <N> ms",
+	"efficient":        "using at most <N> <resource>",
+	"efficiently":      "using at most <N> <resource>",
+	"scalable":         "for up to <N> <units>",
+	"user-friendly":    "<observable behavior>",
+	"easy":             "in at most <N> steps",
+	"easily":           "in at most <N> steps",
+	"simple":           "in at most <N> steps",
+	"intuitive":        "<observable behavior>",
+	"robust":           "<failure handling behavior>",
+	"reliable":         "with <N>% availability",
+	"flexible":         "<supported variations>",
+	"appropriate":      "<criterion>",
+	"appropriately":    "<criterion>",
+	"adequate":         "<criterion>",
+	"sufficient":       "<criterion>",
+	"reasonable":       "<criterion>",
+	"modern":           "<criterion>",
+	"seamless":         "<observable behavior>",
+	"seamlessly":       "<observable behavior>",
+	"high performance": "<throughput or latency target>",
+	"several":          "<N>",
+	"various":          "<listed cases>",
+	"etc.":             "<remaining cases>",
+	"and so on":        "<remaining cases>",
+	"among others":     "<remaining cases>",
+}
+
+// untestablePhrases maps phrasing that cannot be verified to the reason and
+// the replacement a rewrite uses ("" drops the phrase).
+var untestablePhrases = []struct {
+	phrase, reason, replacement string
+}{
+	{"as soon as possible", "has no deadline", "within <N> seconds"},
+	{"as much as possible", "has no target", "to at least <N>"},
+	{"as far as possible", "has no target", "to at least <N>"},
+	{"if possible", "leaves the behavior optional", ""},
+	{"where possible", "leaves the behavior optional", ""},
+	{"when possible", "leaves the behavior optional", ""},
+	{"where appropriate", "leaves the condition undefined", "when <condition>"},
+	{"if necessary", "leaves the condition undefined", "when <condition>"},
+	{"as needed", "leaves the condition undefined", "when <condition>"},
+	{"as required", "leaves the condition undefined", "when <condition>"},
+	{"and/or", "is ambiguous", "and"},
+	{"be able to", "describes a capability, not observable behavior", ""},
+	{"minimize", "has no target", "keep below <N>"},
+	{"maximize", "has no target", "reach at least <N>"},
+	{"optimize", "has no target", "reach <N>"},
+	{"tbd", "is unfinished", "<decided value>"},
+	{"todo", "is unfinished", "<decided value>"},
+}
+
+// LintRequirement checks a requirement's description and strength.
+func LintRequirement(description, strength string, opts LintOptions) []LintIssue {
+	description = strings.TrimSpace(description)
+	strength = normalizeStrength(strength)
+	if description == "" {
+		return []LintIssue{{
+			Rule:       LintMissingKeyword,
+			Message:    "Requirement has no description.",
+			Suggestion: fmt.Sprintf("The <system> %s <observable behavior>.", keywordFor(strength)),
+		}}
+	}
+
+	var issues []LintIssue
+
+	// RFC 2119 keywords against strength.
+	keywords := rfcKeywordRe.FindAllString(description, -1)
+	implied := ""
+	levels := map[string]bool{}
+	for _, k := range keywords {
+		levels[normalizeStrength(k)] = true
+	}
+	if len(keywords) > 0 {
+		implied = normalizeStrength(keywords[0])
+	}
+	switch {
+	case len(keywords) == 0 && lowerKeywordRe.MatchString(description):
+		lower := lowerKeywordRe.FindString(description)
+		issues = append(issues, LintIssue{
+			Rule:       LintLowercaseKeyword,
+			Message:    fmt.Sprintf("Keyword %q is lowercase; RFC 2119 keywords are written in capitals so the obligation is unambiguous.", lower),
+			Suggestion: replaceFirstWord(description, lower, strings.ToUpper(lower)),
+		})
+		implied = normalizeStrength(strings.ToUpper(lower))
+	case len(keywords) == 0:
+		issues = append(issues, LintIssue{
+			Rule:       LintMissingKeyword,
+			Message:    "Description has no RFC 2119 keyword (MUST, SHALL, SHOULD, MAY), so its obligation is unclear.",
+			Suggestion: fmt.Sprintf("The <system> %s %s", keywordFor(strength), lowerFirst(description)),
+		})
+	}
+	if len(levels) > 1 {
+		issues = append(issues, LintIssue{
+			Rule:       LintMixedKeywords,
+			Message:    fmt.Sprintf("Description mixes obligation levels (%s); a requirement should state one.", strings.Join(sortedKeys(levels), ", ")),
+			Suggestion: "Split it into one requirement per obligation level.",
+		})
+	}
+	switch {
+	case strength == "" && implied != "":
+		issues = append(issues, LintIssue{
+			Rule:       LintMissingStrength,
+			Message:    "Requirement has no strength.",
+			Suggestion: fmt.Sprintf("Set strength to %s to match the description.", implied),
+		})
+	case strength == "":
+		issues = append(issues, LintIssue{
+			Rule:       LintMissingStrength,
+			Message:    "Requirement has no strength.",
+			Suggestion: "Set strength to MUST, SHOULD, or MAY.",
+		})
+	case !validStrength(strength):
+		issues = append(issues, LintIssue{
+			Rule:       LintMissingStrength,
+			Message:    fmt.Sprintf("Strength %q is not an RFC 2119 level.", strength),
+			Suggestion: "Set strength to MUST, SHOULD, or MAY.",
+		})
+	case implied != "" && implied != strength:
+		kw := keywords
+		if len(kw) == 0 {
+			kw = []string{lowerKeywordRe.FindString(description)}
+		}
+		issues = append(issues, LintIssue{
+			Rule:       LintStrengthMismatch,
+			Message:    fmt.Sprintf("Description says %s but strength is %s.", kw[0], strength),
+			Suggestion: replaceFirstWord(description, kw[0], keywordFor(strength)),
+		})
+	}
+
+	// Vague terms.
+	extra := opts.extraTerms()
+	terms := make([]string, 0, len(vagueTerms)+len(extra))
+	for term := range vagueTerms {
+		terms = append(terms, term)
+	}
+	terms = append(terms, extra...)
+	sort.Strings(terms)
+	for _, term := range terms {
+		if !containsPhrase(description, term) {
+			continue
+		}
+		replacement, ok := vagueTerms[strings.ToLower(term)]
+		if !ok {
+			replacement = "<measurable criterion>"
+		}
+		issues = append(issues, LintIssue{
+			Rule:       LintVagueTerm,
+			Message:    fmt.Sprintf("%q is vague; replace it with a measurable criterion.", term),
+			Suggestion: replacePhrase(description, term, replacement),
+		})
+	}
+
+	// Non-testable phrasing.
+	for _, u := range untestablePhrases {
+		if !containsPhrase(description, u.phrase) {
+			continue
+		}
+		issues = append(issues, LintIssue{
+			Rule:       LintUntestable,
+			Message:    fmt.Sprintf("%q %s, so no scenario can verify it.", u.phrase, u.reason),
+			Suggestion: replacePhrase(description, u.phrase, u.replacement),
+		})
+	}
+
+	// EARS template conformance.
+	if opts.EARS && !earsRe.MatchString(description) {
+		issues = append(issues, LintIssue{
+			Rule:    LintEARS,
+			Message: "Description does not follow an EARS template.",
+			Suggestion: fmt.Sprintf("The <system> %[1]s <response>. | When <trigger>, the <system> %[1]s <response>. | While <state>, the <system> %[1]s <response>. | If <unwanted condition>, then the <system> %[1]s <response>. | Where <feature is included>, the <system> %[1]s <response>.",
+				keywordFor(strength)),
+		})
+	}
+
+	return issues
+}
+
+// SuggestRewrite applies every wording fix the linter knows to a description:
+// the keyword is capitalized and made to match strength (or added), and vague
+// terms and non-testable phrases are replaced with placeholders to fill in.
+// It returns the description unchanged when there is nothing to fix.
+func SuggestRewrite(description, strength string, opts LintOptions) string {
+	out := strings.TrimSpace(description)
+	strength = normalizeStrength(strength)
+	if out == "" {
+		return ""
+	}
+
+	if kw := rfcKeywordRe.FindString(out); kw != "" {
+		if validStrength(strength) && normalizeStrength(kw) != strength {
+			out = replaceFirstWord(out, kw, keywordFor(strength))
+		}
+	} else if lower := lowerKeywordRe.FindString(out); lower != "" {
+		replacement := strings.ToUpper(lower)
+		if validStrength(strength) {
+			replacement = strength
+		}
+		out = replaceFirstWord(out, lower, replacement)
+	} else {
+		out = fmt.Sprintf("The <system> %s %s", keywordFor(strength), lowerFirst(out))
+	}
+
+	for term, replacement := range vagueTerms {
+		out = replacePhrase(out, term, replacement)
+	}
+	for _, term := range opts.extraTerms() {
+		if _, builtin := vagueTerms[strings.ToLower(term)]; !builtin {
+			out = replacePhrase(out, term, "<measurable criterion>")
+		}
+	}
+	for _, u := range untestablePhrases {
+		out = replacePhrase(out, u.phrase, u.replacement)
+	}
+	return out
+}
+
+// RequirementQuality returns the requirement_quality guard, which fails with
+// the configured severity when the requirement in gctx has lint issues.
+func RequirementQuality(opts LintOptions) Guard {
+	return NewGuardFunc("requirement_quality", func(_ context.Context, gctx *GuardContext) Result {
+		if gctx.ArtifactType != "requirement" {
+			return Pass("requirement_quality")
+		}
+		issues := LintRequirement(gctx.RequirementDescription, gctx.RequirementStrength, opts)
+		if len(issues) == 0 {
+			return Pass("requirement_quality")
+		}
+		messages := make([]string, len(issues))
+		for i, issue := range issues {
+			messages[i] = issue.Message
+		}
+		return Fail("requirement_quality", opts.Severity,
+			fmt.Sprintf("Requirement %q has %d wording issue(s): %s", gctx.RequirementName, len(issues), joinStrings(messages, " ")),
+			fmt.Sprintf("Fix the description or strength with spec_update_artifact, e.g. %q.", SuggestRewrite(gctx.RequirementDescription, gctx.RequirementStrength, opts)),
+		)
+	})
+}
+
+// normalizeStrength maps an RFC 2119 keyword or strength to MUST, SHOULD, or
+// MAY. Other values are returned upper-cased.
+func normalizeStrength(s string) string {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	switch {
+	case s == "MUST" || s == "SHALL" || s == "REQUIRED" || strings.HasPrefix(s, "MUST ") || strings.HasPrefix(s, "SHALL "):
+		return "MUST"
+	case s == "SHOULD" || s == "RECOMMENDED" || strings.HasPrefix(s, "SHOULD "):
+		return "SHOULD"
+	case s == "MAY" || s == "OPTIONAL":
+		return "MAY"
+	}
+	return s
+}
+
+func validStrength(s string) bool {
+	return s == "MUST" || s == "SHOULD" || s == "MAY"
+}
+
+// keywordFor returns the keyword a rewrite uses for a strength.
+func keywordFor(strength string) string {
+	if validStrength(strength) {
+		return strength
+	}
+	return "MUST"
+}
+
+// containsPhrase reports whether text contains phrase as whole words,
+// ignoring case.
+func containsPhrase(text, phrase string) bool {
+	return phraseRe(phrase).MatchString(text)
+}
+
+// replacePhrase replaces every whole-word occurrence of phrase, ignoring
+// case, and tidies the spacing left behind.
+func replacePhrase(text, phrase, replacement string) string {
+	out := phraseRe(phrase).ReplaceAllLiteralString(text, replacement)
+	out = strings.Join(strings.Fields(out), " ")
+	return strings.NewReplacer(" ,", ",", " .", ".").Replace(out)
+}
+
+// replaceFirstWord replaces the first whole-word occurrence of word.
+func replaceFirstWord(text, word, replacement string) string {
+	loc := phraseRe(word).FindStringIndex(text)
+	if loc == nil {
+		return text
+	}
+	return text[:loc[0]] + replacement + text[loc[1]:]
+}
+
+// phraseRes caches the compiled pattern of each phrase phraseRe is asked
+// for: the built-in lists, configured terms, and keywords.
+var phraseRes sync.Map // phrase → *regexp.Regexp
+
+// phraseRe matches phrase as whole words. Phrases ending in punctuation
+// ("etc.") only need a word boundary at the start. phrase must not be empty.
+func phraseRe(phrase string) *regexp.Regexp {
+	if re, ok := phraseRes.Load(phrase); ok {
+		return re.(*regexp.Regexp)
+	}
+	pattern := `(?i)\b` + regexp.QuoteMeta(phrase)
+	if last := phrase[len(phrase)-1]; last >= 'a' && last <= 'z' || last >= 'A' && last <= 'Z' || last >= '0' && last <= '9' {
+		pattern += `\b`
+	}
+	re, _ := phraseRes.LoadOrStore(phrase, regexp.MustCompile(pattern))
+	return re.(*regexp.Regexp)
+}
+
+// lowerFirst lower-cases the first letter unless it starts an acronym.
+func lowerFirst(s string) string {
+	if len(s) < 2 || strings.ToUpper(s[1:2]) == s[1:2] {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,174 @@
+package guards
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLintRequirement(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		strength    string
+		opts        LintOptions
+		want        []string // rules, in order
+	}{
+		{
+			name:        "clean",
+			description: "The system MUST reject expired tokens.",
+			strength:    "MUST",
+		},
+		{
+			name:        "lowercase keyword",
+			description: "The system must reject expired tokens.",
+			strength:    "MUST",
+			want:        []string{LintLowercaseKeyword},
+		},
+		{
+			name:        "no keyword or strength",
+			description: "Reject expired tokens.",
+			want:        []string{LintMissingKeyword, LintMissingStrength},
+		},
+		{
+			name:     "empty description",
+			strength: "SHOULD",
+			want:     []string{LintMissingKeyword},
+		},
+		{
+			name:        "strength not an RFC 2119 level",
+			description: "The system MUST reject expired tokens.",
+			strength:    "critical",
+			want:        []string{LintMissingStrength},
+		},
+		{
+			name:        "strength mismatch and vague term",
+			description: "The system SHOULD respond fast.",
+			strength:    "MUST",
+			want:        []string{LintStrengthMismatch, LintVagueTerm},
+		},
+		{
+			name:        "mixed keywords",
+			description: "The system MUST log in users and MAY cache sessions.",
+			strength:    "MUST",
+			want:        []string{LintMixedKeywords},
+		},
+		{
+			name:        "untestable phrase",
+			description: "The API MUST return results as soon as possible.",
+			strength:    "MUST",
+			want:        []string{LintUntestable},
+		},
+		{
+			name:        "words with precise uses are not vague",
+			description: "The system MUST support SSO and keep large files secure for many users.",
+			strength:    "MUST",
+		},
+		{
+			name:        "configured vague term, blank entries skipped",
+			description: "The UI MUST feel snappy.",
+			strength:    "MUST",
+			opts:        LintOptions{VagueTerms: []string{"", "  ", " snappy "}},
+			want:        []string{LintVagueTerm},
+		},
+		{
+			name:        "not EARS",
+			description: "Users MUST log in.",
+			strength:    "MUST",
+			opts:        LintOptions{EARS: true},
+			want:        []string{LintEARS},
+		},
+		{
+			name:        "event-driven EARS",
+			description: "When a user logs in, the system SHALL record the time.",
+			strength:    "MUST",
+			opts:        LintOptions{EARS: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, issue := range LintRequirement(tt.description, tt.strength, tt.opts) {
+				got = append(got, issue.Rule)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("LintRequirement(%q, %q) rules = %v, want %v", tt.description, tt.strength, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestRewrite(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		strength    string
+		opts        LintOptions
+		want        string
+	}{
+		{
+			name:        "nothing to fix",
+			description: "The system MUST reject expired tokens.",
+			strength:    "MUST",
+			want:        "The system MUST reject expired tokens.",
+		},
+		{
+			name:        "capitalizes keyword",
+			description: "The system must reject expired tokens.",
+			strength:    "MUST",
+			want:        "The system MUST reject expired tokens.",
+		},
+		{
+			name:        "lowercase keyword takes strength",
+			description: "The system may cache results.",
+			strength:    "SHOULD",
+			want:        "The system SHOULD cache results.",
+		},
+		{
+			name:        "adds keyword",
+			description: "Reject expired tokens.",
+			want:        "The <system> MUST reject expired tokens.",
+		},
+		{
+			name:        "matches strength and replaces vague term",
+			description: "The system SHOULD respond fast.",
+			strength:    "MUST",
+			want:        "The system MUST respond within <N> ms.",
+		},
+		{
+			name:        "replaces untestable phrase",
+			description: "The API MUST return results as soon as possible.",
+			strength:    "MUST",
+			want:        "The API MUST return results within <N> seconds.",
+		},
+		{
+			name:        "drops optional phrase",
+			description: "The system MUST retry failed uploads if possible.",
+			strength:    "MUST",
+			want:        "The system MUST retry failed uploads.",
+		},
+		{
+			name:        "configured vague term",
+			description: "The UI MUST feel snappy.",
+			strength:    "MUST",
+			opts:        LintOptions{VagueTerms: []string{"", "snappy"}},
+			want:        "The UI MUST feel <measurable criterion>.",
+		},
+		{
+			name:        "keeps words with precise uses",
+			description: "The system MUST support SSO for large files.",
+			strength:    "MUST",
+			want:        "The system MUST support SSO for large files.",
+		},
+		{
+			name: "empty",
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestRewrite(tt.description, tt.strength, tt.opts); got != tt.want {
+				t.Errorf("SuggestRewrite(%q, %q) = %q, want %q", tt.description, tt.strength, got, tt.want)
+			}
+		})
+	}
+}
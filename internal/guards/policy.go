@@ -0,0 +1,335 @@
+package guards
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+)
+
+// --- Guard Policy ---
+// A guard policy overrides the compiled-in severity of guards, or turns them
+// off, e.g. "patterns_seeded: warning" or "context_discovery@billing-api: off".
+// Overrides come from config and from the Constitution's guard_policy, which
+// wins per guard and app. An override naming an app applies to changes and
+// artifacts scoped to that app (scoped_to_app).
+
+// guardNameRegex matches guard names: lowercase words joined by underscores.
+var guardNameRegex = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// GuardOverride replaces the severity of one guard, optionally for one app.
+type GuardOverride struct {
+	Guard    string   `json:"guard"`
+	App      string   `json:"app,omitempty"` // empty applies to everything
+	Severity Severity `json:"severity"`
+	Disabled bool     `json:"disabled,omitempty"`
+}
+
+func (o GuardOverride) String() string {
+	s := o.Guard
+	if o.App != "" {
+		s += "@" + o.App
+	}
+	if o.Disabled {
+		return s + ": off"
+	}
+	return s + ": " + strings.ToLower(o.Severity.String())
+}
+
+// GuardPolicy is a set of overrides, at most one per guard and app.
+type GuardPolicy struct {
+	Overrides []GuardOverride `json:"overrides"`
+}
+
+// ParseGuardOverride reads an override of the form
+// "<guard>[@<app>]: <severity|off>".
+func ParseGuardOverride(s string) (GuardOverride, error) {
+	target, value, ok := strings.Cut(s, ":")
+	value = strings.TrimSpace(value)
+	if !ok || value == "" {
+		return GuardOverride{}, fmt.Errorf("invalid guard override %q (want \"<guard>[@<app>]: <severity|off>\")", s)
+	}
+	name, app, scoped := strings.Cut(strings.TrimSpace(target), "@")
+	name, app = strings.TrimSpace(name), strings.TrimSpace(app)
+	if !guardNameRegex.MatchString(name) {
+		return GuardOverride{}, fmt.Errorf("invalid guard override %q: %q is not a guard name", s, name)
+	}
+	if scoped && app == "" {
+		return GuardOverride{}, fmt.Errorf("invalid guard override %q: missing app name after @", s)
+	}
+	o := GuardOverride{Guard: name, App: app}
+	if strings.EqualFold(value, "off") {
+		o.Disabled = true
+		return o, nil
+	}
+	sev, err := ParseSeverity(value)
+	if err != nil {
+		return GuardOverride{}, fmt.Errorf("invalid guard override %q: %w", s, err)
+	}
+	o.Severity = sev
+	return o, nil
+}
+
+// ParseGuardPolicy reads a list of overrides. Later overrides for the same
+// guard and app replace earlier ones.
+func ParseGuardPolicy(overrides []string) (*GuardPolicy, error) {
+	p := &GuardPolicy{}
+	for _, s := range overrides {
+		o, err := ParseGuardOverride(s)
+		if err != nil {
+			return nil, err
+		}
+		p = p.With(o)
+	}
+	return p, nil
+}
+
+// With returns the policy with o replacing any override for the same guard
+// and app.
+func (p *GuardPolicy) With(o GuardOverride) *GuardPolicy {
+	out := &GuardPolicy{}
+	if p != nil {
+		for _, other := range p.Overrides {
+			if other.Guard != o.Guard || !strings.EqualFold(other.App, o.App) {
+				out.Overrides = append(out.Overrides, other)
+			}
+		}
+	}
+	out.Overrides = append(out.Overrides, o)
+	return out
+}
+
+// CheckGuardNames returns an error for the first override naming neither a
+// built-in guard of w nor one of rules, so a misspelt guard (e.g.
+// "patterns_seede: off") is reported instead of silently ignored.
+func (p *GuardPolicy) CheckGuardNames(w *Workflow, rules []*Rule) error {
+	if p == nil {
+		return nil
+	}
+	known := make(map[string]bool)
+	for _, g := range BuiltinGuards(w) {
+		known[g.Name] = true
+	}
+	for _, r := range rules {
+		known[r.Name] = true
+	}
+	for _, o := range p.Overrides {
+		if !known[o.Guard] {
+			return fmt.Errorf("invalid guard override %q: unknown guard %q (specmcp://guardrails lists the guards)", o.String(), o.Guard)
+		}
+	}
+	return nil
+}
+
+// CheckRelaxations returns an error for the first override that turns off or
+// downgrades a guard that hard-blocks by default in w, e.g.
+// "review_approval: off". Constitution overrides may only relax the softer
+// guards; the hard blocks are relaxed in config, by whoever runs the server.
+func (p *GuardPolicy) CheckRelaxations(w *Workflow) error {
+	if p == nil {
+		return nil
+	}
+	hard := make(map[string]bool)
+	for _, g := range BuiltinGuards(w) {
+		if g.Severity == HardBlock {
+			hard[g.Name] = true
+		}
+	}
+	for _, o := range p.Overrides {
+		if hard[o.Guard] && (o.Disabled || o.Severity < HardBlock) {
+			return fmt.Errorf("invalid guard override %q: %s is a hard block and can only be relaxed in the server config", o.String(), o.Guard)
+		}
+	}
+	return nil
+}
+
+// lookup returns the override of guard for app: the app's own, else the
+// global one.
+func (p *GuardPolicy) lookup(guard, app string) (GuardOverride, bool) {
+	var global GuardOverride
+	found := false
+	for _, o := range p.Overrides {
+		if o.Guard != guard {
+			continue
+		}
+		if o.App == "" {
+			global, found = o, true
+		} else if app != "" && strings.EqualFold(o.App, app) {
+			return o, true
+		}
+	}
+	return global, found
+}
+
+// resolve returns the severity of guard for each of apps (or globally when
+// there are none), given its compiled-in severity, and whether it is enabled
+// for any of them. Across apps the strictest severity wins.
+func (p *GuardPolicy) resolve(guard string, apps []string, sev Severity) (Severity, bool) {
+	if p == nil {
+		return sev, true
+	}
+	scopes := apps
+	if len(scopes) == 0 {
+		scopes = []string{""}
+	}
+	out, enabled := sev, false
+	for _, app := range scopes {
+		s, on := sev, true
+		if o, ok := p.lookup(guard, app); ok {
+			s, on = o.Severity, !o.Disabled
+		}
+		if on && (!enabled || s > out) {
+			out, enabled = s, true
+		}
+	}
+	return out, enabled
+}
+
+// Enabled reports whether guard runs for a change or artifact scoped to apps.
+// A guard is skipped only when it is off for every app.
+func (p *GuardPolicy) Enabled(guard string, apps []string) bool {
+	_, on := p.resolve(guard, apps, Suggestion)
+	return on
+}
+
+// Severity returns the effective severity of a failed guard whose compiled-in
+// severity is sev.
+func (p *GuardPolicy) Severity(guard string, apps []string, sev Severity) Severity {
+	s, _ := p.resolve(guard, apps, sev)
+	return s
+}
+
+// LoadGuardPolicy returns the configured overrides (see
+// emergent.ClientFactory.SetGuardPolicy) with the guard_policy of the
+// project's Constitutions on top. Invalid constitution overrides, including
+// ones relaxing a hard block (see CheckRelaxations), are returned as an error.
+func LoadGuardPolicy(ctx context.Context, client *emergent.Client) (*GuardPolicy, error) {
+	policy, err := ParseGuardPolicy(client.GuardPolicy())
+	if err != nil {
+		return nil, fmt.Errorf("guard overrides: %w", err)
+	}
+	constitutions, _, err := client.ListAllObjects(ctx, &graph.ListObjectsOptions{Type: emergent.TypeConstitution}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing constitutions: %w", err)
+	}
+	var overlay GuardPolicy
+	for _, obj := range constitutions {
+		raw, _ := obj.Properties["guard_policy"].([]any)
+		for _, v := range raw {
+			s, _ := v.(string)
+			o, err := ParseGuardOverride(s)
+			if err != nil {
+				return nil, fmt.Errorf("constitution guard_policy: %w", err)
+			}
+			overlay.Overrides = append(overlay.Overrides, o)
+		}
+	}
+	if len(overlay.Overrides) == 0 {
+		return policy, nil
+	}
+	w, err := workflowOf(client, constitutions)
+	if err != nil {
+		return nil, err
+	}
+	if err := overlay.CheckRelaxations(w); err != nil {
+		return nil, fmt.Errorf("constitution guard_policy: %w", err)
+	}
+	for _, o := range overlay.Overrides {
+		policy = policy.With(o)
+	}
+	return policy, nil
+}
+
+// PopulatePolicyState loads gctx.Policy if unset and adds the names of the
+// Apps entityID is scoped to (scoped_to_app) to gctx.Apps. entityID may be
+// empty.
+func PopulatePolicyState(ctx context.Context, client *emergent.Client, gctx *GuardContext, entityID string) error {
+	if gctx.Policy == nil {
+		policy, err := LoadGuardPolicy(ctx, client)
+		if err != nil {
+			return err
+		}
+		gctx.Policy = policy
+	}
+	if entityID == "" {
+		return nil
+	}
+	edges, err := client.GetObjectEdges(ctx, entityID, &graph.GetObjectEdgesOptions{
+		Types:     []string{emergent.RelScopedToApp},
+		Direction: "outgoing",
+	})
+	if err != nil {
+		return fmt.Errorf("getting apps of %s: %w", entityID, err)
+	}
+	if len(edges.Outgoing) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(edges.Outgoing))
+	for _, rel := range edges.Outgoing {
+		ids = append(ids, rel.DstID)
+	}
+	apps, err := client.GetObjects(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("getting apps of %s: %w", entityID, err)
+	}
+	for _, app := range apps {
+		name, _ := app.Properties["name"].(string)
+		if name == "" && app.Key != nil {
+			name = *app.Key
+		}
+		if name != "" && !containsFold(gctx.Apps, name) {
+			gctx.Apps = append(gctx.Apps, name)
+		}
+	}
+	return nil
+}
+
+func containsFold(ss []string, s string) bool {
+	for _, v := range ss {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// GuardInfo describes a built-in guard and its compiled-in severity.
+type GuardInfo struct {
+	Name     string   `json:"name"`
+	RunsOn   string   `json:"runs_on"`
+	Severity Severity `json:"severity"`
+	// ConfiguredBy names the config setting that replaces the compiled-in
+	// severity, if any.
+	ConfiguredBy string `json:"configured_by,omitempty"`
+}
+
+// BuiltinGuards lists the built-in guards, with the stage guards of w (or of
+// the default workflow if w is nil).
+func BuiltinGuards(w *Workflow) []GuardInfo {
+	if w == nil {
+		w = DefaultWorkflow()
+	}
+	out := []GuardInfo{
+		{Name: "kebab_case_name", RunsOn: "spec_new", Severity: HardBlock},
+		{Name: "constitution_required", RunsOn: "spec_new", Severity: HardBlock},
+		{Name: "patterns_seeded", RunsOn: "spec_new", Severity: SoftBlock},
+		{Name: "context_discovery", RunsOn: "spec_new", Severity: Suggestion},
+		{Name: "component_discovery", RunsOn: "spec_new", Severity: Suggestion},
+	}
+	for _, st := range w.Stages {
+		if len(st.Requires) > 0 {
+			out = append(out, GuardInfo{Name: st.GuardName(), RunsOn: "spec_artifact, spec_generate_tasks", Severity: HardBlock})
+		}
+	}
+	return append(out,
+		GuardInfo{Name: "artifact_completeness", RunsOn: "spec_archive", Severity: SoftBlock},
+		GuardInfo{Name: "task_completion", RunsOn: "spec_archive", Severity: SoftBlock},
+		GuardInfo{Name: "dependencies_archived", RunsOn: "spec_archive", Severity: SoftBlock},
+		GuardInfo{Name: "requirement_quality", RunsOn: "spec_mark_ready", Severity: Warning, ConfiguredBy: "lint.severity"},
+		GuardInfo{Name: "review_approval", RunsOn: "spec_mark_ready", Severity: HardBlock, ConfiguredBy: "review.severity"},
+		GuardInfo{Name: "open_comments", RunsOn: "spec_mark_ready", Severity: SoftBlock},
+	)
+}
@@ -0,0 +1,110 @@
+package guards
+
+import "testing"
+
+func TestParseGuardOverride(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    GuardOverride
+		wantErr bool
+	}{
+		{in: "patterns_seeded: warning", want: GuardOverride{Guard: "patterns_seeded", Severity: Warning}},
+		{in: "patterns_seeded:soft-block", want: GuardOverride{Guard: "patterns_seeded", Severity: SoftBlock}},
+		{in: "context_discovery@billing-api: off", want: GuardOverride{Guard: "context_discovery", App: "billing-api", Disabled: true}},
+		{in: " context_discovery @ billing-api : HARD_BLOCK ", want: GuardOverride{Guard: "context_discovery", App: "billing-api", Severity: HardBlock}},
+		{in: "patterns_seeded", wantErr: true},
+		{in: "patterns_seeded:", wantErr: true},
+		{in: "Patterns-Seeded: warning", wantErr: true},
+		{in: "patterns_seeded@: warning", wantErr: true},
+		{in: "patterns_seeded: loud", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseGuardOverride(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseGuardOverride(%q) = %+v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGuardOverride(%q): %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseGuardOverride(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuardPolicyResolve(t *testing.T) {
+	policy, err := ParseGuardPolicy([]string{
+		"patterns_seeded: warning",
+		"patterns_seeded@billing-api: hard_block",
+		"patterns_seeded@docs: off",
+		"context_discovery@docs: off",
+		"context_discovery@web: off",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		policy      *GuardPolicy
+		guard       string
+		apps        []string
+		want        Severity
+		wantEnabled bool
+	}{
+		{name: "nil policy", guard: "patterns_seeded", want: SoftBlock, wantEnabled: true},
+		{name: "no override", policy: policy, guard: "task_completion", want: SoftBlock, wantEnabled: true},
+		{name: "global", policy: policy, guard: "patterns_seeded", want: Warning, wantEnabled: true},
+		{name: "app without override falls back to global", policy: policy, guard: "patterns_seeded", apps: []string{"web"}, want: Warning, wantEnabled: true},
+		{name: "app override", policy: policy, guard: "patterns_seeded", apps: []string{"billing-api"}, want: HardBlock, wantEnabled: true},
+		{name: "app name is case-insensitive", policy: policy, guard: "patterns_seeded", apps: []string{"Billing-API"}, want: HardBlock, wantEnabled: true},
+		{name: "off for the only app", policy: policy, guard: "patterns_seeded", apps: []string{"docs"}, want: SoftBlock},
+		{name: "off for one of several apps", policy: policy, guard: "patterns_seeded", apps: []string{"docs", "web"}, want: Warning, wantEnabled: true},
+		{name: "strictest app wins", policy: policy, guard: "patterns_seeded", apps: []string{"web", "billing-api"}, want: HardBlock, wantEnabled: true},
+		{name: "off for every app", policy: policy, guard: "context_discovery", apps: []string{"docs", "web"}, want: SoftBlock},
+		{name: "app override does not apply globally", policy: policy, guard: "context_discovery", want: SoftBlock, wantEnabled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, enabled := tt.policy.resolve(tt.guard, tt.apps, SoftBlock)
+			if enabled != tt.wantEnabled {
+				t.Fatalf("resolve() enabled = %v, want %v", enabled, tt.wantEnabled)
+			}
+			if enabled && got != tt.want {
+				t.Errorf("resolve() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRelaxations(t *testing.T) {
+	tests := []struct {
+		override string
+		wantErr  bool
+	}{
+		{override: "patterns_seeded: off"},
+		{override: "context_discovery: hard_block"},
+		{override: "review_approval: hard_block"},
+		{override: "review_approval: off", wantErr: true},
+		{override: "review_approval: warning", wantErr: true},
+		{override: "constitution_required@docs: off", wantErr: true},
+		{override: "kebab_case_name: soft_block", wantErr: true},
+		{override: "specify_prerequisites: suggestion", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.override, func(t *testing.T) {
+			policy, err := ParseGuardPolicy([]string{tt.override})
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = policy.CheckRelaxations(DefaultWorkflow())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckRelaxations(%q) error = %v, want error %v", tt.override, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 
 	"github.com/emergent-company/specmcp/internal/emergent"
+	sdkerrors "github.com/emergent-company/emergent/apps/server-go/pkg/sdk/errors"
 	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
 )
 
 // PopulateProjectState fills the GuardContext with project-level state
-// (constitution, patterns, contexts, components). Used for pre-change guards.
+// (constitution, patterns, contexts, components) and loads the guard policy.
+// Used for pre-change guards.
 func PopulateProjectState(ctx context.Context, client *emergent.Client, gctx *GuardContext) error {
 	// Check for constitution
 	constCount, err := client.CountObjects(ctx, emergent.TypeConstitution)
@@ -39,13 +41,14 @@ func PopulateProjectState(ctx context.Context, client *emergent.Client, gctx *Gu
 	}
 	gctx.ComponentCount = componentCount
 
-	return nil
+	return PopulatePolicyState(ctx, client, gctx, "")
 }
 
 // PopulateChangeState fills the GuardContext with change-level state
 // (proposal, specs, design, tasks). Uses a single ExpandGraph call instead
 // of multiple ListRelationships calls. Also computes readiness booleans
-// by reading status properties from workflow artifacts.
+// by reading status properties from workflow artifacts, and loads the
+// workflow, the guard policy, and the Apps the change is scoped to.
 func PopulateChangeState(ctx context.Context, client *emergent.Client, gctx *GuardContext) error {
 	if gctx.ChangeID == "" {
 		return nil
@@ -214,5 +217,99 @@ func PopulateChangeState(ctx context.Context, client *emergent.Client, gctx *Gua
 		gctx.AllSpecsReady = allSpecsReady
 	}
 
+	// --- Workflow stages ---
+
+	// nodeTags returns a node's tags, from its tags property or its labels
+	nodeTags := func(nodeID string) []string {
+		node, ok := nodeMap[nodeID]
+		if !ok {
+			return nil
+		}
+		if raw, ok := node.Properties["tags"].([]any); ok {
+			tags := make([]string, 0, len(raw))
+			for _, v := range raw {
+				if t, ok := v.(string); ok {
+					tags = append(tags, t)
+				}
+			}
+			return tags
+		}
+		return labelTags(node.Labels)
+	}
+
+	// specReady reports whether a spec and all its requirements and
+	// scenarios are ready
+	specReady := func(specID string) bool {
+		if nodeStatus(specID) != emergent.StatusReady {
+			return false
+		}
+		for _, reqID := range edgesBySrcAndType[specID][emergent.RelHasRequirement] {
+			if nodeStatus(reqID) != emergent.StatusReady {
+				return false
+			}
+			for _, scenID := range edgesBySrcAndType[reqID][emergent.RelHasScenario] {
+				if nodeStatus(scenID) != emergent.StatusReady {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	gctx.Artifacts = gctx.Artifacts[:0]
+	for _, id := range proposalIDs {
+		gctx.Artifacts = append(gctx.Artifacts, ChangeArtifact{ID: id, Type: "proposal", Ready: nodeStatus(id) == emergent.StatusReady, Tags: nodeTags(id)})
+	}
+	for _, id := range specIDs {
+		gctx.Artifacts = append(gctx.Artifacts, ChangeArtifact{ID: id, Type: "spec", Ready: specReady(id), Tags: nodeTags(id)})
+	}
+	for _, id := range designIDs {
+		gctx.Artifacts = append(gctx.Artifacts, ChangeArtifact{ID: id, Type: "design", Ready: nodeStatus(id) == emergent.StatusReady, Tags: nodeTags(id)})
+	}
+	for _, id := range taskIDs {
+		gctx.Artifacts = append(gctx.Artifacts, ChangeArtifact{ID: id, Type: "task", Ready: nodeStatus(id) == emergent.StatusCompleted, Tags: nodeTags(id)})
+	}
+
+	if gctx.Workflow == nil {
+		w, err := LoadWorkflow(ctx, client)
+		if err != nil {
+			return err
+		}
+		gctx.Workflow = w
+	}
+	if err := PopulatePolicyState(ctx, client, gctx, gctx.ChangeID); err != nil {
+		return err
+	}
+
+	// Dependencies are read separately rather than expanded above, so a long
+	// dependency chain can't crowd the change's own artifacts out of MaxNodes.
+	deps, err := client.GetObjectEdges(ctx, gctx.ChangeID, &graph.GetObjectEdgesOptions{
+		Type:      emergent.RelDependsOnChange,
+		Direction: "outgoing",
+	})
+	if err != nil {
+		return err
+	}
+	// Edges may point at any version of a dependency; GetChange resolves
+	// it to the head, and the canonical ID keeps each dependency once.
+	seenDeps := make(map[string]bool, len(deps.Outgoing))
+	for _, edge := range deps.Outgoing {
+		dep, err := client.GetChange(ctx, edge.DstID)
+		if sdkerrors.IsNotFound(err) {
+			gctx.MissingDependencies = append(gctx.MissingDependencies, edge.DstID)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if seenDeps[dep.CanonicalID] {
+			continue
+		}
+		seenDeps[dep.CanonicalID] = true
+		if dep.Status != emergent.StatusArchived {
+			gctx.UnarchivedDependencies = append(gctx.UnarchivedDependencies, dep.Name)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,180 @@
+package guards
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+)
+
+// --- Review Approval ---
+// A review policy says how many approvals an artifact type needs before
+// spec_mark_ready accepts it, e.g. "Proposal: 1 human" or "Design: 2".
+// Policies come from config and from the Constitution's review_policy, which
+// can only tighten config per artifact type.
+
+// ReviewRule is the approval requirement for one artifact type.
+type ReviewRule struct {
+	ArtifactType string `json:"artifact_type"`
+	Approvals    int    `json:"approvals"`
+	Human        bool   `json:"human,omitempty"` // approvals must come from humans
+}
+
+func (r ReviewRule) String() string {
+	s := fmt.Sprintf("%s: %d", r.ArtifactType, r.Approvals)
+	if r.Human {
+		s += " human"
+	}
+	return s
+}
+
+// ReviewPolicy is a set of rules, at most one per artifact type.
+type ReviewPolicy []ReviewRule
+
+// ParseReviewRule reads a rule of the form "<ArtifactType>: <count> [human]".
+func ParseReviewRule(s string) (ReviewRule, error) {
+	typeName, rest, ok := strings.Cut(s, ":")
+	fields := strings.Fields(rest)
+	if !ok || len(fields) == 0 || len(fields) > 2 {
+		return ReviewRule{}, fmt.Errorf("invalid review rule %q (want \"<ArtifactType>: <count> [human]\")", s)
+	}
+	typeName = strings.TrimSpace(typeName)
+	canonical := ""
+	for t := range emergent.WorkflowArtifactTypes {
+		if strings.EqualFold(t, typeName) {
+			canonical = t
+		}
+	}
+	if canonical == "" {
+		return ReviewRule{}, fmt.Errorf("invalid review rule %q: %q is not a workflow artifact type", s, typeName)
+	}
+	n, err := strconv.Atoi(fields[0])
+	if err != nil || n < 0 {
+		return ReviewRule{}, fmt.Errorf("invalid review rule %q: approval count must be a non-negative integer", s)
+	}
+	rule := ReviewRule{ArtifactType: canonical, Approvals: n}
+	if len(fields) == 2 {
+		if !strings.EqualFold(fields[1], "human") {
+			return ReviewRule{}, fmt.Errorf("invalid review rule %q: unknown qualifier %q (want human)", s, fields[1])
+		}
+		rule.Human = true
+	}
+	return rule, nil
+}
+
+// ParseReviewPolicy reads a list of rules. Later rules for the same artifact
+// type replace earlier ones.
+func ParseReviewPolicy(rules []string) (ReviewPolicy, error) {
+	var p ReviewPolicy
+	for _, s := range rules {
+		rule, err := ParseReviewRule(s)
+		if err != nil {
+			return nil, err
+		}
+		p = p.With(rule)
+	}
+	return p, nil
+}
+
+// With returns the policy with rule replacing any rule for the same type.
+func (p ReviewPolicy) With(rule ReviewRule) ReviewPolicy {
+	out := make(ReviewPolicy, 0, len(p)+1)
+	for _, r := range p {
+		if r.ArtifactType != rule.ArtifactType {
+			out = append(out, r)
+		}
+	}
+	return append(out, rule)
+}
+
+// Raise returns the policy with rule merged into any rule for the same type:
+// the higher approval count applies, and human once either requires it.
+func (p ReviewPolicy) Raise(rule ReviewRule) ReviewPolicy {
+	if cur, ok := p.For(rule.ArtifactType); ok {
+		rule.Approvals = max(rule.Approvals, cur.Approvals)
+		rule.Human = rule.Human || cur.Human
+	}
+	return p.With(rule)
+}
+
+// For returns the rule for an artifact type, if any.
+func (p ReviewPolicy) For(artifactType string) (ReviewRule, bool) {
+	for _, r := range p {
+		if strings.EqualFold(r.ArtifactType, artifactType) {
+			return r, true
+		}
+	}
+	return ReviewRule{}, false
+}
+
+// LoadReviewPolicy raises base with the review_policy of the project's
+// Constitutions; base is a floor a Constitution cannot lower. Invalid
+// constitution rules are returned as an error.
+func LoadReviewPolicy(ctx context.Context, client *emergent.Client, base ReviewPolicy) (ReviewPolicy, error) {
+	constitutions, _, err := client.ListAllObjects(ctx, &graph.ListObjectsOptions{Type: emergent.TypeConstitution}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing constitutions: %w", err)
+	}
+	policy := base
+	for _, obj := range constitutions {
+		raw, _ := obj.Properties["review_policy"].([]any)
+		for _, v := range raw {
+			s, _ := v.(string)
+			rule, err := ParseReviewRule(s)
+			if err != nil {
+				return nil, fmt.Errorf("constitution review_policy: %w", err)
+			}
+			policy = policy.Raise(rule)
+		}
+	}
+	return policy, nil
+}
+
+// PopulateReviewState fills the review fields of gctx for an artifact.
+func PopulateReviewState(ctx context.Context, client *emergent.Client, gctx *GuardContext, artifact *graph.GraphObject) error {
+	reviews, err := client.ListReviews(ctx, artifact.ID)
+	if err != nil {
+		return err
+	}
+	summary := emergent.SummarizeReviews(reviews, emergent.ArtifactDigest(artifact.Properties))
+	gctx.Approvals = summary.Approvals
+	gctx.HumanApprovals = summary.HumanApprovals
+	gctx.RejectedBy = summary.RejectedBy
+	gctx.PendingReviewers = summary.Pending
+	return nil
+}
+
+// ReviewApproval returns the review_approval guard, which fails with the
+// given severity when the artifact in gctx lacks the approvals its policy
+// rule requires or has an outstanding rejection.
+func ReviewApproval(policy ReviewPolicy, severity Severity) Guard {
+	return NewGuardFunc("review_approval", func(_ context.Context, gctx *GuardContext) Result {
+		rule, ok := policy.For(gctx.ArtifactType)
+		if !ok {
+			return Pass("review_approval")
+		}
+		if len(gctx.RejectedBy) > 0 {
+			return Fail("review_approval", severity,
+				fmt.Sprintf("%s was rejected by %s.", rule.ArtifactType, joinComma(gctx.RejectedBy)),
+				"Address the review comments, update the artifact, and request a new review with spec_request_review.",
+			)
+		}
+		have, kind := gctx.Approvals, ""
+		if rule.Human {
+			have, kind = gctx.HumanApprovals, " human"
+		}
+		if have >= rule.Approvals {
+			return Pass("review_approval")
+		}
+		msg := fmt.Sprintf("Review policy requires %d%s approval(s) for a %s; it has %d.", rule.Approvals, kind, rule.ArtifactType, have)
+		if len(gctx.PendingReviewers) > 0 {
+			msg += fmt.Sprintf(" Awaiting: %s.", joinComma(gctx.PendingReviewers))
+		}
+		return Fail("review_approval", severity, msg,
+			"Request reviews with spec_request_review; reviewers approve with spec_approve_review.",
+		)
+	})
+}
@@ -0,0 +1,470 @@
+package guards
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/validation"
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+// --- Custom Rules ---
+// A custom rule is a guard written as an expr-lang (https://expr-lang.org)
+// expression instead of Go, e.g.
+//
+//	!("domain:payments" in change.tags) || any(scenarios, {"auditor" in linked(#, "executed_by")})
+//
+// The expression is evaluated over the guard facts (see RuleFacts) and the
+// rule fails with its severity, message, and remedy when it is false. Rules
+// come from config and from the Constitution's guard_rules, which replace
+// configured rules of the same name, and run after the built-in guards of
+// the operations they name.
+
+// Operations a custom rule can run on.
+const (
+	RulesNewChange = "new_change" // spec_new
+	RulesArtifact  = "artifact"   // spec_artifact, spec_generate_tasks
+	RulesMarkReady = "mark_ready" // spec_mark_ready
+	RulesArchive   = "archive"    // spec_archive
+)
+
+// ruleOperations lists the valid operations, in order.
+var ruleOperations = []string{RulesNewChange, RulesArtifact, RulesMarkReady, RulesArchive}
+
+// ruleEntityLists are the facts holding the change's artifacts, by type.
+var ruleEntityLists = map[string]string{
+	emergent.TypeProposal:    "proposals",
+	emergent.TypeSpec:        "specs",
+	emergent.TypeRequirement: "requirements",
+	emergent.TypeScenario:    "scenarios",
+	emergent.TypeDesign:      "designs",
+	emergent.TypeTask:        "tasks",
+}
+
+// Rule is a compiled custom rule.
+type Rule struct {
+	Name     string
+	On       []string
+	Expr     string
+	Severity Severity
+	Message  string
+	Remedy   string
+	program  *vm.Program
+}
+
+// ruleEnv declares the facts a rule may refer to, for compile-time checks.
+func ruleEnv() map[string]any {
+	env := map[string]any{
+		"operation": "",
+		"change":    map[string]any{},
+		"artifact":  map[string]any{},
+		"counts":    map[string]any{},
+	}
+	for _, list := range ruleEntityLists {
+		env[list] = []any{}
+	}
+	return env
+}
+
+// linked returns the names of the entities an entity fact links to with a
+// relationship type, e.g. linked(#, "executed_by") on a scenario.
+func linked(params ...any) (any, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("linked(entity, relationship) takes 2 arguments")
+	}
+	rel, ok := params[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("linked: relationship must be a string")
+	}
+	entity, _ := params[0].(map[string]any)
+	links, _ := entity["links"].(map[string][]string)
+	if names := links[rel]; names != nil {
+		return names, nil
+	}
+	return []string{}, nil
+}
+
+// CompileRule checks a rule definition and compiles its expression.
+func CompileRule(def emergent.GuardRule) (*Rule, error) {
+	if !guardNameRegex.MatchString(def.Name) {
+		return nil, fmt.Errorf("invalid guard rule %q: name must be lowercase words joined by underscores", def.Name)
+	}
+	if len(def.On) == 0 {
+		return nil, fmt.Errorf("invalid guard rule %q: on must list at least one of %s", def.Name, strings.Join(ruleOperations, ", "))
+	}
+	for _, op := range def.On {
+		if !slices.Contains(ruleOperations, op) {
+			return nil, fmt.Errorf("invalid guard rule %q: unknown operation %q (want %s)", def.Name, op, strings.Join(ruleOperations, ", "))
+		}
+	}
+	if strings.TrimSpace(def.Expr) == "" {
+		return nil, fmt.Errorf("invalid guard rule %q: expr is required", def.Name)
+	}
+	if def.Message == "" {
+		return nil, fmt.Errorf("invalid guard rule %q: message is required", def.Name)
+	}
+	sev := Warning
+	if def.Severity != "" {
+		s, err := ParseSeverity(def.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("invalid guard rule %q: %w", def.Name, err)
+		}
+		sev = s
+	}
+	program, err := expr.Compile(def.Expr,
+		expr.Env(ruleEnv()),
+		expr.AsBool(),
+		expr.Function("linked", linked),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid guard rule %q: %w", def.Name, err)
+	}
+	return &Rule{
+		Name:     def.Name,
+		On:       def.On,
+		Expr:     def.Expr,
+		Severity: sev,
+		Message:  def.Message,
+		Remedy:   def.Remedy,
+		program:  program,
+	}, nil
+}
+
+// CompileRules compiles a list of rule definitions. Later rules replace
+// earlier ones of the same name.
+func CompileRules(defs []emergent.GuardRule) ([]*Rule, error) {
+	var rules []*Rule
+	for _, def := range defs {
+		r, err := CompileRule(def)
+		if err != nil {
+			return nil, err
+		}
+		rules = withRule(rules, r)
+	}
+	return rules, nil
+}
+
+// withRule returns rules with r replacing any rule of the same name.
+func withRule(rules []*Rule, r *Rule) []*Rule {
+	out := make([]*Rule, 0, len(rules)+1)
+	for _, other := range rules {
+		if other.Name != r.Name {
+			out = append(out, other)
+		}
+	}
+	return append(out, r)
+}
+
+// LoadRules returns the configured rules (see
+// emergent.ClientFactory.SetGuardRules) with the guard_rules of the project's
+// Constitutions on top. Invalid constitution rules are returned as an error.
+func LoadRules(ctx context.Context, client *emergent.Client) ([]*Rule, error) {
+	rules, err := CompileRules(client.GuardRules())
+	if err != nil {
+		return nil, fmt.Errorf("guard rules: %w", err)
+	}
+	constitutions, _, err := client.ListAllObjects(ctx, &graph.ListObjectsOptions{Type: emergent.TypeConstitution}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing constitutions: %w", err)
+	}
+	for _, obj := range constitutions {
+		raw, ok := obj.Properties["guard_rules"]
+		if !ok {
+			continue
+		}
+		b, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("constitution guard_rules: %w", err)
+		}
+		var defs []emergent.GuardRule
+		if err := json.Unmarshal(b, &defs); err != nil {
+			return nil, fmt.Errorf("constitution guard_rules: %w", err)
+		}
+		for _, def := range defs {
+			r, err := CompileRule(def)
+			if err != nil {
+				return nil, fmt.Errorf("constitution guard_rules: %w", err)
+			}
+			rules = withRule(rules, r)
+		}
+	}
+	return rules, nil
+}
+
+// RunsOn reports whether the rule runs on an operation.
+func (r *Rule) RunsOn(operation string) bool {
+	return slices.Contains(r.On, operation)
+}
+
+// Guard returns the rule as a guard over gctx.Facts. A rule that cannot be
+// evaluated, or whose facts are incomplete, fails with WARNING, so a broken
+// rule never blocks and a partial view never passes or blocks silently.
+func (r *Rule) Guard() Guard {
+	return NewGuardFunc(r.Name, func(_ context.Context, gctx *GuardContext) Result {
+		if gctx.FactsTruncated {
+			return Fail(r.Name, Warning,
+				"Custom rule not evaluated: the change has more artifacts or links than the facts can load",
+				"Check the rule by hand, or split the change into smaller ones.",
+			)
+		}
+		out, err := expr.Run(r.program, gctx.Facts)
+		if err != nil {
+			return Fail(r.Name, Warning,
+				fmt.Sprintf("Custom rule could not be evaluated: %v", err),
+				"Fix the rule's expr in the [[guards.rules]] config or the Constitution's guard_rules.",
+			)
+		}
+		if ok, _ := out.(bool); ok {
+			return Pass(r.Name)
+		}
+		return Fail(r.Name, r.Severity, r.Message, r.Remedy)
+	})
+}
+
+// WithRules returns base followed by the custom rules that run on operation,
+// loading the rules into gctx if needed and, when any apply, the facts they
+// are evaluated over.
+func WithRules(ctx context.Context, client *emergent.Client, gctx *GuardContext, operation string, base []Guard) ([]Guard, error) {
+	if gctx.Rules == nil {
+		rules, err := LoadRules(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		gctx.Rules = append([]*Rule{}, rules...)
+	}
+	out := append([]Guard(nil), base...)
+	for _, r := range gctx.Rules {
+		if !r.RunsOn(operation) {
+			continue
+		}
+		if gctx.Facts == nil {
+			facts, truncated, err := RuleFacts(ctx, client, gctx, operation)
+			if err != nil {
+				return nil, fmt.Errorf("loading guard rule facts: %w", err)
+			}
+			gctx.Facts = facts
+			gctx.FactsTruncated = truncated
+		}
+		out = append(out, r.Guard())
+	}
+	return out, nil
+}
+
+// RuleFacts builds the facts custom rules are evaluated over:
+//
+//   - operation: new_change, artifact, mark_ready, or archive
+//   - change: the change's properties (name, status, tags, ...), apps, and
+//     links; on new_change just name, tags, and apps; empty on mark_ready
+//   - artifact: the artifact being added (artifact) or marked ready
+//     (mark_ready): its type (e.g. "Design"), tags, properties, and links
+//     (mark_ready only)
+//   - counts: specs, tasks, completed_tasks, pending_tasks, patterns,
+//     contexts, components, open_comments, approvals, human_approvals
+//   - proposals, specs, requirements, scenarios, designs, tasks: the change's
+//     artifacts, each with id, type, name, status, tags, its properties, and
+//     links
+//
+// links maps relationship types to the names of the linked entities; read it
+// with linked(entity, "executed_by").
+//
+// truncated reports that a graph expansion hit its node or edge cap, so some
+// artifacts or links are missing from the facts.
+func RuleFacts(ctx context.Context, client *emergent.Client, gctx *GuardContext, operation string) (facts map[string]any, truncated bool, err error) {
+	facts = ruleEnv()
+	facts["operation"] = operation
+	facts["counts"] = map[string]any{
+		"specs":           gctx.SpecCount,
+		"tasks":           gctx.TaskCount,
+		"completed_tasks": gctx.CompletedTasks,
+		"pending_tasks":   gctx.PendingTasks,
+		"patterns":        gctx.PatternCount,
+		"contexts":        gctx.ContextCount,
+		"components":      gctx.ComponentCount,
+		"open_comments":   gctx.OpenComments,
+		"approvals":       gctx.Approvals,
+		"human_approvals": gctx.HumanApprovals,
+	}
+	apps := append([]string{}, gctx.Apps...)
+
+	switch {
+	case gctx.ChangeID != "":
+		resp, err := client.ExpandGraph(ctx, &graph.GraphExpandRequest{
+			RootIDs:   []string{gctx.ChangeID},
+			Direction: "outgoing",
+			MaxDepth:  3, // change→spec→requirement→scenario
+			MaxNodes:  500,
+			MaxEdges:  1000,
+			RelationshipTypes: []string{
+				emergent.RelHasProposal,
+				emergent.RelHasSpec,
+				emergent.RelHasDesign,
+				emergent.RelHasTask,
+				emergent.RelHasRequirement,
+				emergent.RelHasScenario,
+			},
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		truncated = resp.Truncated
+		ids := make([]string, 0, len(resp.Nodes))
+		for _, node := range resp.Nodes {
+			ids = append(ids, node.ID)
+		}
+		links, linksTruncated, err := entityLinks(ctx, client, ids)
+		if err != nil {
+			return nil, false, err
+		}
+		truncated = truncated || linksTruncated
+		lists := make(map[string][]any)
+		for _, node := range resp.Nodes {
+			entity := entityFacts(node, links[node.ID])
+			if node.ID == gctx.ChangeID || node.CanonicalID == gctx.ChangeID {
+				entity["apps"] = apps
+				facts["change"] = entity
+			} else if list, ok := ruleEntityLists[node.Type]; ok {
+				lists[list] = append(lists[list], entity)
+			}
+		}
+		for _, list := range ruleEntityLists {
+			if lists[list] != nil {
+				facts[list] = lists[list]
+			}
+		}
+	case gctx.ChangeName != "":
+		facts["change"] = map[string]any{
+			"name": gctx.ChangeName,
+			"tags": append([]string{}, gctx.ChangeTags...),
+			"apps": apps,
+		}
+	}
+
+	switch {
+	case gctx.ArtifactID != "":
+		obj, err := client.GetObject(ctx, gctx.ArtifactID)
+		if err != nil {
+			return nil, false, err
+		}
+		links, linksTruncated, err := entityLinks(ctx, client, []string{obj.ID})
+		if err != nil {
+			return nil, false, err
+		}
+		truncated = truncated || linksTruncated
+		facts["artifact"] = entityFacts(&graph.ExpandNode{
+			ID:          obj.ID,
+			CanonicalID: obj.CanonicalID,
+			Type:        obj.Type,
+			Key:         obj.Key,
+			Labels:      obj.Labels,
+			Properties:  obj.Properties,
+		}, links[obj.ID])
+	case gctx.ArtifactType != "":
+		artifact := make(map[string]any, len(gctx.ArtifactProperties)+2)
+		maps.Copy(artifact, gctx.ArtifactProperties)
+		artifact["type"] = objectType(gctx.ArtifactType)
+		artifact["tags"] = append([]string{}, gctx.ArtifactTags...)
+		facts["artifact"] = artifact
+	}
+	return facts, truncated, nil
+}
+
+// entityLinks returns, per entity ID, the names of the entities it links to
+// by relationship type, and whether the expansion hit its cap.
+func entityLinks(ctx context.Context, client *emergent.Client, ids []string) (map[string]map[string][]string, bool, error) {
+	links := make(map[string]map[string][]string, len(ids))
+	if len(ids) == 0 {
+		return links, false, nil
+	}
+	resp, err := client.ExpandGraph(ctx, &graph.GraphExpandRequest{
+		RootIDs:   ids,
+		Direction: "outgoing",
+		MaxDepth:  1,
+		MaxNodes:  1000,
+		MaxEdges:  2000,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	nodeMap := emergent.NewNodeIndex(resp.Nodes)
+	emergent.CanonicalizeEdgeIDs(resp.Edges, nodeMap)
+	for _, edge := range resp.Edges {
+		dst, ok := nodeMap[edge.DstID]
+		if !ok {
+			continue
+		}
+		if links[edge.SrcID] == nil {
+			links[edge.SrcID] = make(map[string][]string)
+		}
+		links[edge.SrcID][edge.Type] = append(links[edge.SrcID][edge.Type], nodeName(dst))
+	}
+	return links, resp.Truncated, nil
+}
+
+// entityFacts returns the facts of one entity: its properties plus id, type,
+// name, status, tags, and links.
+func entityFacts(node *graph.ExpandNode, links map[string][]string) map[string]any {
+	entity := make(map[string]any, len(node.Properties)+6)
+	maps.Copy(entity, node.Properties)
+	entity["id"] = node.ID
+	entity["type"] = node.Type
+	entity["name"] = nodeName(node)
+	if s, _ := entity["status"].(string); s == "" {
+		entity["status"] = validation.InitialStatus(node.Type)
+	}
+	tags := labelTags(node.Labels)
+	if raw, ok := node.Properties["tags"].([]any); ok {
+		tags = tags[:0]
+		for _, v := range raw {
+			if t, ok := v.(string); ok {
+				tags = append(tags, t)
+			}
+		}
+	}
+	entity["tags"] = tags
+	if links == nil {
+		links = map[string][]string{}
+	}
+	entity["links"] = links
+	return entity
+}
+
+// labelTags returns the labels that double as tags, leaving out the
+// bookkeeping labels of idempotent creates.
+func labelTags(labels []string) []string {
+	tags := []string{}
+	for _, l := range labels {
+		if !strings.HasPrefix(l, emergent.IdempotencyLabelPrefix) {
+			tags = append(tags, l)
+		}
+	}
+	return tags
+}
+
+// objectType returns the graph type of an artifact_type such as
+// "scenario_step" (ScenarioStep), or artifactType itself if none matches.
+func objectType(artifactType string) string {
+	for _, t := range emergent.ObjectTypes {
+		if strings.EqualFold(t, strings.ReplaceAll(artifactType, "_", "")) {
+			return t
+		}
+	}
+	return artifactType
+}
+
+// nodeName returns a node's name property, else its key, else its ID.
+func nodeName(node *graph.ExpandNode) string {
+	if name, _ := node.Properties["name"].(string); name != "" {
+		return name
+	}
+	if node.Key != nil && *node.Key != "" {
+		return *node.Key
+	}
+	return node.ID
+}
@@ -0,0 +1,398 @@
+package guards
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+)
+
+// --- Workflow Stages ---
+// A workflow is an ordered list of stages. Each stage owns some artifact
+// types and may require earlier stages to be complete before its artifacts
+// can be added. Stages are written one per string:
+//
+//	<name>[?]: [artifact types] [after <stage>, ...]
+//
+// A trailing "?" makes the stage optional: skipped while it has no
+// artifacts. Artifacts tagged "stage:<name>" belong to that stage whatever
+// their type, so a tag-only stage such as "threat-model?: after specify"
+// collects e.g. a Design tagged stage:threat-model.
+//
+// The workflow comes from the Constitution's workflow_stages, else from
+// config, else DefaultWorkflowStages.
+
+// DefaultWorkflowStages is the Proposal → Spec → Design → Tasks chain.
+var DefaultWorkflowStages = []string{
+	"propose: proposal",
+	"specify: spec, requirement, scenario, scenario_step after propose",
+	"design: design after propose, specify",
+	"implement: task after design",
+}
+
+// StageArtifactTypes are the spec_artifact types a stage can own.
+var StageArtifactTypes = []string{"proposal", "spec", "requirement", "scenario", "scenario_step", "design", "task"}
+
+// StageTagPrefix marks an artifact as belonging to a named stage.
+const StageTagPrefix = "stage:"
+
+var stageNameRe = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// Stage is one step of a change's workflow.
+type Stage struct {
+	Name      string   `json:"name"`
+	Artifacts []string `json:"artifacts,omitempty"` // spec_artifact types owned by the stage
+	Requires  []string `json:"requires,omitempty"`  // stages that must be complete first
+	Optional  bool     `json:"optional,omitempty"`  // skipped while it has no artifacts
+}
+
+func (s Stage) String() string {
+	name := s.Name
+	if s.Optional {
+		name += "?"
+	}
+	out := name + ":"
+	if len(s.Artifacts) > 0 {
+		out += " " + strings.Join(s.Artifacts, ", ")
+	}
+	if len(s.Requires) > 0 {
+		out += " after " + strings.Join(s.Requires, ", ")
+	}
+	return out
+}
+
+// Workflow is an ordered list of stages.
+type Workflow struct {
+	Stages []Stage
+}
+
+// ParseStage reads one stage definition.
+func ParseStage(s string) (Stage, error) {
+	name, rest, ok := strings.Cut(s, ":")
+	if !ok {
+		return Stage{}, fmt.Errorf("invalid stage %q (want \"<name>[?]: [artifact types] [after <stage>, ...]\")", s)
+	}
+	var st Stage
+	name = strings.TrimSpace(name)
+	if strings.HasSuffix(name, "?") {
+		st.Optional = true
+		name = strings.TrimSpace(strings.TrimSuffix(name, "?"))
+	}
+	if !stageNameRe.MatchString(name) {
+		return Stage{}, fmt.Errorf("invalid stage %q: name %q must be kebab-case", s, name)
+	}
+	st.Name = name
+
+	types, requires := rest, ""
+	fields := strings.Fields(rest)
+	for i, f := range fields {
+		if f == "after" {
+			types = strings.Join(fields[:i], " ")
+			requires = strings.Join(fields[i+1:], " ")
+			if requires == "" {
+				return Stage{}, fmt.Errorf("invalid stage %q: \"after\" needs at least one stage", s)
+			}
+			break
+		}
+	}
+	for _, t := range splitList(types) {
+		t = strings.ToLower(t)
+		if !slices.Contains(StageArtifactTypes, t) {
+			return Stage{}, fmt.Errorf("invalid stage %q: %q is not a stage artifact type (%s)", s, t, strings.Join(StageArtifactTypes, ", "))
+		}
+		st.Artifacts = append(st.Artifacts, t)
+	}
+	st.Requires = splitList(requires)
+	return st, nil
+}
+
+// splitList splits a comma- or space-separated list.
+func splitList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+}
+
+// ParseWorkflow reads a list of stage definitions. Stages may only require
+// stages defined before them, and each artifact type belongs to at most
+// one stage.
+func ParseWorkflow(defs []string) (*Workflow, error) {
+	w := &Workflow{}
+	owner := make(map[string]string)
+	for _, def := range defs {
+		st, err := ParseStage(def)
+		if err != nil {
+			return nil, err
+		}
+		if w.Stage(st.Name) != nil {
+			return nil, fmt.Errorf("invalid workflow: stage %q is defined twice", st.Name)
+		}
+		for _, req := range st.Requires {
+			if w.Stage(req) == nil {
+				return nil, fmt.Errorf("invalid workflow: stage %q requires %q, which is not defined before it", st.Name, req)
+			}
+		}
+		for _, t := range st.Artifacts {
+			if prev, ok := owner[t]; ok {
+				return nil, fmt.Errorf("invalid workflow: artifact type %q belongs to both %q and %q", t, prev, st.Name)
+			}
+			owner[t] = st.Name
+		}
+		w.Stages = append(w.Stages, st)
+	}
+	if len(w.Stages) == 0 {
+		return nil, fmt.Errorf("invalid workflow: no stages")
+	}
+	return w, nil
+}
+
+// DefaultWorkflow returns the workflow defined by DefaultWorkflowStages.
+func DefaultWorkflow() *Workflow {
+	w, err := ParseWorkflow(DefaultWorkflowStages)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// LoadWorkflow returns the project's workflow: the first Constitution with
+// workflow_stages, else the stages configured on the client, else the
+// default.
+func LoadWorkflow(ctx context.Context, client *emergent.Client) (*Workflow, error) {
+	constitutions, _, err := client.ListAllObjects(ctx, &graph.ListObjectsOptions{Type: emergent.TypeConstitution}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing constitutions: %w", err)
+	}
+	return workflowOf(client, constitutions)
+}
+
+// workflowOf is LoadWorkflow over already listed Constitutions.
+func workflowOf(client *emergent.Client, constitutions []*graph.GraphObject) (*Workflow, error) {
+	for _, obj := range constitutions {
+		raw, _ := obj.Properties["workflow_stages"].([]any)
+		if len(raw) == 0 {
+			continue
+		}
+		defs := make([]string, 0, len(raw))
+		for _, v := range raw {
+			s, _ := v.(string)
+			defs = append(defs, s)
+		}
+		w, err := ParseWorkflow(defs)
+		if err != nil {
+			return nil, fmt.Errorf("constitution workflow_stages: %w", err)
+		}
+		return w, nil
+	}
+	if defs := client.WorkflowStages(); len(defs) > 0 {
+		return ParseWorkflow(defs)
+	}
+	return DefaultWorkflow(), nil
+}
+
+// Stage returns the named stage, or nil.
+func (w *Workflow) Stage(name string) *Stage {
+	for i := range w.Stages {
+		if w.Stages[i].Name == name {
+			return &w.Stages[i]
+		}
+	}
+	return nil
+}
+
+// StageFor returns the stage an artifact belongs to: the stage named by a
+// stage: tag, else the stage owning its type. Returns nil if none.
+func (w *Workflow) StageFor(artifactType string, tags []string) *Stage {
+	for _, tag := range tags {
+		if name, ok := strings.CutPrefix(tag, StageTagPrefix); ok {
+			if st := w.Stage(name); st != nil {
+				return st
+			}
+		}
+	}
+	for i := range w.Stages {
+		if slices.Contains(w.Stages[i].Artifacts, artifactType) {
+			return &w.Stages[i]
+		}
+	}
+	return nil
+}
+
+// ChangeArtifact is one of a change's proposals, specs, designs, or tasks.
+// A spec is ready when it and all its requirements and scenarios are; a
+// task is ready when completed.
+type ChangeArtifact struct {
+	ID    string
+	Type  string // proposal, spec, design, task
+	Ready bool
+	Tags  []string
+}
+
+// StageState is the progress of one stage of a change.
+type StageState struct {
+	Name      string `json:"name"`
+	Optional  bool   `json:"optional,omitempty"`
+	Artifacts int    `json:"artifacts"`
+	Ready     int    `json:"ready"`
+	Status    string `json:"status"` // pending, in_progress, complete, skipped
+}
+
+// Complete reports whether the stage no longer holds up later stages.
+func (s StageState) Complete() bool {
+	return s.Status == "complete" || s.Status == "skipped"
+}
+
+// States computes the state of every stage from gctx.Artifacts.
+func (w *Workflow) States(gctx *GuardContext) []StageState {
+	states := make([]StageState, len(w.Stages))
+	index := make(map[string]int, len(w.Stages))
+	for i, st := range w.Stages {
+		states[i] = StageState{Name: st.Name, Optional: st.Optional}
+		index[st.Name] = i
+	}
+	for _, a := range gctx.Artifacts {
+		st := w.StageFor(a.Type, a.Tags)
+		if st == nil {
+			continue
+		}
+		s := &states[index[st.Name]]
+		s.Artifacts++
+		if a.Ready {
+			s.Ready++
+		}
+	}
+	for i := range states {
+		s := &states[i]
+		switch {
+		case s.Artifacts == 0 && s.Optional:
+			s.Status = "skipped"
+		case s.Artifacts == 0:
+			s.Status = "pending"
+		case s.Ready < s.Artifacts:
+			s.Status = "in_progress"
+		default:
+			s.Status = "complete"
+		}
+	}
+	return states
+}
+
+// Complete reports whether every stage of the change is complete or skipped.
+func (w *Workflow) Complete(gctx *GuardContext) bool {
+	for _, s := range w.States(gctx) {
+		if !s.Complete() {
+			return false
+		}
+	}
+	return true
+}
+
+// Progress returns the change's current stage and the steps that advance
+// it. The stage is "complete" once every stage is.
+func (w *Workflow) Progress(gctx *GuardContext) (string, []string) {
+	var optional []string
+	for i, s := range w.States(gctx) {
+		st := w.Stages[i]
+		if s.Status == "skipped" {
+			optional = append(optional, fmt.Sprintf("Optional stage %q: %s", st.Name, addStep(st)))
+			continue
+		}
+		if s.Complete() {
+			optional = nil // optional stages before a completed stage were passed by
+			continue
+		}
+		var steps []string
+		if s.Artifacts == 0 {
+			steps = append(steps, addStep(st))
+		} else {
+			steps = append(steps, readyStep(st, s))
+		}
+		return st.Name, append(optional, steps...)
+	}
+	return "complete", append(optional, "All stages complete. Archive the change: spec_archive")
+}
+
+// primaryType is the stage's first artifact type a change links directly
+// (proposal, spec, design, task), or "".
+func primaryType(st Stage) string {
+	for _, t := range st.Artifacts {
+		switch t {
+		case "proposal", "spec", "design", "task":
+			return t
+		}
+	}
+	return ""
+}
+
+func addStep(st Stage) string {
+	switch primaryType(st) {
+	case "proposal":
+		return "Add a proposal: spec_artifact with artifact_type='proposal'"
+	case "spec":
+		return "Add specs: spec_artifact with artifact_type='spec'"
+	case "design":
+		return "Add a design: spec_artifact with artifact_type='design'"
+	case "task":
+		return "Generate tasks: spec_generate_tasks"
+	}
+	return fmt.Sprintf("Add artifacts tagged '%s%s' with spec_artifact", StageTagPrefix, st.Name)
+}
+
+func readyStep(st Stage, s StageState) string {
+	switch primaryType(st) {
+	case "spec":
+		return "Mark all specs, requirements, and scenarios as ready (bottom-up) using spec_mark_ready"
+	case "task":
+		return fmt.Sprintf("Complete %d remaining task(s): use spec_complete_task", s.Artifacts-s.Ready)
+	}
+	return fmt.Sprintf("Mark the %d remaining %s artifact(s) as ready: spec_mark_ready with each entity_id", s.Artifacts-s.Ready, st.Name)
+}
+
+// GuardName is the name of the guard generated for a stage.
+func (s Stage) GuardName() string {
+	return strings.ReplaceAll(s.Name, "-", "_") + "_prerequisites"
+}
+
+// ArtifactGuards returns one guard per stage with requirements. Each fails
+// with HardBlock when an artifact of its stage is added before the required
+// stages are complete.
+func (w *Workflow) ArtifactGuards() []Guard {
+	var out []Guard
+	for _, st := range w.Stages {
+		if len(st.Requires) == 0 {
+			continue
+		}
+		out = append(out, w.stageGuard(st))
+	}
+	return out
+}
+
+func (w *Workflow) stageGuard(st Stage) Guard {
+	name := st.GuardName()
+	return NewGuardFunc(name, func(_ context.Context, gctx *GuardContext) Result {
+		if target := w.StageFor(gctx.ArtifactType, gctx.ArtifactTags); target == nil || target.Name != st.Name {
+			return Pass(name)
+		}
+		states := w.States(gctx)
+		for _, req := range st.Requires {
+			i := slices.IndexFunc(w.Stages, func(s Stage) bool { return s.Name == req })
+			s, prev := states[i], w.Stages[i]
+			if s.Complete() {
+				continue
+			}
+			if s.Artifacts == 0 {
+				return Fail(name, HardBlock,
+					fmt.Sprintf("Stage %q must be complete before adding a %s (stage %q), but the change has nothing for it yet.", req, gctx.ArtifactType, st.Name),
+					addStep(prev)+".",
+				)
+			}
+			return Fail(name, HardBlock,
+				fmt.Sprintf("Stage %q must be complete before adding a %s (stage %q): %d of %d of its artifacts are not ready.", req, gctx.ArtifactType, st.Name, s.Artifacts-s.Ready, s.Artifacts),
+				readyStep(prev, s)+".",
+			)
+		}
+		return Pass(name)
+	})
+}
@@ -0,0 +1,95 @@
+package guards
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStage(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Stage
+		wantErr bool
+	}{
+		{in: "propose: proposal", want: Stage{Name: "propose", Artifacts: []string{"proposal"}}},
+		{
+			in:   "specify: spec, requirement, scenario after propose",
+			want: Stage{Name: "specify", Artifacts: []string{"spec", "requirement", "scenario"}, Requires: []string{"propose"}},
+		},
+		{
+			in:   "design?: Design after propose, specify",
+			want: Stage{Name: "design", Artifacts: []string{"design"}, Requires: []string{"propose", "specify"}, Optional: true},
+		},
+		{
+			in:   "threat-model ?: after specify",
+			want: Stage{Name: "threat-model", Requires: []string{"specify"}, Optional: true},
+		},
+		{in: "review:", want: Stage{Name: "review"}},
+		{in: "propose proposal", wantErr: true},
+		{in: "Propose: proposal", wantErr: true},
+		{in: "threat_model: design", wantErr: true},
+		{in: "propose: proposal, changelog", wantErr: true},
+		{in: "design: design after", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseStage(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseStage(%q) = %+v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStage(%q): %v", tt.in, err)
+			}
+			// String covers every field, and unlike DeepEqual treats an
+			// empty list like a missing one.
+			if got.String() != tt.want.String() {
+				t.Errorf("ParseStage(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+			if back, err := ParseStage(got.String()); err != nil || back.String() != got.String() {
+				t.Errorf("ParseStage(%q) does not round-trip: %q, %v", got, back, err)
+			}
+		})
+	}
+}
+
+func TestParseWorkflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		defs    []string
+		want    []string // stage names, in order
+		wantErr bool
+	}{
+		{name: "default", defs: DefaultWorkflowStages, want: []string{"propose", "specify", "design", "implement"}},
+		{name: "tag-only stage", defs: []string{"propose: proposal", "threat-model?: after propose"}, want: []string{"propose", "threat-model"}},
+		{name: "empty", wantErr: true},
+		{name: "invalid stage", defs: []string{"propose"}, wantErr: true},
+		{name: "stage defined twice", defs: []string{"propose: proposal", "propose: design"}, wantErr: true},
+		{name: "requires a later stage", defs: []string{"design: design after specify", "specify: spec"}, wantErr: true},
+		{name: "requires an unknown stage", defs: []string{"design: design after review"}, wantErr: true},
+		{name: "artifact type in two stages", defs: []string{"propose: proposal, design", "design: design after propose"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseWorkflow(tt.defs)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseWorkflow(%q) succeeded, want error", tt.defs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWorkflow(%q): %v", tt.defs, err)
+			}
+			var got []string
+			for _, st := range w.Stages {
+				got = append(got, st.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWorkflow(%q) stages = %q, want %q", tt.defs, got, tt.want)
+			}
+		})
+	}
+}
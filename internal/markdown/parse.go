@@ -0,0 +1,594 @@
+// Package markdown reads and writes changes in the OpenSpec markdown layout:
+//
+//	<change>/
+//	  proposal.md             ## Why / ## What Changes / ## Impact
+//	  design.md               free-form, with optional ## Decisions / ## Data Flow / ## File Changes
+//	  tasks.md                ## N. Section, then - [ ] N.M description checkboxes
+//	  specs/<capability>/spec.md
+//	                          ## ADDED|MODIFIED|REMOVED|RENAMED Requirements
+//	                          ### Requirement: <name>
+//	                          #### Scenario: <name> with - **GIVEN/WHEN/THEN/AND** lines
+//
+// ReadDir parses such a directory and WriteDir renders one back; ParseFiles
+// and Files do the same for file contents held in memory. RenderPR renders
+// the same content as a single pull request description. The package only
+// deals with the documents; mapping them onto graph entities is up to the
+// caller.
+package markdown
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Delta types, as written in spec.md section headings.
+const (
+	DeltaAdded    = "added"
+	DeltaModified = "modified"
+	DeltaRemoved  = "removed"
+	DeltaRenamed  = "renamed"
+)
+
+// Task statuses derived from checkbox state.
+const (
+	TaskPending    = "pending"
+	TaskInProgress = "in_progress"
+	TaskCompleted  = "completed"
+)
+
+// Change is one change directory.
+type Change struct {
+	Name     string
+	Proposal *Proposal
+	Design   *Design
+	Specs    []*Spec
+	Tasks    []*Task // top-level tasks (tasks.md sections); subtasks nest under them
+}
+
+// Proposal is proposal.md.
+type Proposal struct {
+	Intent string // ## Why
+	Scope  string // ## What Changes, plus any sections other than Why and Impact
+	Impact string // ## Impact
+}
+
+// Design is design.md.
+type Design struct {
+	Approach    string   // everything outside the sections below
+	Decisions   string   // ## Decisions
+	DataFlow    string   // ## Data Flow
+	FileChanges []string // ## File Changes, one list item each
+}
+
+// Spec is specs/<capability>/spec.md.
+type Spec struct {
+	Name         string // the capability directory name
+	Purpose      string // ## Purpose, if present
+	DeltaType    string // shared by all requirements, else modified
+	Requirements []*Requirement
+}
+
+// Requirement is a ### Requirement: block.
+type Requirement struct {
+	Name        string
+	Description string
+	Strength    string // MUST, SHOULD, or MAY: a **Strength**: line, else the RFC 2119 keyword used
+	DeltaType   string
+	RenamedFrom string
+	Scenarios   []*Scenario
+}
+
+// Scenario is a #### Scenario: block.
+type Scenario struct {
+	Name    string
+	Given   string
+	When    string
+	Then    string
+	AndAlso []string
+}
+
+// Task is a tasks.md section (a parent task) or checkbox item.
+type Task struct {
+	Number      string
+	Description string
+	Status      string
+	Subtasks    []*Task
+}
+
+// ReadDir parses the change directory at dir. The change is named after the
+// directory. Missing files are left nil or empty; a directory with none of
+// the expected files is an error.
+func ReadDir(dir string) (*Change, error) {
+	ch, err := ReadFS(os.DirFS(dir))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	ch.Name = path.Base(strings.TrimRight(strings.ReplaceAll(dir, `\`, "/"), "/"))
+	return ch, nil
+}
+
+// ReadFS parses a change laid out at the root of fsys. Name is left empty.
+func ReadFS(fsys fs.FS) (*Change, error) {
+	files, err := ReadFiles(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFiles(files)
+}
+
+// ReadFiles reads the files of the change layout at the root of fsys, keyed
+// by slash separated path as ParseFiles takes them. Other files are ignored.
+func ReadFiles(fsys fs.FS) (map[string]string, error) {
+	files := map[string]string{}
+	read := func(name string) error {
+		b, err := fs.ReadFile(fsys, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		files[name] = string(b)
+		return nil
+	}
+	for _, name := range []string{"proposal.md", "design.md", "tasks.md"} {
+		if err := read(name); err != nil {
+			return nil, err
+		}
+	}
+	entries, err := fs.ReadDir(fsys, "specs")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		if err := read(path.Join("specs", e.Name(), "spec.md")); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// ParseFiles parses a change from its files, keyed by slash separated path
+// relative to the change directory as Files returns them. Paths outside the
+// layout are ignored. Name is left empty.
+func ParseFiles(files map[string]string) (*Change, error) {
+	ch := &Change{}
+	found := false
+	for name, content := range files {
+		switch name {
+		case "proposal.md":
+			ch.Proposal = ParseProposal(content)
+		case "design.md":
+			ch.Design = ParseDesign(content)
+		case "tasks.md":
+			ch.Tasks = ParseTasks(content)
+		default:
+			capability, ok := specCapability(name)
+			if !ok {
+				continue
+			}
+			ch.Specs = append(ch.Specs, ParseSpec(capability, content))
+		}
+		found = true
+	}
+	sort.Slice(ch.Specs, func(i, j int) bool { return ch.Specs[i].Name < ch.Specs[j].Name })
+
+	if !found {
+		return nil, errors.New("no proposal.md, design.md, tasks.md, or specs/*/spec.md found")
+	}
+	return ch, nil
+}
+
+// specCapability returns the capability of a specs/<capability>/spec.md path.
+func specCapability(name string) (string, bool) {
+	rest, ok := strings.CutPrefix(name, "specs/")
+	if !ok {
+		return "", false
+	}
+	capability, ok := strings.CutSuffix(rest, "/spec.md")
+	if !ok || capability == "" || strings.Contains(capability, "/") {
+		return "", false
+	}
+	return capability, true
+}
+
+// section is a ## block of a document: its heading text and body.
+type section struct {
+	heading string // empty for text before the first heading
+	body    string
+}
+
+// splitSections splits a document on headings of the given level ("## ").
+// Deeper headings stay in the body.
+func splitSections(doc, marker string) []section {
+	var out []section
+	cur := section{}
+	var body []string
+	flush := func() {
+		cur.body = strings.TrimSpace(strings.Join(body, "\n"))
+		if cur.heading != "" || cur.body != "" {
+			out = append(out, cur)
+		}
+		body = nil
+	}
+	fence := ""
+	for _, line := range strings.Split(normalize(doc), "\n") {
+		// A "## " inside a fenced code block is code, not a heading.
+		if f := fenceOf(line); f != "" && (fence == "" || strings.HasPrefix(f, fence)) {
+			if fence == "" {
+				fence = f
+			} else {
+				fence = ""
+			}
+		} else if fence == "" && strings.HasPrefix(line, marker) {
+			flush()
+			cur = section{heading: strings.TrimSpace(strings.TrimPrefix(line, marker))}
+			continue
+		}
+		body = append(body, line)
+	}
+	flush()
+	return out
+}
+
+// fenceOf returns the run of backticks or tildes opening line if it is a
+// code fence, or "".
+func fenceOf(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return ""
+	}
+	for _, c := range []string{"`", "~"} {
+		n := len(trimmed) - len(strings.TrimLeft(trimmed, c))
+		if n >= 3 {
+			return trimmed[:n]
+		}
+	}
+	return ""
+}
+
+// ParseProposal parses proposal.md.
+func ParseProposal(doc string) *Proposal {
+	p := &Proposal{}
+	var scope []string
+	for _, s := range splitSections(doc, "## ") {
+		switch strings.ToLower(s.heading) {
+		case "why":
+			p.Intent = s.body
+		case "impact":
+			p.Impact = s.body
+		case "what changes":
+			scope = append([]string{s.body}, scope...)
+		default:
+			scope = append(scope, joinSection(s))
+		}
+	}
+	p.Scope = strings.TrimSpace(strings.Join(scope, "\n\n"))
+	return p
+}
+
+// ParseDesign parses design.md.
+func ParseDesign(doc string) *Design {
+	d := &Design{}
+	var approach []string
+	for _, s := range splitSections(doc, "## ") {
+		switch strings.ToLower(s.heading) {
+		case "decisions":
+			d.Decisions = s.body
+		case "data flow":
+			d.DataFlow = s.body
+		case "file changes":
+			for _, line := range strings.Split(s.body, "\n") {
+				if item, ok := listItem(line); ok {
+					if m := codeSpanRe.FindStringSubmatch(item); m != nil {
+						item = m[1]
+					}
+					d.FileChanges = append(d.FileChanges, item)
+				}
+			}
+		default:
+			approach = append(approach, joinSection(s))
+		}
+	}
+	d.Approach = strings.TrimSpace(strings.Join(approach, "\n\n"))
+	return d
+}
+
+var (
+	taskSectionRe = regexp.MustCompile(`^(\d+)\.\s+(.*)$`)
+	checkboxRe    = regexp.MustCompile(`^\s*[-*]\s+\[([ xX~-])\]\s+(.*)$`)
+	taskNumberRe  = regexp.MustCompile(`^(\d+(?:\.\d+[a-z]*)*)\.?\s+(.*)$`)
+)
+
+// ParseTasks parses tasks.md. "## N. Title" sections become parent tasks,
+// completed once all their subtasks are; checkbox items become subtasks,
+// numbered N.M in order when the item has no number of its own. [x] maps to
+// completed, [~] or [-] to in progress, and [ ] to pending.
+func ParseTasks(doc string) []*Task {
+	var tasks []*Task
+	var parent *Task
+	for _, line := range strings.Split(normalize(doc), "\n") {
+		if heading, ok := strings.CutPrefix(line, "## "); ok {
+			heading = strings.TrimSpace(heading)
+			parent = &Task{Number: fmt.Sprint(len(tasks) + 1), Description: heading}
+			if m := taskSectionRe.FindStringSubmatch(heading); m != nil {
+				parent.Number, parent.Description = m[1], m[2]
+			}
+			tasks = append(tasks, parent)
+			continue
+		}
+		m := checkboxRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		t := &Task{Description: strings.TrimSpace(m[2]), Status: TaskPending}
+		switch m[1] {
+		case "x", "X":
+			t.Status = TaskCompleted
+		case "~", "-":
+			t.Status = TaskInProgress
+		}
+		if n := taskNumberRe.FindStringSubmatch(t.Description); n != nil {
+			t.Number, t.Description = n[1], n[2]
+		}
+		if parent == nil {
+			if t.Number == "" {
+				t.Number = fmt.Sprint(len(tasks) + 1)
+			}
+			tasks = append(tasks, t)
+			continue
+		}
+		if t.Number == "" {
+			t.Number = fmt.Sprintf("%s.%d", parent.Number, len(parent.Subtasks)+1)
+		}
+		parent.Subtasks = append(parent.Subtasks, t)
+	}
+	for _, t := range tasks {
+		t.Status = sectionStatus(t)
+	}
+	return tasks
+}
+
+// sectionStatus derives a parent task's status from its subtasks.
+func sectionStatus(t *Task) string {
+	if len(t.Subtasks) == 0 {
+		if t.Status == "" {
+			return TaskPending
+		}
+		return t.Status
+	}
+	completed := 0
+	for _, s := range t.Subtasks {
+		switch s.Status {
+		case TaskCompleted:
+			completed++
+		case TaskInProgress:
+			return TaskInProgress
+		}
+	}
+	switch completed {
+	case len(t.Subtasks):
+		return TaskCompleted
+	case 0:
+		return TaskPending
+	default:
+		return TaskInProgress
+	}
+}
+
+var (
+	deltaHeadingRe = regexp.MustCompile(`(?i)^(ADDED|MODIFIED|REMOVED|RENAMED)\s+Requirements$`)
+	renameRe       = regexp.MustCompile("(?i)^\\s*[-*]\\s*(FROM|TO):\\s*`?(?:###\\s*)?(?:Requirement:\\s*)?([^`]+?)`?\\s*$")
+	clauseRe       = regexp.MustCompile(`^\s*[-*]\s+\*\*(GIVEN|WHEN|THEN|AND)\*\*:?\s*(.*)$`)
+	strengthRe     = regexp.MustCompile(`\b(SHALL NOT|MUST NOT|SHALL|MUST|SHOULD NOT|SHOULD|MAY)\b`)
+	strengthLineRe = regexp.MustCompile(`(?i)^\s*\*\*Strength\*\*:\s*(MUST|SHOULD|MAY)\s*$`)
+	codeSpanRe     = regexp.MustCompile("^`([^`]+)`$")
+)
+
+// ParseSpec parses a capability's spec.md.
+func ParseSpec(name, doc string) *Spec {
+	spec := &Spec{Name: name}
+	for _, s := range splitSections(doc, "## ") {
+		if strings.EqualFold(s.heading, "purpose") {
+			spec.Purpose = s.body
+			continue
+		}
+		delta := ""
+		if m := deltaHeadingRe.FindStringSubmatch(s.heading); m != nil {
+			delta = strings.ToLower(m[1])
+		}
+		// A RENAMED section lists - FROM: / - TO: pairs, optionally followed
+		// by the full blocks of the renamed requirements.
+		var renames []*Requirement
+		for _, rs := range splitSections(s.body, "### ") {
+			if rs.heading == "" && delta == DeltaRenamed {
+				renames = parseRenames(rs.body)
+				spec.Requirements = append(spec.Requirements, renames...)
+				continue
+			}
+			reqName, ok := cutLabel(rs.heading, "Requirement:")
+			if !ok {
+				continue
+			}
+			req := parseRequirement(reqName, rs.body)
+			req.DeltaType = delta
+			if r := findRequirement(renames, reqName); r != nil {
+				req.RenamedFrom = r.RenamedFrom
+				*r = *req
+				continue
+			}
+			spec.Requirements = append(spec.Requirements, req)
+		}
+	}
+	spec.DeltaType = specDelta(spec.Requirements)
+	return spec
+}
+
+// parseRequirement parses the body of a ### Requirement: block.
+func parseRequirement(name, body string) *Requirement {
+	req := &Requirement{Name: name}
+	parts := splitSections(body, "#### ")
+	for _, p := range parts {
+		if p.heading == "" {
+			req.Description, req.Strength = cutStrength(p.body)
+			continue
+		}
+		if scenName, ok := cutLabel(p.heading, "Scenario:"); ok {
+			req.Scenarios = append(req.Scenarios, parseScenario(scenName, p.body))
+		}
+	}
+	if req.Strength == "" {
+		req.Strength = ImpliedStrength(req.Description)
+	}
+	return req
+}
+
+// ImpliedStrength returns the strength of the first RFC 2119 keyword in a
+// requirement description: MUST for SHALL or MUST, SHOULD, or MAY. It
+// returns empty string when there is none.
+func ImpliedStrength(description string) string {
+	m := strengthRe.FindString(description)
+	switch {
+	case m == "":
+		return ""
+	case strings.HasPrefix(m, "SHOULD"):
+		return "SHOULD"
+	case m == "MAY":
+		return "MAY"
+	default:
+		return "MUST"
+	}
+}
+
+// cutStrength removes an explicit **Strength**: line from a requirement
+// description and returns the strength it names.
+func cutStrength(body string) (string, string) {
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		if m := strengthLineRe.FindStringSubmatch(line); m != nil {
+			rest := append(lines[:i:i], lines[i+1:]...)
+			return strings.TrimSpace(strings.Join(rest, "\n")), strings.ToUpper(m[1])
+		}
+	}
+	return body, ""
+}
+
+// findRequirement returns the requirement with the given name, or nil.
+func findRequirement(reqs []*Requirement, name string) *Requirement {
+	for _, r := range reqs {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// parseScenario reads - **GIVEN/WHEN/THEN/AND** clauses. Continuation lines
+// are folded into the clause before them.
+func parseScenario(name, body string) *Scenario {
+	sc := &Scenario{Name: name}
+	var last *string
+	for _, line := range strings.Split(body, "\n") {
+		m := clauseRe.FindStringSubmatch(line)
+		if m == nil {
+			if t := strings.TrimSpace(line); t != "" && last != nil {
+				*last = strings.TrimSpace(*last + " " + t)
+			}
+			continue
+		}
+		text := strings.TrimSpace(m[2])
+		switch strings.ToUpper(m[1]) {
+		case "GIVEN":
+			sc.Given, last = text, &sc.Given
+		case "WHEN":
+			sc.When, last = text, &sc.When
+		case "THEN":
+			sc.Then, last = text, &sc.Then
+		case "AND":
+			sc.AndAlso = append(sc.AndAlso, text)
+			last = &sc.AndAlso[len(sc.AndAlso)-1]
+		}
+	}
+	return sc
+}
+
+// parseRenames reads a RENAMED section's - FROM: / - TO: pairs.
+func parseRenames(body string) []*Requirement {
+	var out []*Requirement
+	from := ""
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		m := renameRe.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		switch strings.ToUpper(m[1]) {
+		case "FROM":
+			from = strings.TrimSpace(m[2])
+		case "TO":
+			out = append(out, &Requirement{Name: strings.TrimSpace(m[2]), RenamedFrom: from, DeltaType: DeltaRenamed})
+			from = ""
+		}
+	}
+	return out
+}
+
+// specDelta is the delta type shared by every requirement, or modified when
+// they differ.
+func specDelta(reqs []*Requirement) string {
+	if len(reqs) == 0 {
+		return ""
+	}
+	delta := reqs[0].DeltaType
+	for _, r := range reqs[1:] {
+		if r.DeltaType != delta {
+			return DeltaModified
+		}
+	}
+	if delta == DeltaRenamed {
+		return DeltaModified
+	}
+	return delta
+}
+
+// cutLabel strips a "Label:" prefix from a heading.
+func cutLabel(heading, label string) (string, bool) {
+	if len(heading) < len(label) || !strings.EqualFold(heading[:len(label)], label) {
+		return "", false
+	}
+	return strings.TrimSpace(heading[len(label):]), true
+}
+
+// listItem returns the text of a "- " or "* " list item.
+func listItem(line string) (string, bool) {
+	t := strings.TrimSpace(line)
+	for _, p := range []string{"- ", "* "} {
+		if item, ok := strings.CutPrefix(t, p); ok {
+			return strings.TrimSpace(item), true
+		}
+	}
+	return "", false
+}
+
+// joinSection renders a section back with its heading.
+func joinSection(s section) string {
+	if s.heading == "" {
+		return s.body
+	}
+	return strings.TrimSpace("## " + s.heading + "\n\n" + s.body)
+}
+
+// normalize converts line endings to \n.
+func normalize(doc string) string {
+	return strings.ReplaceAll(doc, "\r\n", "\n")
+}
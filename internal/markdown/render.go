@@ -0,0 +1,327 @@
+package markdown
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Files renders a change as the files of its directory, keyed by slash
+// separated path relative to the change directory. ReadFS of the result
+// yields the same change.
+func Files(ch *Change) map[string]string {
+	files := map[string]string{}
+	if ch.Proposal != nil {
+		files["proposal.md"] = RenderProposal(ch.Proposal)
+	}
+	if ch.Design != nil {
+		files["design.md"] = RenderDesign(ch.Design)
+	}
+	if len(ch.Tasks) > 0 {
+		files["tasks.md"] = RenderTasks(ch.Tasks)
+	}
+	for _, s := range ch.Specs {
+		files["specs/"+s.Name+"/spec.md"] = RenderSpec(s)
+	}
+	return files
+}
+
+// WriteDir writes the change's files under dir, creating directories as
+// needed. Files of the layout that the change has no content for are left
+// alone. It returns the paths written.
+func WriteDir(dir string, ch *Change) ([]string, error) {
+	return WriteFiles(dir, Files(ch))
+}
+
+// WriteFiles writes files, keyed by slash separated path as returned by
+// Files, under dir, creating directories as needed. It returns the paths
+// written.
+func WriteFiles(dir string, files map[string]string) ([]string, error) {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	written := make([]string, 0, len(paths))
+	for _, p := range paths {
+		full := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			return written, err
+		}
+		if err := os.WriteFile(full, []byte(files[p]), 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, full)
+	}
+	return written, nil
+}
+
+// doc accumulates ## sections, skipping empty ones.
+type doc struct {
+	b strings.Builder
+}
+
+func (d *doc) section(heading, body string) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return
+	}
+	if d.b.Len() > 0 {
+		d.b.WriteString("\n")
+	}
+	if heading != "" {
+		d.b.WriteString(heading + "\n\n")
+	}
+	d.b.WriteString(body + "\n")
+}
+
+func (d *doc) String() string { return d.b.String() }
+
+// RenderProposal renders proposal.md.
+func RenderProposal(p *Proposal) string {
+	var d doc
+	d.section("## Why", p.Intent)
+	d.section("## What Changes", p.Scope)
+	d.section("## Impact", p.Impact)
+	return d.String()
+}
+
+// RenderDesign renders design.md: the approach as written, then the
+// Decisions, Data Flow, and File Changes sections.
+func RenderDesign(ds *Design) string {
+	var d doc
+	d.section("", ds.Approach)
+	d.section("## Decisions", ds.Decisions)
+	d.section("## Data Flow", ds.DataFlow)
+	var files []string
+	for _, f := range ds.FileChanges {
+		if !strings.Contains(f, "`") {
+			f = "`" + f + "`"
+		}
+		files = append(files, "- "+f)
+	}
+	d.section("## File Changes", strings.Join(files, "\n"))
+	return d.String()
+}
+
+// RenderTasks renders tasks.md. Tasks with subtasks become "## N. Title"
+// sections of checkboxes; top-level tasks without subtasks are listed as
+// checkboxes before the first section so they read back as top-level.
+func RenderTasks(tasks []*Task) string {
+	var d doc
+	var loose []string
+	for _, t := range tasks {
+		if len(t.Subtasks) == 0 {
+			loose = append(loose, checkbox(t, 0)...)
+		}
+	}
+	d.section("", strings.Join(loose, "\n"))
+	for _, t := range tasks {
+		if len(t.Subtasks) == 0 {
+			continue
+		}
+		var items []string
+		for _, s := range t.Subtasks {
+			items = append(items, checkbox(s, 0)...)
+		}
+		d.section(fmt.Sprintf("## %s. %s", t.Number, t.Description), strings.Join(items, "\n"))
+	}
+	return d.String()
+}
+
+// checkbox renders a task and its subtasks as nested checkbox items.
+func checkbox(t *Task, depth int) []string {
+	mark := " "
+	switch t.Status {
+	case TaskCompleted:
+		mark = "x"
+	case TaskInProgress:
+		mark = "~"
+	}
+	line := fmt.Sprintf("%s- [%s] %s", strings.Repeat("  ", depth), mark, t.Description)
+	if t.Number != "" {
+		line = fmt.Sprintf("%s- [%s] %s %s", strings.Repeat("  ", depth), mark, t.Number, t.Description)
+	}
+	lines := []string{line}
+	for _, s := range t.Subtasks {
+		lines = append(lines, checkbox(s, depth+1)...)
+	}
+	return lines
+}
+
+// deltaOrder is the order of requirement groups in spec.md.
+var deltaOrder = []string{DeltaAdded, DeltaModified, DeltaRemoved, DeltaRenamed, ""}
+
+// RenderSpec renders a capability's spec.md, grouping requirements by delta
+// type.
+func RenderSpec(s *Spec) string {
+	var d doc
+	d.section("## Purpose", s.Purpose)
+	for _, delta := range deltaOrder {
+		var blocks []string
+		for _, r := range s.Requirements {
+			if r.DeltaType != delta {
+				continue
+			}
+			if delta == DeltaRenamed && r.RenamedFrom != "" {
+				blocks = append(blocks, fmt.Sprintf("- FROM: `### Requirement: %s`\n- TO: `### Requirement: %s`", r.RenamedFrom, r.Name))
+			}
+		}
+		for _, r := range s.Requirements {
+			if r.DeltaType == delta {
+				blocks = append(blocks, renderRequirement(r))
+			}
+		}
+		heading := "## Requirements"
+		if delta != "" {
+			heading = "## " + strings.ToUpper(delta) + " Requirements"
+		}
+		d.section(heading, strings.Join(blocks, "\n\n"))
+	}
+	return d.String()
+}
+
+// renderRequirement renders a ### Requirement: block. The strength is
+// spelled out only when the description's wording doesn't already imply it.
+func renderRequirement(r *Requirement) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "### Requirement: %s\n", r.Name)
+	if r.Description != "" {
+		b.WriteString(r.Description + "\n")
+	}
+	if r.Strength != "" && r.Strength != ImpliedStrength(r.Description) {
+		fmt.Fprintf(&b, "**Strength**: %s\n", r.Strength)
+	}
+	for _, sc := range r.Scenarios {
+		fmt.Fprintf(&b, "\n#### Scenario: %s\n", sc.Name)
+		for _, line := range clauses(sc) {
+			b.WriteString(line + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// clauses renders a scenario's - **GIVEN/WHEN/THEN/AND** lines.
+func clauses(sc *Scenario) []string {
+	var lines []string
+	for _, c := range []struct{ kw, text string }{{"GIVEN", sc.Given}, {"WHEN", sc.When}, {"THEN", sc.Then}} {
+		if c.text != "" {
+			lines = append(lines, fmt.Sprintf("- **%s** %s", c.kw, c.text))
+		}
+	}
+	for _, and := range sc.AndAlso {
+		lines = append(lines, "- **AND** "+and)
+	}
+	return lines
+}
+
+// RenderPR renders the whole change as one pull request description: the
+// proposal, each spec's requirements with their strength and scenarios, the
+// design, and the task checklist.
+func RenderPR(ch *Change) string {
+	var d doc
+	d.b.WriteString("# " + ch.Name + "\n")
+	if p := ch.Proposal; p != nil {
+		d.section("## Why", p.Intent)
+		d.section("## What Changes", demote(p.Scope))
+		d.section("## Impact", p.Impact)
+	}
+	if len(ch.Specs) > 0 {
+		var specs []string
+		for _, s := range ch.Specs {
+			specs = append(specs, renderPRSpec(s))
+		}
+		d.section("## Specs", strings.Join(specs, "\n\n"))
+	}
+	if ds := ch.Design; ds != nil {
+		d.section("## Design", demote(strings.TrimSpace(RenderDesign(ds))))
+	}
+	if len(ch.Tasks) > 0 {
+		var items []string
+		for _, t := range ch.Tasks {
+			items = append(items, checkbox(t, 0)...)
+		}
+		d.section("## Tasks", strings.Join(items, "\n"))
+	}
+	return d.String()
+}
+
+// renderPRSpec renders one spec for RenderPR.
+func renderPRSpec(s *Spec) string {
+	var b strings.Builder
+	b.WriteString("### " + s.Name)
+	if s.DeltaType != "" {
+		b.WriteString(" (" + s.DeltaType + ")")
+	}
+	b.WriteString("\n")
+	if s.Purpose != "" {
+		b.WriteString("\n" + s.Purpose + "\n")
+	}
+	for _, r := range s.Requirements {
+		b.WriteString("\n#### " + r.Name)
+		var tags []string
+		if r.Strength != "" {
+			tags = append(tags, r.Strength)
+		}
+		if r.DeltaType != "" {
+			tags = append(tags, r.DeltaType)
+		}
+		if r.RenamedFrom != "" {
+			tags = append(tags, "renamed from "+r.RenamedFrom)
+		}
+		if len(tags) > 0 {
+			b.WriteString(" (" + strings.Join(tags, ", ") + ")")
+		}
+		b.WriteString("\n")
+		if r.Description != "" {
+			b.WriteString("\n" + r.Description + "\n")
+		}
+		for _, sc := range r.Scenarios {
+			b.WriteString("\n**Scenario: " + sc.Name + "**\n")
+			for _, line := range clauses(sc) {
+				b.WriteString(line + "\n")
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// demote pushes headings in embedded text down one level so they nest under
+// the section that contains them.
+func demote(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			lines[i] = "#" + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SortTasks orders tasks, and recursively their subtasks, by number, comparing
+// dotted numbers segment by segment ("1.2" before "1.10").
+func SortTasks(tasks []*Task) {
+	sort.SliceStable(tasks, func(i, j int) bool { return lessNumber(tasks[i].Number, tasks[j].Number) })
+	for _, t := range tasks {
+		SortTasks(t.Subtasks)
+	}
+}
+
+func lessNumber(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] == bs[i] {
+			continue
+		}
+		ai, aerr := strconv.Atoi(as[i])
+		bi, berr := strconv.Atoi(bs[i])
+		if aerr == nil && berr == nil {
+			return ai < bi
+		}
+		return as[i] < bs[i]
+	}
+	return len(as) < len(bs)
+}
@@ -24,12 +24,18 @@ import (
 // Authentication: clients must send their Emergent project token as a Bearer
 // token in the Authorization header. This token is injected into the request
 // context and used by ClientFactory.ClientFor to create per-request SDK clients.
+//
+// Project selection: clients may pick the Emergent project per request with an
+// X-Project-ID header or a "project" query parameter on the MCP URL. The value
+// must be in the configured allowlist (see SetProjects).
 type HTTPServer struct {
-	server     *Server
-	cors       string
-	logger     *slog.Logger
-	sessions   sync.Map // sessionID -> *session
-	lastActive sync.Map // sessionID -> time.Time - track last activity
+	server           *Server
+	cors             string
+	logger           *slog.Logger
+	sessions         sync.Map        // sessionID -> *session
+	lastActive       sync.Map        // sessionID -> time.Time - track last activity
+	defaultProjectID string          // Project used when the request does not select one
+	allowedProjects  map[string]bool // Projects a request may select; "*" allows any
 }
 
 // session tracks an MCP session established via initialize.
@@ -47,6 +53,18 @@ func NewHTTPServer(server *Server, corsOrigins string, logger *slog.Logger) *HTT
 	}
 }
 
+// SetProjects configures per-request project selection. defaultProjectID is
+// used when a request does not name a project (empty falls back to the
+// EMERGENT_PROJECT_ID environment variable). allowed lists the project IDs a
+// request may select; "*" allows any. The default project is always allowed.
+func (h *HTTPServer) SetProjects(defaultProjectID string, allowed []string) {
+	h.defaultProjectID = defaultProjectID
+	h.allowedProjects = make(map[string]bool, len(allowed))
+	for _, id := range allowed {
+		h.allowedProjects[id] = true
+	}
+}
+
 // Handler returns an http.Handler that serves the MCP Streamable HTTP endpoint.
 // Mount this at your desired path (e.g. "/mcp").
 func (h *HTTPServer) Handler() http.Handler {
@@ -81,11 +99,18 @@ func (h *HTTPServer) handleMCP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Resolve the project selected by this request, if any.
+	projectID, ok := h.resolveProject(r)
+	if !ok {
+		http.Error(w, `{"error":"project not allowed"}`, http.StatusForbidden)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodPost:
-		h.handlePost(w, h.injectToken(r))
+		h.handlePost(w, h.injectProject(h.injectToken(r), projectID))
 	case http.MethodGet:
-		h.handleGet(w, h.injectToken(r))
+		h.handleGet(w, h.injectProject(h.injectToken(r), projectID))
 	case http.MethodDelete:
 		h.handleDelete(w, r)
 	default:
@@ -277,6 +302,33 @@ func (h *HTTPServer) injectToken(r *http.Request) *http.Request {
 	return r
 }
 
+// resolveProject returns the project ID selected by the request through the
+// X-Project-ID header or the "project" query parameter, falling back to the
+// default project. ok is false if the selected project is not allowed.
+func (h *HTTPServer) resolveProject(r *http.Request) (projectID string, ok bool) {
+	projectID = strings.TrimSpace(r.Header.Get("X-Project-ID"))
+	if projectID == "" {
+		projectID = strings.TrimSpace(r.URL.Query().Get("project"))
+	}
+	if projectID == "" || projectID == h.defaultProjectID {
+		return h.defaultProjectID, true
+	}
+	if h.allowedProjects["*"] || h.allowedProjects[projectID] {
+		return projectID, true
+	}
+	h.logger.Warn("rejected request for project not in allowlist", "project_id", projectID)
+	return "", false
+}
+
+// injectProject injects the resolved project ID into the request context so
+// ClientFactory.ClientFor targets that project.
+func (h *HTTPServer) injectProject(r *http.Request, projectID string) *http.Request {
+	if projectID == "" {
+		return r
+	}
+	return r.WithContext(emergent.WithProjectID(r.Context(), projectID))
+}
+
 // createSession generates a new session ID and stores it.
 func (h *HTTPServer) createSession() string {
 	b := make([]byte, 16)
@@ -314,7 +366,7 @@ func (h *HTTPServer) setCORS(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Accept, Mcp-Session-Id")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Accept, Mcp-Session-Id, X-Project-ID")
 	w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
 }
 
@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// IdempotencyArgument is the tool argument clients use to make a call safe to
+// retry. The same key can also be sent in the tools/call "_meta" object as
+// "idempotency_key" or "idempotencyKey".
+const IdempotencyArgument = "idempotency_key"
+
+// ErrIdempotencyKeyReused is returned by IdempotencyStore.Do when a key is
+// sent again with different arguments.
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used with different arguments")
+
+// idempotencyKeyProperty is the JSON Schema of the idempotency_key argument.
+const idempotencyKeyProperty = `{"type": "string", "description": "Optional key to make retries safe: repeating a call with the same key and arguments returns the original result instead of running the tool again"}`
+
+// IdempotentSchema returns a tool's input schema with the idempotency_key
+// argument added. Every tool that changes the graph wraps its schema in it.
+func IdempotentSchema(schema json.RawMessage) json.RawMessage {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(schema, &top); err != nil {
+		return schema
+	}
+	props := make(map[string]json.RawMessage)
+	if raw, ok := top["properties"]; ok {
+		if err := json.Unmarshal(raw, &props); err != nil {
+			return schema
+		}
+	}
+	props[IdempotencyArgument] = json.RawMessage(idempotencyKeyProperty)
+	raw, err := json.Marshal(props)
+	if err != nil {
+		return schema
+	}
+	top["properties"] = raw
+	out, err := json.MarshalIndent(top, "", "  ")
+	if err != nil {
+		return schema
+	}
+	return out
+}
+
+// IdempotencyStore remembers tool results by idempotency key for a fixed
+// window, so a client that retries a call after a timeout gets the original
+// result back instead of running the tool a second time.
+//
+// The store is local to the process. Objects created under a key are also
+// labelled in the graph (see emergent.WithIdempotencyKey), which covers
+// replays that arrive after a restart or after the window has expired.
+type IdempotencyStore struct {
+	mu      sync.Mutex
+	window  time.Duration
+	entries map[string]*idempotencyEntry
+}
+
+// idempotencyEntry is a stored or in-flight tool result.
+type idempotencyEntry struct {
+	digest  string        // arguments the key was first used with
+	done    chan struct{} // closed when result is set
+	result  *ToolsCallResult
+	expires time.Time
+}
+
+// NewIdempotencyStore creates a store that keeps results for the given window.
+func NewIdempotencyStore(window time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{
+		window:  window,
+		entries: make(map[string]*idempotencyEntry),
+	}
+}
+
+// Do returns the stored result for key if there is one, waiting for an
+// in-flight call with the same key to finish first. Otherwise it runs fn and
+// stores its result. Failed calls (errors or error results) are not stored,
+// so the client can retry them. The replayed return value reports whether the
+// result came from the store.
+//
+// digest fingerprints the call's arguments (see argumentsDigest); a key
+// reused with other arguments returns ErrIdempotencyKeyReused. Waiting for
+// an in-flight call stops when ctx is done.
+func (s *IdempotencyStore) Do(ctx context.Context, key, digest string, fn func() (*ToolsCallResult, error)) (result *ToolsCallResult, replayed bool, err error) {
+	for {
+		s.mu.Lock()
+		s.pruneLocked()
+		entry, ok := s.entries[key]
+		if !ok {
+			entry = &idempotencyEntry{digest: digest, done: make(chan struct{})}
+			s.entries[key] = entry
+			s.mu.Unlock()
+			break
+		}
+		s.mu.Unlock()
+		if entry.digest != digest {
+			return nil, false, ErrIdempotencyKeyReused
+		}
+
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+		if entry.result != nil {
+			return entry.result, true, nil
+		}
+		// The earlier attempt failed and was dropped; run it again.
+	}
+
+	result, err = fn()
+
+	s.mu.Lock()
+	entry := s.entries[key]
+	if err == nil && result != nil && !result.IsError {
+		entry.result = result
+		entry.expires = time.Now().Add(s.window)
+	} else {
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+	close(entry.done)
+
+	return result, false, err
+}
+
+// pruneLocked drops expired entries. Callers must hold s.mu.
+func (s *IdempotencyStore) pruneLocked() {
+	now := time.Now()
+	for k, e := range s.entries {
+		if e.result != nil && now.After(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+}
+
+// idempotencyKeyFrom extracts the idempotency key for a tool call from the
+// "_meta" object or the tool arguments. Returns "" if none was given.
+func idempotencyKeyFrom(params *ToolsCallParams) string {
+	if len(params.Meta) > 0 {
+		var meta map[string]any
+		if err := json.Unmarshal(params.Meta, &meta); err == nil {
+			for _, name := range []string{IdempotencyArgument, "idempotencyKey"} {
+				if v, ok := meta[name].(string); ok && v != "" {
+					return v
+				}
+			}
+		}
+	}
+	if len(params.Arguments) > 0 {
+		var args map[string]any
+		if err := json.Unmarshal(params.Arguments, &args); err == nil {
+			if v, ok := args[IdempotencyArgument].(string); ok && v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// argumentsDigest fingerprints a call's arguments, leaving out the
+// idempotency key itself, so a key can be bound to the call it was first
+// sent with. Arguments that are not a JSON object are hashed as sent.
+func argumentsDigest(arguments json.RawMessage) string {
+	b := []byte(arguments)
+	var args map[string]any
+	if err := json.Unmarshal(arguments, &args); err == nil {
+		delete(args, IdempotencyArgument)
+		if canonical, err := json.Marshal(args); err == nil { // map keys are sorted
+			b = canonical
+		}
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+// idempotencyScope builds the store key for a call. It includes the tool
+// name, the project, and a hash of the caller's token so that keys from
+// different tools, projects, or tenants never collide.
+func idempotencyScope(token, projectID, tool, key string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8]) + "/" + projectID + "/" + tool + "/" + key
+}
@@ -0,0 +1,146 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyStoreDo(t *testing.T) {
+	ok := &ToolsCallResult{Content: []ContentBlock{TextContent("done")}}
+	failed := ErrorResult("failed")
+	errTool := errors.New("tool failed")
+
+	// call is one Do on the store; fnResult and fnErr are what the tool
+	// returns if it runs.
+	type call struct {
+		key, digest  string
+		fnResult     *ToolsCallResult
+		fnErr        error
+		wantRan      bool
+		wantReplayed bool
+		wantErr      error
+	}
+	tests := []struct {
+		name   string
+		window time.Duration
+		calls  []call
+	}{
+		{
+			name:   "replays a stored result",
+			window: time.Hour,
+			calls: []call{
+				{key: "k", digest: "a", fnResult: ok, wantRan: true},
+				{key: "k", digest: "a", fnResult: ok, wantReplayed: true},
+			},
+		},
+		{
+			name:   "rejects a key reused with other arguments",
+			window: time.Hour,
+			calls: []call{
+				{key: "k", digest: "a", fnResult: ok, wantRan: true},
+				{key: "k", digest: "b", fnResult: ok, wantErr: ErrIdempotencyKeyReused},
+			},
+		},
+		{
+			name:   "keys are independent",
+			window: time.Hour,
+			calls: []call{
+				{key: "k1", digest: "a", fnResult: ok, wantRan: true},
+				{key: "k2", digest: "b", fnResult: ok, wantRan: true},
+			},
+		},
+		{
+			name:   "runs again once expired",
+			window: -time.Second,
+			calls: []call{
+				{key: "k", digest: "a", fnResult: ok, wantRan: true},
+				{key: "k", digest: "b", fnResult: ok, wantRan: true},
+			},
+		},
+		{
+			name:   "does not store error results",
+			window: time.Hour,
+			calls: []call{
+				{key: "k", digest: "a", fnResult: failed, wantRan: true},
+				{key: "k", digest: "a", fnResult: ok, wantRan: true},
+			},
+		},
+		{
+			name:   "does not store errors",
+			window: time.Hour,
+			calls: []call{
+				{key: "k", digest: "a", fnErr: errTool, wantRan: true, wantErr: errTool},
+				{key: "k", digest: "b", fnResult: ok, wantRan: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewIdempotencyStore(tt.window)
+			for i, c := range tt.calls {
+				ran := false
+				result, replayed, err := s.Do(context.Background(), c.key, c.digest, func() (*ToolsCallResult, error) {
+					ran = true
+					return c.fnResult, c.fnErr
+				})
+				if !errors.Is(err, c.wantErr) {
+					t.Fatalf("call %d: err = %v, want %v", i, err, c.wantErr)
+				}
+				if ran != c.wantRan || replayed != c.wantReplayed {
+					t.Fatalf("call %d: ran = %v, replayed = %v, want %v, %v", i, ran, replayed, c.wantRan, c.wantReplayed)
+				}
+				if err == nil && result != c.fnResult {
+					t.Fatalf("call %d: result = %+v, want %+v", i, result, c.fnResult)
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotencyStoreDoWaitsForInFlightCall(t *testing.T) {
+	ok := &ToolsCallResult{Content: []ContentBlock{TextContent("done")}}
+	s := NewIdempotencyStore(time.Hour)
+	started, release := make(chan struct{}), make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		_, _, err := s.Do(context.Background(), "k", "a", func() (*ToolsCallResult, error) {
+			close(started)
+			<-release
+			return ok, nil
+		})
+		first <- err
+	}()
+	<-started
+
+	// A retry whose client gave up stops waiting.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := s.Do(ctx, "k", "a", func() (*ToolsCallResult, error) {
+		t.Error("cancelled retry ran the tool")
+		return ok, nil
+	}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled retry: err = %v, want %v", err, context.Canceled)
+	}
+
+	// A retry that waits gets the first call's result.
+	second := make(chan *ToolsCallResult, 1)
+	go func() {
+		result, replayed, err := s.Do(context.Background(), "k", "a", func() (*ToolsCallResult, error) {
+			t.Error("waiting retry ran the tool")
+			return ok, nil
+		})
+		if err != nil || !replayed {
+			t.Errorf("waiting retry: replayed = %v, err = %v", replayed, err)
+		}
+		second <- result
+	}()
+	close(release)
+	if err := <-first; err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	if result := <-second; result != ok {
+		t.Fatalf("waiting retry: result = %+v, want %+v", result, ok)
+	}
+}
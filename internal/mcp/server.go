@@ -4,19 +4,23 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"os"
+
+	"github.com/emergent-company/specmcp/internal/emergent"
 )
 
 // Server implements the MCP protocol. It handles JSON-RPC dispatch independent
 // of transport. The Run method provides stdio transport; HTTPHandler provides
 // Streamable HTTP transport.
 type Server struct {
-	registry *Registry
-	info     ServerInfo
-	logger   *slog.Logger
+	registry    *Registry
+	info        ServerInfo
+	logger      *slog.Logger
+	idempotency *IdempotencyStore // Optional: replays results of calls with an idempotency key
 }
 
 // NewServer creates an MCP server with the given registry and server info.
@@ -28,6 +32,13 @@ func NewServer(registry *Registry, info ServerInfo, logger *slog.Logger) *Server
 	}
 }
 
+// SetIdempotencyStore enables idempotent replay of tool calls that carry an
+// idempotency key. Without a store, keys are still passed to the Emergent
+// client so retried creates reuse existing objects.
+func (s *Server) SetIdempotencyStore(store *IdempotencyStore) {
+	s.idempotency = store
+}
+
 // Run reads JSON-RPC requests from stdin and writes responses to stdout.
 // It blocks until stdin is closed or the context is cancelled.
 func (s *Server) Run(ctx context.Context) error {
@@ -197,7 +208,34 @@ func (s *Server) handleToolsCall(ctx context.Context, params json.RawMessage) (a
 
 	s.logger.Info("calling tool", "tool", callParams.Name)
 
-	result, err := tool.Execute(ctx, callParams.Arguments)
+	var (
+		result *ToolsCallResult
+		err    error
+	)
+	if key := idempotencyKeyFrom(&callParams); key != "" {
+		// Graph labels are bound to the arguments too, so a key reused for a
+		// different call after a restart doesn't pick up the first call's
+		// objects.
+		digest := argumentsDigest(callParams.Arguments)
+		ctx = emergent.WithIdempotencyKey(ctx, key+"/"+digest)
+		if s.idempotency != nil {
+			var replayed bool
+			scope := idempotencyScope(emergent.TokenFrom(ctx), emergent.ProjectIDFrom(ctx), callParams.Name, key)
+			result, replayed, err = s.idempotency.Do(ctx, scope, digest, func() (*ToolsCallResult, error) {
+				return tool.Execute(ctx, callParams.Arguments)
+			})
+			if errors.Is(err, ErrIdempotencyKeyReused) {
+				return ErrorResult(fmt.Sprintf("%v; use a new key for a different call", err)), nil
+			}
+			if replayed {
+				s.logger.Info("replaying idempotent tool result", "tool", callParams.Name)
+			}
+		} else {
+			result, err = tool.Execute(ctx, callParams.Arguments)
+		}
+	} else {
+		result, err = tool.Execute(ctx, callParams.Arguments)
+	}
 	if err != nil {
 		s.logger.Error("tool execution failed", "tool", callParams.Name, "error", err)
 		return ErrorResult(fmt.Sprintf("tool execution failed: %v", err)), nil
@@ -98,6 +98,7 @@ type ToolDefinition struct {
 type ToolsCallParams struct {
 	Name      string          `json:"name"`
 	Arguments json.RawMessage `json:"arguments,omitempty"`
+	Meta      json.RawMessage `json:"_meta,omitempty"`
 }
 
 // ToolsCallResult is returned for tools/call.
@@ -0,0 +1,398 @@
+// Package packs loads SpecMCP template packs and compares them with the
+// types compiled into an Emergent project, so that installs and upgrades can
+// be previewed before they are applied.
+package packs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/templatepacks"
+)
+
+// Pack is a parsed template pack file.
+type Pack struct {
+	Name              string
+	Version           string
+	Description       string
+	Author            string
+	ObjectTypes       map[string]ObjectType
+	RelationshipTypes map[string]RelationshipType
+
+	// raw holds the file's top-level fields so the pack can be uploaded in
+	// exactly the format it was written in (object-keyed or array).
+	raw map[string]json.RawMessage
+}
+
+// ObjectType is an object type schema from a pack or a project.
+type ObjectType struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description,omitempty"`
+	Properties  map[string]json.RawMessage `json:"properties,omitempty"`
+}
+
+// RelationshipType is a relationship type schema from a pack or a project.
+type RelationshipType struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	SourceTypes []string `json:"sourceTypes,omitempty"`
+	TargetTypes []string `json:"targetTypes,omitempty"`
+}
+
+// Load parses a template pack. Both the array format (Emergent v0.14+) and
+// the object-keyed format (v0.13) are accepted.
+func Load(data []byte) (*Pack, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing pack: %w", err)
+	}
+	var header struct {
+		Name        string `json:"name"`
+		Version     string `json:"version"`
+		Description string `json:"description"`
+		Author      string `json:"author"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("parsing pack header: %w", err)
+	}
+	if header.Name == "" || header.Version == "" {
+		return nil, fmt.Errorf("pack is missing name or version")
+	}
+
+	p := &Pack{
+		Name:              header.Name,
+		Version:           header.Version,
+		Description:       header.Description,
+		Author:            header.Author,
+		ObjectTypes:       make(map[string]ObjectType),
+		RelationshipTypes: make(map[string]RelationshipType),
+		raw:               raw,
+	}
+
+	objs, err := decodeSchemas[ObjectType](raw["object_type_schemas"])
+	if err != nil {
+		return nil, fmt.Errorf("parsing object_type_schemas: %w", err)
+	}
+	for name, ot := range objs {
+		ot.Name = name
+		p.ObjectTypes[name] = ot
+	}
+
+	rels, err := decodeSchemas[RelationshipType](raw["relationship_type_schemas"])
+	if err != nil {
+		return nil, fmt.Errorf("parsing relationship_type_schemas: %w", err)
+	}
+	for name, rt := range rels {
+		rt.Name = name
+		slices.Sort(rt.SourceTypes)
+		slices.Sort(rt.TargetTypes)
+		p.RelationshipTypes[name] = rt
+	}
+	return p, nil
+}
+
+// decodeSchemas decodes a schema list in either array form ([{name, ...}])
+// or object-keyed form ({name: {...}}) into a map keyed by name.
+func decodeSchemas[T any](data json.RawMessage) (map[string]T, error) {
+	out := make(map[string]T)
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return out, nil
+	}
+	if data[0] == '{' {
+		if err := json.Unmarshal(data, &out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		var named struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(item, &named); err != nil {
+			return nil, err
+		}
+		if named.Name == "" {
+			return nil, fmt.Errorf("schema entry without a name")
+		}
+		var v T
+		if err := json.Unmarshal(item, &v); err != nil {
+			return nil, fmt.Errorf("%s: %w", named.Name, err)
+		}
+		out[named.Name] = v
+	}
+	return out, nil
+}
+
+// CreateRequest builds the request that registers this pack with Emergent.
+// Schemas are sent as they appear in the pack file.
+func (p *Pack) CreateRequest() *templatepacks.CreatePackRequest {
+	req := &templatepacks.CreatePackRequest{
+		Name:                    p.Name,
+		Version:                 p.Version,
+		ObjectTypeSchemas:       p.raw["object_type_schemas"],
+		RelationshipTypeSchemas: p.raw["relationship_type_schemas"],
+		UIConfigs:               p.raw["ui_configs"],
+		ExtractionPrompts:       p.raw["extraction_prompts"],
+	}
+	if p.Description != "" {
+		req.Description = &p.Description
+	}
+	if p.Author != "" {
+		req.Author = &p.Author
+	}
+	return req
+}
+
+// Installed is the set of types compiled into a project, restricted to the
+// ones contributed by packs with a given name.
+type Installed struct {
+	ObjectTypes       map[string]ObjectType
+	RelationshipTypes map[string]RelationshipType
+
+	// Other lists type names that exist in the project but come from packs
+	// with a different name. A pack type that collides with one of these is
+	// reported as a conflict rather than an addition.
+	Other map[string]string // type name -> owning pack name
+}
+
+// FromCompiled extracts the types that packs named packName contribute to a
+// project. Relationship types are compiled as one entry per source/target
+// pair; they are merged back into one type per name.
+func FromCompiled(compiled *templatepacks.CompiledTypesResponse, packName string) *Installed {
+	in := &Installed{
+		ObjectTypes:       make(map[string]ObjectType),
+		RelationshipTypes: make(map[string]RelationshipType),
+		Other:             make(map[string]string),
+	}
+	for _, ot := range compiled.ObjectTypes {
+		if ot.PackName != "" && ot.PackName != packName {
+			in.Other[ot.Name] = ot.PackName
+			continue
+		}
+		props := make(map[string]json.RawMessage)
+		if len(ot.Properties) > 0 {
+			// Unparseable properties are compared as empty, which shows up
+			// as every property being added.
+			_ = json.Unmarshal(ot.Properties, &props)
+		}
+		in.ObjectTypes[ot.Name] = ObjectType{Name: ot.Name, Description: ot.Description, Properties: props}
+	}
+	for _, rt := range compiled.RelationshipTypes {
+		if rt.PackName != "" && rt.PackName != packName {
+			in.Other[rt.Name] = rt.PackName
+			continue
+		}
+		merged := in.RelationshipTypes[rt.Name]
+		merged.Name = rt.Name
+		if merged.Description == "" {
+			merged.Description = rt.Description
+		}
+		if rt.SourceType != "" && !slices.Contains(merged.SourceTypes, rt.SourceType) {
+			merged.SourceTypes = append(merged.SourceTypes, rt.SourceType)
+		}
+		if rt.TargetType != "" && !slices.Contains(merged.TargetTypes, rt.TargetType) {
+			merged.TargetTypes = append(merged.TargetTypes, rt.TargetType)
+		}
+		in.RelationshipTypes[rt.Name] = merged
+	}
+	for name, rt := range in.RelationshipTypes {
+		slices.Sort(rt.SourceTypes)
+		slices.Sort(rt.TargetTypes)
+		in.RelationshipTypes[name] = rt
+	}
+	return in
+}
+
+// Diff describes how a pack differs from what a project has installed.
+type Diff struct {
+	AddedTypes           []string             `json:"added_types,omitempty"`
+	RemovedTypes         []string             `json:"removed_types,omitempty"`
+	ChangedTypes         []TypeChange         `json:"changed_types,omitempty"`
+	AddedRelationships   []string             `json:"added_relationships,omitempty"`
+	RemovedRelationships []string             `json:"removed_relationships,omitempty"`
+	ChangedRelationships []RelationshipChange `json:"changed_relationships,omitempty"`
+	Conflicts            []string             `json:"conflicts,omitempty"` // pack types already owned by another pack
+}
+
+// TypeChange lists the property differences of one object type.
+type TypeChange struct {
+	Name              string           `json:"name"`
+	AddedProperties   []string         `json:"added_properties,omitempty"`
+	RemovedProperties []string         `json:"removed_properties,omitempty"`
+	ChangedProperties []PropertyChange `json:"changed_properties,omitempty"`
+}
+
+// PropertyChange names a property whose schema differs and the schema
+// fields (type, enum, description, ...) that differ.
+type PropertyChange struct {
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+}
+
+// RelationshipChange lists the endpoint differences of one relationship type.
+type RelationshipChange struct {
+	Name           string   `json:"name"`
+	AddedSources   []string `json:"added_sources,omitempty"`
+	RemovedSources []string `json:"removed_sources,omitempty"`
+	AddedTargets   []string `json:"added_targets,omitempty"`
+	RemovedTargets []string `json:"removed_targets,omitempty"`
+}
+
+// Empty reports whether the pack matches the project exactly.
+func (d *Diff) Empty() bool {
+	return len(d.AddedTypes) == 0 && len(d.RemovedTypes) == 0 && len(d.ChangedTypes) == 0 &&
+		len(d.AddedRelationships) == 0 && len(d.RemovedRelationships) == 0 &&
+		len(d.ChangedRelationships) == 0 && len(d.Conflicts) == 0
+}
+
+// Removals reports whether applying the pack would remove types, properties,
+// or relationship endpoints that the project currently has.
+func (d *Diff) Removals() bool {
+	if len(d.RemovedTypes) > 0 || len(d.RemovedRelationships) > 0 {
+		return true
+	}
+	for _, tc := range d.ChangedTypes {
+		if len(tc.RemovedProperties) > 0 {
+			return true
+		}
+	}
+	for _, rc := range d.ChangedRelationships {
+		if len(rc.RemovedSources) > 0 || len(rc.RemovedTargets) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare computes the differences between a pack and the installed types.
+// All lists are sorted by name.
+func Compare(p *Pack, in *Installed) *Diff {
+	d := &Diff{}
+
+	for _, name := range slices.Sorted(maps.Keys(p.ObjectTypes)) {
+		want := p.ObjectTypes[name]
+		have, ok := in.ObjectTypes[name]
+		if !ok {
+			if owner, taken := in.Other[name]; taken {
+				d.Conflicts = append(d.Conflicts, fmt.Sprintf("%s (owned by pack %q)", name, owner))
+			} else {
+				d.AddedTypes = append(d.AddedTypes, name)
+			}
+			continue
+		}
+		if tc := compareProperties(name, want.Properties, have.Properties); tc != nil {
+			d.ChangedTypes = append(d.ChangedTypes, *tc)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(in.ObjectTypes)) {
+		if _, ok := p.ObjectTypes[name]; !ok {
+			d.RemovedTypes = append(d.RemovedTypes, name)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(p.RelationshipTypes)) {
+		want := p.RelationshipTypes[name]
+		have, ok := in.RelationshipTypes[name]
+		if !ok {
+			if owner, taken := in.Other[name]; taken {
+				d.Conflicts = append(d.Conflicts, fmt.Sprintf("%s (owned by pack %q)", name, owner))
+			} else {
+				d.AddedRelationships = append(d.AddedRelationships, name)
+			}
+			continue
+		}
+		rc := RelationshipChange{
+			Name:           name,
+			AddedSources:   missing(want.SourceTypes, have.SourceTypes),
+			RemovedSources: missing(have.SourceTypes, want.SourceTypes),
+			AddedTargets:   missing(want.TargetTypes, have.TargetTypes),
+			RemovedTargets: missing(have.TargetTypes, want.TargetTypes),
+		}
+		if len(rc.AddedSources)+len(rc.RemovedSources)+len(rc.AddedTargets)+len(rc.RemovedTargets) > 0 {
+			d.ChangedRelationships = append(d.ChangedRelationships, rc)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(in.RelationshipTypes)) {
+		if _, ok := p.RelationshipTypes[name]; !ok {
+			d.RemovedRelationships = append(d.RemovedRelationships, name)
+		}
+	}
+	return d
+}
+
+// compareProperties returns the property differences of one object type, or
+// nil if there are none.
+func compareProperties(typeName string, want, have map[string]json.RawMessage) *TypeChange {
+	tc := &TypeChange{Name: typeName}
+	for _, prop := range slices.Sorted(maps.Keys(want)) {
+		haveSchema, ok := have[prop]
+		if !ok {
+			tc.AddedProperties = append(tc.AddedProperties, prop)
+			continue
+		}
+		if fields := changedFields(want[prop], haveSchema); len(fields) > 0 {
+			tc.ChangedProperties = append(tc.ChangedProperties, PropertyChange{Name: prop, Fields: fields})
+		}
+	}
+	for _, prop := range slices.Sorted(maps.Keys(have)) {
+		if _, ok := want[prop]; !ok {
+			tc.RemovedProperties = append(tc.RemovedProperties, prop)
+		}
+	}
+	if len(tc.AddedProperties)+len(tc.RemovedProperties)+len(tc.ChangedProperties) == 0 {
+		return nil
+	}
+	return tc
+}
+
+// changedFields compares two property schemas and returns the names of the
+// top-level schema fields that differ. Key order and whitespace are ignored.
+func changedFields(a, b json.RawMessage) []string {
+	var am, bm map[string]any
+	if json.Unmarshal(a, &am) != nil || json.Unmarshal(b, &bm) != nil {
+		if !bytes.Equal(bytes.TrimSpace(a), bytes.TrimSpace(b)) {
+			return []string{"schema"}
+		}
+		return nil
+	}
+	var fields []string
+	for _, k := range slices.Sorted(maps.Keys(am)) {
+		if !equalJSON(am[k], bm[k]) {
+			fields = append(fields, k)
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(bm)) {
+		if _, ok := am[k]; !ok {
+			fields = append(fields, k)
+		}
+	}
+	return fields
+}
+
+// equalJSON compares two decoded JSON values. Marshalling sorts map keys, so
+// equal values produce equal bytes.
+func equalJSON(a, b any) bool {
+	ab, _ := json.Marshal(a)
+	bb, _ := json.Marshal(b)
+	return bytes.Equal(ab, bb)
+}
+
+// missing returns the elements of a that are not in b.
+func missing(a, b []string) []string {
+	var out []string
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
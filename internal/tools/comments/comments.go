@@ -0,0 +1,371 @@
+// Package comments implements the SpecMCP comment tools:
+// spec_add_comment, spec_list_comments, spec_resolve_comment.
+//
+// Comments are feedback threads on any graph entity. A thread stays open
+// until resolved; open threads on a workflow artifact soft-block
+// spec_mark_ready and are counted in spec_status.
+package comments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/mcp"
+)
+
+func unknownAuthor(name string) *mcp.ToolsCallResult {
+	return mcp.ErrorResult(fmt.Sprintf(
+		"%q is not an Agent or Actor. Create one with spec_artifact (artifact_type 'agent' with type 'human' or 'ai', or 'actor').",
+		name,
+	))
+}
+
+// --- spec_add_comment ---
+
+type addCommentParams struct {
+	EntityID string   `json:"entity_id,omitempty"`
+	ReplyTo  string   `json:"reply_to,omitempty"`
+	Author   string   `json:"author"`
+	Body     string   `json:"body"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// AddComment starts a comment thread on an entity or replies to a comment.
+type AddComment struct {
+	factory *emergent.ClientFactory
+}
+
+// NewAddComment creates an AddComment tool.
+func NewAddComment(factory *emergent.ClientFactory) *AddComment {
+	return &AddComment{factory: factory}
+}
+
+func (t *AddComment) Name() string { return "spec_add_comment" }
+
+func (t *AddComment) Description() string {
+	return "Comment on any graph entity (e.g. a Requirement or Design) as a named Agent or Actor, or reply to an existing comment with reply_to. A comment without reply_to starts a thread that stays open until spec_resolve_comment; open threads on a workflow artifact soft-block spec_mark_ready."
+}
+
+func (t *AddComment) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entity_id": {
+      "type": "string",
+      "description": "ID of the entity to comment on (not needed with reply_to)"
+    },
+    "reply_to": {
+      "type": "string",
+      "description": "ID of the comment to reply to"
+    },
+    "author": {
+      "type": "string",
+      "description": "Name of the commenting Agent or Actor"
+    },
+    "body": {
+      "type": "string",
+      "description": "Comment text"
+    },
+    "tags": {
+      "type": "array",
+      "items": {"type": "string"},
+      "description": "Optional tags"
+    }
+  },
+  "required": ["author", "body"]
+}`))
+}
+
+func (t *AddComment) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p addCommentParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if p.Author == "" {
+		return mcp.ErrorResult("author is required"), nil
+	}
+	if p.Body == "" {
+		return mcp.ErrorResult("body is required"), nil
+	}
+	if p.EntityID == "" && p.ReplyTo == "" {
+		return mcp.ErrorResult("entity_id or reply_to is required"), nil
+	}
+
+	// A reply goes on its parent's entity.
+	var parent *emergent.Comment
+	var replyTarget string
+	targetID := p.EntityID
+	if p.ReplyTo != "" {
+		parent, err = client.GetComment(ctx, p.ReplyTo)
+		if err != nil {
+			return mcp.ErrorResult(fmt.Sprintf("comment not found: %v", err)), nil
+		}
+		ctx = emergent.WithBranch(ctx, parent.BranchID)
+		parentTarget, err := client.CommentTarget(ctx, parent.ID)
+		if err != nil {
+			return nil, err
+		}
+		if targetID == "" {
+			targetID = parentTarget
+		}
+		replyTarget = parentTarget
+	}
+	target, err := client.GetObject(ctx, targetID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("entity not found: %v", err)), nil
+	}
+	if target.Type == emergent.TypeComment {
+		return mcp.ErrorResult("entity_id is a comment; use reply_to to reply to it"), nil
+	}
+	if parent != nil && replyTarget != target.ID && replyTarget != target.CanonicalID {
+		return mcp.ErrorResult("reply_to is a comment on a different entity than entity_id"), nil
+	}
+	ctx = emergent.WithBranch(ctx, emergent.ObjectBranch(target))
+
+	author, err := client.FindParticipant(ctx, p.Author)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return unknownAuthor(p.Author), nil
+	}
+
+	cm := &emergent.Comment{
+		Name:       fmt.Sprintf("%s comment on %s", author.Name, entityName(target.Properties, target.ID)),
+		Body:       p.Body,
+		Author:     author.Name,
+		AuthorType: author.Type,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+		Tags:       p.Tags,
+	}
+	parentID := ""
+	if parent != nil {
+		cm.ReplyTo = parent.CanonicalID
+		parentID = parent.ID
+	}
+	created, err := client.CreateComment(ctx, target.ID, parentID, author.ID, cm)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := client.ListComments(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.JSONResult(map[string]any{
+		"comment_id":   created.ID,
+		"entity_id":    target.ID,
+		"type":         target.Type,
+		"reply_to":     parentID,
+		"open_threads": emergent.OpenThreads(comments),
+		"message":      fmt.Sprintf("%s commented on %s %q", author.Name, target.Type, entityName(target.Properties, target.ID)),
+	})
+}
+
+// entityName returns an entity's name, or its ID when it has none.
+func entityName(props map[string]any, id string) string {
+	if name, _ := props["name"].(string); name != "" {
+		return name
+	}
+	return id
+}
+
+// --- spec_list_comments ---
+
+type listCommentsParams struct {
+	EntityID       string `json:"entity_id"`
+	UnresolvedOnly bool   `json:"unresolved_only,omitempty"`
+}
+
+// ListComments lists the comment threads on an entity.
+type ListComments struct {
+	factory *emergent.ClientFactory
+}
+
+// NewListComments creates a ListComments tool.
+func NewListComments(factory *emergent.ClientFactory) *ListComments {
+	return &ListComments{factory: factory}
+}
+
+func (t *ListComments) Name() string { return "spec_list_comments" }
+
+func (t *ListComments) Description() string {
+	return "List the comment threads on an entity, oldest first, each with its replies, author, and resolved state. Use unresolved_only to see only open threads."
+}
+
+func (t *ListComments) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entity_id": {
+      "type": "string",
+      "description": "ID of the entity whose comments to list"
+    },
+    "unresolved_only": {
+      "type": "boolean",
+      "description": "Only return unresolved threads (default: false)"
+    }
+  },
+  "required": ["entity_id"]
+}`)
+}
+
+func (t *ListComments) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p listCommentsParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if p.EntityID == "" {
+		return mcp.ErrorResult("entity_id is required"), nil
+	}
+	target, err := client.GetObject(ctx, p.EntityID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("entity not found: %v", err)), nil
+	}
+	ctx = emergent.WithBranch(ctx, emergent.ObjectBranch(target))
+
+	comments, err := client.ListComments(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	threads := []*emergent.CommentThread{}
+	open := 0
+	for _, thread := range emergent.BuildThreads(comments) {
+		if !thread.Resolved {
+			open++
+		} else if p.UnresolvedOnly {
+			continue
+		}
+		threads = append(threads, thread)
+	}
+
+	return mcp.JSONResult(map[string]any{
+		"entity_id":    target.ID,
+		"type":         target.Type,
+		"name":         entityName(target.Properties, target.ID),
+		"threads":      threads,
+		"open_threads": open,
+		"comments":     len(comments),
+	})
+}
+
+// --- spec_resolve_comment ---
+
+type resolveCommentParams struct {
+	CommentID  string `json:"comment_id"`
+	ResolvedBy string `json:"resolved_by,omitempty"`
+	Reopen     bool   `json:"reopen,omitempty"`
+}
+
+// ResolveComment resolves (or reopens) a comment thread.
+type ResolveComment struct {
+	factory *emergent.ClientFactory
+}
+
+// NewResolveComment creates a ResolveComment tool.
+func NewResolveComment(factory *emergent.ClientFactory) *ResolveComment {
+	return &ResolveComment{factory: factory}
+}
+
+func (t *ResolveComment) Name() string { return "spec_resolve_comment" }
+
+func (t *ResolveComment) Description() string {
+	return "Resolve the thread a comment belongs to (any comment in the thread may be given), or reopen it with reopen=true. Resolved threads no longer block spec_mark_ready."
+}
+
+func (t *ResolveComment) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "comment_id": {
+      "type": "string",
+      "description": "ID of a comment in the thread"
+    },
+    "resolved_by": {
+      "type": "string",
+      "description": "Who resolved the thread"
+    },
+    "reopen": {
+      "type": "boolean",
+      "description": "Reopen a resolved thread instead (default: false)"
+    }
+  },
+  "required": ["comment_id"]
+}`))
+}
+
+func (t *ResolveComment) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p resolveCommentParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if p.CommentID == "" {
+		return mcp.ErrorResult("comment_id is required"), nil
+	}
+	cm, err := client.GetComment(ctx, p.CommentID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("comment not found: %v", err)), nil
+	}
+	ctx = emergent.WithBranch(ctx, cm.BranchID)
+	targetID, err := client.CommentTarget(ctx, cm.ID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := client.ListComments(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range comments {
+		if other.CanonicalID == cm.CanonicalID {
+			cm = other
+		}
+	}
+	root := emergent.ThreadRoot(comments, cm)
+
+	props := map[string]any{"resolved": !p.Reopen}
+	if p.Reopen {
+		props["resolved_by"] = ""
+		props["resolved_at"] = ""
+	} else {
+		props["resolved_by"] = p.ResolvedBy
+		props["resolved_at"] = time.Now().Format(time.RFC3339)
+	}
+	updated, err := client.UpdateComment(ctx, root.ID, props)
+	if err != nil {
+		return nil, fmt.Errorf("updating comment: %w", err)
+	}
+	*root = *updated
+
+	action := "Resolved"
+	if p.Reopen {
+		action = "Reopened"
+	}
+	return mcp.JSONResult(map[string]any{
+		"thread_id":    root.ID,
+		"entity_id":    targetID,
+		"resolved":     root.Resolved,
+		"open_threads": emergent.OpenThreads(comments),
+		"message":      fmt.Sprintf("%s thread started by %s", action, root.Author),
+	})
+}
@@ -6,21 +6,26 @@ import (
 	"fmt"
 
 	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/guards"
 	"github.com/emergent-company/specmcp/internal/mcp"
 )
 
 // --- spec_create_constitution ---
 
 type createParams struct {
-	Name                 string   `json:"name"`
-	Version              string   `json:"version"`
-	Principles           string   `json:"principles,omitempty"`
-	Guardrails           []string `json:"guardrails,omitempty"`
-	TestingRequirements  string   `json:"testing_requirements,omitempty"`
-	SecurityRequirements string   `json:"security_requirements,omitempty"`
-	PatternsRequired     []string `json:"patterns_required,omitempty"`
-	PatternsForbidden    []string `json:"patterns_forbidden,omitempty"`
-	Tags                 []string `json:"tags,omitempty"`
+	Name                 string               `json:"name"`
+	Version              string               `json:"version"`
+	Principles           string               `json:"principles,omitempty"`
+	Guardrails           []string             `json:"guardrails,omitempty"`
+	TestingRequirements  string               `json:"testing_requirements,omitempty"`
+	SecurityRequirements string               `json:"security_requirements,omitempty"`
+	PatternsRequired     []string             `json:"patterns_required,omitempty"`
+	PatternsForbidden    []string             `json:"patterns_forbidden,omitempty"`
+	ReviewPolicy         []string             `json:"review_policy,omitempty"`
+	WorkflowStages       []string             `json:"workflow_stages,omitempty"`
+	GuardPolicy          []string             `json:"guard_policy,omitempty"`
+	GuardRules           []emergent.GuardRule `json:"guard_rules,omitempty"`
+	Tags                 []string             `json:"tags,omitempty"`
 }
 
 // CreateConstitution creates or updates a project Constitution.
@@ -36,10 +41,10 @@ func NewCreateConstitution(factory *emergent.ClientFactory) *CreateConstitution
 
 func (t *CreateConstitution) Name() string { return "spec_create_constitution" }
 func (t *CreateConstitution) Description() string {
-	return "Create or update the project's constitution. A constitution defines project principles, guardrails, testing requirements, and pattern mandates. Must exist before any changes can be created."
+	return "Create or update the project's constitution. A constitution defines project principles, guardrails, testing requirements, pattern mandates, the review policy for marking artifacts ready, and optionally the workflow stages, guard severity overrides, and custom guard rules. Must exist before any changes can be created."
 }
 func (t *CreateConstitution) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "name": {
@@ -77,6 +82,37 @@ func (t *CreateConstitution) InputSchema() json.RawMessage {
       "items": {"type": "string"},
       "description": "Pattern names that no entity may use"
     },
+    "review_policy": {
+      "type": "array",
+      "items": {"type": "string"},
+      "description": "Approvals spec_mark_ready requires per artifact type, e.g. ['Proposal: 1 human', 'Design: 1 human']. Overrides the configured review policy for the types it names"
+    },
+    "workflow_stages": {
+      "type": "array",
+      "items": {"type": "string"},
+      "description": "Workflow stages in order, as '<name>[?]: [artifact types] [after <stage>, ...]' ('?' marks an optional stage), e.g. ['propose: proposal', 'specify: spec, requirement, scenario, scenario_step after propose', 'design?: design after specify', 'implement: task after specify, design']. Replaces the configured workflow"
+    },
+    "guard_policy": {
+      "type": "array",
+      "items": {"type": "string"},
+      "description": "Guard severity overrides, as '<guard>[@<app>]: <suggestion|warning|soft_block|hard_block|off>', e.g. ['patterns_seeded: warning', 'context_discovery@billing-api: off']. An @app override applies to changes scoped to that App. Overrides the configured guard overrides per guard and app, but cannot turn off or downgrade the hard blocks (e.g. review_approval, constitution_required)"
+    },
+    "guard_rules": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string", "description": "Rule name, lowercase words joined by underscores"},
+          "on": {"type": "array", "items": {"type": "string", "enum": ["new_change", "artifact", "mark_ready", "archive"]}, "description": "Operations the rule runs on"},
+          "expr": {"type": "string", "description": "expr-lang expression that must be true, e.g. 'all(designs, {len(.file_changes ?? []) > 0})'"},
+          "severity": {"type": "string", "enum": ["suggestion", "warning", "soft_block", "hard_block"], "description": "Severity when the expression is false (default: warning)"},
+          "message": {"type": "string", "description": "Why the rule failed"},
+          "remedy": {"type": "string", "description": "How to fix it"}
+        },
+        "required": ["name", "on", "expr", "message"]
+      },
+      "description": "Custom guards written as expressions over the change, the artifact, counts, and the change's proposals, specs, requirements, scenarios, designs, and tasks (see specmcp://guardrails). Replace configured rules of the same name"
+    },
     "tags": {
       "type": "array",
       "items": {"type": "string"},
@@ -84,7 +120,7 @@ func (t *CreateConstitution) InputSchema() json.RawMessage {
     }
   },
   "required": ["name", "version"]
-}`)
+}`))
 }
 
 func (t *CreateConstitution) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
@@ -98,12 +134,51 @@ func (t *CreateConstitution) Execute(ctx context.Context, params json.RawMessage
 	if p.Version == "" {
 		return mcp.ErrorResult("version is required"), nil
 	}
+	if _, err := guards.ParseReviewPolicy(p.ReviewPolicy); err != nil {
+		return mcp.ErrorResult(err.Error()), nil
+	}
+	var workflow *guards.Workflow
+	if len(p.WorkflowStages) > 0 {
+		w, err := guards.ParseWorkflow(p.WorkflowStages)
+		if err != nil {
+			return mcp.ErrorResult(err.Error()), nil
+		}
+		workflow = w
+	}
+	policy, err := guards.ParseGuardPolicy(p.GuardPolicy)
+	if err != nil {
+		return mcp.ErrorResult(err.Error()), nil
+	}
+	rules, err := guards.CompileRules(p.GuardRules)
+	if err != nil {
+		return mcp.ErrorResult(err.Error()), nil
+	}
 
 	client, err := t.factory.ClientFor(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("creating client: %w", err)
 	}
 
+	// Overrides must name a guard that exists: a built-in guard of the
+	// workflow this constitution sets (or the project's), or a custom rule.
+	if len(policy.Overrides) > 0 {
+		if workflow == nil {
+			if workflow, err = guards.LoadWorkflow(ctx, client); err != nil {
+				return nil, fmt.Errorf("loading workflow: %w", err)
+			}
+		}
+		loaded, err := guards.LoadRules(ctx, client)
+		if err != nil {
+			return nil, fmt.Errorf("loading guard rules: %w", err)
+		}
+		if err := policy.CheckGuardNames(workflow, append(loaded, rules...)); err != nil {
+			return mcp.ErrorResult(err.Error()), nil
+		}
+		if err := policy.CheckRelaxations(workflow); err != nil {
+			return mcp.ErrorResult(err.Error()), nil
+		}
+	}
+
 	// Build properties
 	props := map[string]any{
 		"name":    p.Name,
@@ -127,6 +202,18 @@ func (t *CreateConstitution) Execute(ctx context.Context, params json.RawMessage
 	if len(p.PatternsForbidden) > 0 {
 		props["patterns_forbidden"] = p.PatternsForbidden
 	}
+	if len(p.ReviewPolicy) > 0 {
+		props["review_policy"] = p.ReviewPolicy
+	}
+	if len(p.WorkflowStages) > 0 {
+		props["workflow_stages"] = p.WorkflowStages
+	}
+	if len(p.GuardPolicy) > 0 {
+		props["guard_policy"] = p.GuardPolicy
+	}
+	if len(p.GuardRules) > 0 {
+		props["guard_rules"] = p.GuardRules
+	}
 
 	// Upsert so re-running updates the existing constitution
 	key := p.Name
@@ -58,7 +58,7 @@ User says "Use Zod for validation" → type: technology_choice`
 }
 
 func (t *CreateTool) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
 		"type": "object",
 		"required": ["type", "domain", "title", "description"],
 		"properties": {
@@ -124,7 +124,7 @@ func (t *CreateTool) InputSchema() json.RawMessage {
 				"description": "Additional tags"
 			}
 		}
-	}`)
+	}`))
 }
 
 type CreateInput struct {
@@ -37,6 +37,7 @@ type Report struct {
 	Warnings       int            `json:"warnings"`
 	Issues         []Issue        `json:"issues"`
 	Summary        string         `json:"summary"`
+	Truncated      bool           `json:"truncated,omitempty"` // an entity listing hit the list cap
 }
 
 // --- spec_janitor_run ---
@@ -81,7 +82,7 @@ If serious issues are found and create_proposal=true, a maintenance proposal is
 }
 
 func (t *JanitorRun) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "create_proposal": {
@@ -102,7 +103,7 @@ func (t *JanitorRun) InputSchema() json.RawMessage {
       "description": "Automatically fix minor issues (naming, etc.)"
     }
   }
-}`)
+}`))
 }
 
 func (t *JanitorRun) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
@@ -231,12 +232,13 @@ func (t *JanitorRun) Execute(ctx context.Context, params json.RawMessage) (*mcp.
 
 // verifyChanges checks all Change entities for issues.
 func (t *JanitorRun) verifyChanges(ctx context.Context, client *emergent.Client, report *Report) error {
-	changes, err := client.ListObjects(ctx, &graph.ListObjectsOptions{
+	changes, truncated, err := client.ListAllObjects(ctx, &graph.ListObjectsOptions{
 		Type: emergent.TypeChange,
-	})
+	}, 0)
 	if err != nil {
 		return fmt.Errorf("listing changes: %w", err)
 	}
+	report.Truncated = report.Truncated || truncated
 
 	report.EntityCounts[emergent.TypeChange] = len(changes)
 
@@ -304,13 +306,14 @@ func (t *JanitorRun) verifyArtifacts(ctx context.Context, client *emergent.Clien
 	}
 
 	for _, artType := range artifactTypes {
-		artifacts, err := client.ListObjects(ctx, &graph.ListObjectsOptions{
+		artifacts, truncated, err := client.ListAllObjects(ctx, &graph.ListObjectsOptions{
 			Type: artType,
-		})
+		}, 0)
 		if err != nil {
 			t.logger.Error("error listing artifacts", "type", artType, "error", err)
 			continue
 		}
+		report.Truncated = report.Truncated || truncated
 
 		report.EntityCounts[artType] = len(artifacts)
 
@@ -347,12 +350,13 @@ func (t *JanitorRun) verifyArtifacts(ctx context.Context, client *emergent.Clien
 // verifyRelationships checks for broken or missing relationships.
 func (t *JanitorRun) verifyRelationships(ctx context.Context, client *emergent.Client, report *Report) error {
 	// Check that all Specs have at least one Requirement
-	specs, err := client.ListObjects(ctx, &graph.ListObjectsOptions{
+	specs, truncated, err := client.ListAllObjects(ctx, &graph.ListObjectsOptions{
 		Type: emergent.TypeSpec,
-	})
+	}, 0)
 	if err != nil {
 		return fmt.Errorf("listing specs: %w", err)
 	}
+	report.Truncated = report.Truncated || truncated
 
 	for _, spec := range specs {
 		edgesResp, err := client.GetObjectEdges(ctx, spec.ID, &graph.GetObjectEdgesOptions{
@@ -372,12 +376,13 @@ func (t *JanitorRun) verifyRelationships(ctx context.Context, client *emergent.C
 	}
 
 	// Check that all Requirements have at least one Scenario
-	reqs, err := client.ListObjects(ctx, &graph.ListObjectsOptions{
+	reqs, truncated, err := client.ListAllObjects(ctx, &graph.ListObjectsOptions{
 		Type: emergent.TypeRequirement,
-	})
+	}, 0)
 	if err != nil {
 		return fmt.Errorf("listing requirements: %w", err)
 	}
+	report.Truncated = report.Truncated || truncated
 
 	for _, req := range reqs {
 		edgesResp, err := client.GetObjectEdges(ctx, req.ID, &graph.GetObjectEdgesOptions{
@@ -470,7 +475,7 @@ func (t *JanitorRun) checkOrphaned(ctx context.Context, client *emergent.Client,
 			EntityID:    obj.ID,
 			EntityType:  obj.Type,
 			Description: fmt.Sprintf("%s '%s' is not connected to any Change", obj.Type, safeKey(obj.Key)),
-			Suggestion:  "Associate this artifact with a Change or archive it",
+			Suggestion:  "Associate this artifact with a Change, or remove it with spec_delete_artifact",
 		})
 	}
 
@@ -92,47 +92,53 @@ func (t *SuggestPatterns) Execute(ctx context.Context, params json.RawMessage) (
 	entityIDs := emergent.NewIDSet(obj.ID, obj.CanonicalID)
 
 	// Find patterns used by other entities of the same type
-	sameTypeEntities, err := client.ListObjects(ctx, &graph.ListObjectsOptions{
+	sameTypeEntities, truncated, err := client.ListAllObjects(ctx, &graph.ListObjectsOptions{
 		Type:  entityType,
-		Limit: 20,
-	})
+		Limit: 100,
+	}, 0)
 	if err != nil {
 		return nil, fmt.Errorf("listing same-type entities: %w", err)
 	}
 
-	// Count pattern usage across similar entities using batch edge lookups
-	rawUsage := make(map[string]int)
+	// Also check if any constitution requires patterns for this entity type
+	constitutions, constitutionsTruncated, err := client.ListAllObjects(ctx, &graph.ListObjectsOptions{
+		Type: emergent.TypeConstitution,
+	}, 0)
+	if err != nil {
+		constitutions = nil
+	}
+	truncated = truncated || constitutionsTruncated
+
+	// Count pattern usage across similar entities, and the patterns
+	// constitutions require, from a single expansion of all their edges.
+	rootIDs := make([]string, 0, len(sameTypeEntities)+len(constitutions))
 	for _, entity := range sameTypeEntities {
-		if entityIDs[entity.ID] {
-			continue // canonical-aware self-skip
+		if !entityIDs[entity.ID] { // canonical-aware self-skip
+			rootIDs = append(rootIDs, entity.ID)
 		}
-		edges, err := client.GetObjectEdges(ctx, entity.ID, &graph.GetObjectEdgesOptions{
-			Types:     []string{emergent.RelUsesPattern},
-			Direction: "outgoing",
+	}
+	for _, c := range constitutions {
+		rootIDs = append(rootIDs, c.ID)
+	}
+	rawUsage := make(map[string]int)
+	if len(rootIDs) > 0 {
+		resp, err := client.ExpandGraph(ctx, &graph.GraphExpandRequest{
+			RootIDs:           rootIDs,
+			Direction:         "outgoing",
+			MaxDepth:          1,
+			MaxNodes:          len(rootIDs) + 1000,
+			MaxEdges:          5000,
+			RelationshipTypes: []string{emergent.RelUsesPattern, emergent.RelRequiresPattern},
 		})
 		if err != nil {
-			continue
+			return nil, fmt.Errorf("expanding pattern usage: %w", err)
 		}
-		for _, rel := range edges.Outgoing {
-			rawUsage[rel.DstID]++
-		}
-	}
-
-	// Also check if any constitution requires patterns for this entity type
-	constitutions, err := client.ListObjects(ctx, &graph.ListObjectsOptions{
-		Type:  emergent.TypeConstitution,
-		Limit: 10,
-	})
-	if err == nil {
-		for _, c := range constitutions {
-			edges, err := client.GetObjectEdges(ctx, c.ID, &graph.GetObjectEdgesOptions{
-				Types:     []string{emergent.RelRequiresPattern},
-				Direction: "outgoing",
-			})
-			if err != nil {
-				continue
-			}
-			for _, rel := range edges.Outgoing {
+		truncated = truncated || resp.Truncated
+		for _, rel := range resp.Edges {
+			switch rel.Type {
+			case emergent.RelUsesPattern:
+				rawUsage[rel.DstID]++
+			case emergent.RelRequiresPattern:
 				rawUsage[rel.DstID] += 10 // boost required patterns
 			}
 		}
@@ -219,6 +225,7 @@ func (t *SuggestPatterns) Execute(ctx context.Context, params json.RawMessage) (
 		"suggestions":   suggestions,
 		"already_using": len(usedPatterns),
 		"count":         len(suggestions),
+		"truncated":     truncated,
 	})
 }
 
@@ -242,14 +249,14 @@ func (t *ApplyPattern) Description() string {
 	return "Apply a pattern to an entity by creating a uses_pattern relationship. Returns the pattern's example code and usage guidance."
 }
 func (t *ApplyPattern) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "entity_id": {"type": "string", "description": "ID of the entity to apply the pattern to"},
     "pattern_id": {"type": "string", "description": "ID of the pattern to apply"}
   },
   "required": ["entity_id", "pattern_id"]
-}`)
+}`))
 }
 
 func (t *ApplyPattern) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
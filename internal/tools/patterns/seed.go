@@ -162,7 +162,7 @@ func (t *SeedPatterns) Description() string {
 	return "Seed the graph with standard patterns from the built-in pattern library. Includes naming, structural, behavioral, and error_handling patterns. Skips patterns that already exist by name (unless force=true)."
 }
 func (t *SeedPatterns) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "types": {
@@ -175,7 +175,7 @@ func (t *SeedPatterns) InputSchema() json.RawMessage {
       "description": "If true, recreate patterns even if they already exist (default: false)"
     }
   }
-}`)
+}`))
 }
 
 func (t *SeedPatterns) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
@@ -0,0 +1,393 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/mcp"
+)
+
+// changeTrackingRelTypes link a Change to the entity versions it created,
+// modified, or referenced.
+var changeTrackingRelTypes = []string{
+	emergent.RelChangeCreates,
+	emergent.RelChangeModifies,
+	emergent.RelChangeReferences,
+}
+
+// versionChange is a Change that produced or touched one entity version.
+type versionChange struct {
+	ID           string `json:"id"`
+	CanonicalID  string `json:"canonical_id"`
+	Name         string `json:"name"`
+	Relationship string `json:"relationship"`
+}
+
+// changesByVersion maps each version's ID to the Changes whose tracking
+// relationships point at that exact version.
+func changesByVersion(ctx context.Context, client *emergent.Client, versions []*graph.GraphObject) (map[string][]versionChange, error) {
+	changes := make(map[string]*graph.GraphObject)
+	out := make(map[string][]versionChange, len(versions))
+	for _, v := range versions {
+		edges, err := client.GetObjectEdges(ctx, v.ID, &graph.GetObjectEdgesOptions{
+			Types:     changeTrackingRelTypes,
+			Direction: "incoming",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("getting changes for version %d: %w", v.Version, err)
+		}
+		for _, e := range edges.Incoming {
+			if e.DstID != v.ID {
+				continue
+			}
+			ch, ok := changes[e.SrcID]
+			if !ok {
+				if ch, err = client.GetObject(ctx, e.SrcID); err != nil {
+					return nil, fmt.Errorf("getting change %s: %w", e.SrcID, err)
+				}
+				changes[e.SrcID] = ch
+			}
+			name, _ := ch.Properties["name"].(string)
+			out[v.ID] = append(out[v.ID], versionChange{
+				ID:           ch.ID,
+				CanonicalID:  ch.CanonicalID,
+				Name:         name,
+				Relationship: e.Type,
+			})
+		}
+	}
+	return out, nil
+}
+
+// --- spec_history ---
+
+type historyParams struct {
+	EntityID          string `json:"entity_id"`
+	IncludeProperties bool   `json:"include_properties,omitempty"`
+}
+
+// History lists the versions of an entity and the changes behind them.
+type History struct {
+	factory *emergent.ClientFactory
+}
+
+func NewHistory(factory *emergent.ClientFactory) *History {
+	return &History{factory: factory}
+}
+
+func (t *History) Name() string { return "spec_history" }
+func (t *History) Description() string {
+	return "List the versions of an entity, oldest first, with each version's timestamp, the change(s) that created, modified, or referenced it, and the server's change summary. Use spec_diff to compare two versions."
+}
+func (t *History) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entity_id": {"type": "string", "description": "ID of any version of the entity, or its canonical ID"},
+    "include_properties": {"type": "boolean", "description": "Include the full properties of each version (default: false)"}
+  },
+  "required": ["entity_id"]
+}`)
+}
+
+func (t *History) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p historyParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+	if p.EntityID == "" {
+		return mcp.ErrorResult("entity_id is required"), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	versions, err := client.GetObjectHistory(ctx, p.EntityID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("entity not found: %v", err)), nil
+	}
+	if len(versions) == 0 {
+		return mcp.ErrorResult(fmt.Sprintf("no versions found for %s", p.EntityID)), nil
+	}
+	byVersion, err := changesByVersion(ctx, client, versions)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]map[string]any, 0, len(versions))
+	for _, v := range versions {
+		entry := map[string]any{
+			"id":         v.ID,
+			"version":    v.Version,
+			"created_at": v.CreatedAt,
+		}
+		if v.ChangeSummary != nil {
+			entry["change_summary"] = v.ChangeSummary
+		}
+		if chs := byVersion[v.ID]; len(chs) > 0 {
+			entry["changes"] = chs
+		}
+		if v.DeletedAt != nil {
+			entry["deleted_at"] = v.DeletedAt
+		}
+		if p.IncludeProperties {
+			entry["properties"] = v.Properties
+			entry["labels"] = v.Labels
+		}
+		results = append(results, entry)
+	}
+
+	latest := versions[len(versions)-1]
+	return mcp.JSONResult(map[string]any{
+		"canonical_id": latest.CanonicalID,
+		"type":         latest.Type,
+		"name":         latest.Properties["name"],
+		"versions":     results,
+		"count":        len(results),
+	})
+}
+
+// --- spec_diff ---
+
+type diffParams struct {
+	EntityID    string `json:"entity_id"`
+	FromVersion int    `json:"from_version,omitempty"`
+	ToVersion   int    `json:"to_version,omitempty"`
+	FromChange  string `json:"from_change,omitempty"`
+	ToChange    string `json:"to_change,omitempty"`
+}
+
+// propertyDiff is one property that differs between two versions. Nested
+// objects are compared key by key, so Path may be dotted.
+type propertyDiff struct {
+	Path string `json:"path"`
+	Op   string `json:"op"` // added, removed, or changed
+	From any    `json:"from,omitempty"`
+	To   any    `json:"to,omitempty"`
+}
+
+// Diff compares two versions of an entity property by property.
+type Diff struct {
+	factory *emergent.ClientFactory
+}
+
+func NewDiff(factory *emergent.ClientFactory) *Diff {
+	return &Diff{factory: factory}
+}
+
+func (t *Diff) Name() string { return "spec_diff" }
+func (t *Diff) Description() string {
+	return "Property-level diff between two versions of an entity. Pick each side by version number or by change (the version that change created or modified). Defaults: to is the latest version, from is the version before to — so to_change alone shows what that change did to the entity."
+}
+func (t *Diff) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entity_id": {"type": "string", "description": "ID of any version of the entity, or its canonical ID"},
+    "from_version": {"type": "integer", "description": "Version number to diff from (default: the version before to)"},
+    "to_version": {"type": "integer", "description": "Version number to diff to (default: latest)"},
+    "from_change": {"type": "string", "description": "Diff from the version this change (ID or name) produced. Excludes from_version."},
+    "to_change": {"type": "string", "description": "Diff to the version this change (ID or name) produced. Excludes to_version."}
+  },
+  "required": ["entity_id"]
+}`)
+}
+
+func (t *Diff) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p diffParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+	if p.EntityID == "" {
+		return mcp.ErrorResult("entity_id is required"), nil
+	}
+	if p.FromVersion > 0 && p.FromChange != "" {
+		return mcp.ErrorResult("from_version and from_change are mutually exclusive"), nil
+	}
+	if p.ToVersion > 0 && p.ToChange != "" {
+		return mcp.ErrorResult("to_version and to_change are mutually exclusive"), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	versions, err := client.GetObjectHistory(ctx, p.EntityID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("entity not found: %v", err)), nil
+	}
+	if len(versions) == 0 {
+		return mcp.ErrorResult(fmt.Sprintf("no versions found for %s", p.EntityID)), nil
+	}
+
+	var byVersion map[string][]versionChange
+	if p.FromChange != "" || p.ToChange != "" {
+		if byVersion, err = changesByVersion(ctx, client, versions); err != nil {
+			return nil, err
+		}
+	}
+
+	to := versions[len(versions)-1]
+	switch {
+	case p.ToChange != "":
+		if to, err = changeVersion(ctx, client, versions, byVersion, p.ToChange); err != nil {
+			return mcp.ErrorResult(err.Error()), nil
+		}
+	case p.ToVersion > 0:
+		if to = versionNumbered(versions, p.ToVersion); to == nil {
+			return mcp.ErrorResult(fmt.Sprintf("version %d not found", p.ToVersion)), nil
+		}
+	}
+
+	var from *graph.GraphObject
+	switch {
+	case p.FromChange != "":
+		if from, err = changeVersion(ctx, client, versions, byVersion, p.FromChange); err != nil {
+			return mcp.ErrorResult(err.Error()), nil
+		}
+	case p.FromVersion > 0:
+		if from = versionNumbered(versions, p.FromVersion); from == nil {
+			return mcp.ErrorResult(fmt.Sprintf("version %d not found", p.FromVersion)), nil
+		}
+	default:
+		// The newest version older than to; nil when to is the first.
+		for _, v := range versions {
+			if v.Version < to.Version {
+				from = v
+			}
+		}
+	}
+
+	var fromProps map[string]any
+	var fromLabels []string
+	if from != nil {
+		fromProps, fromLabels = from.Properties, from.Labels
+	}
+	diffs := []propertyDiff{}
+	diffProperties("", fromProps, to.Properties, &diffs)
+
+	result := map[string]any{
+		"canonical_id":   to.CanonicalID,
+		"type":           to.Type,
+		"name":           to.Properties["name"],
+		"to":             versionRef(to, byVersion),
+		"properties":     diffs,
+		"count":          len(diffs),
+		"labels_added":   missingFrom(to.Labels, fromLabels),
+		"labels_removed": missingFrom(fromLabels, to.Labels),
+	}
+	if from != nil {
+		result["from"] = versionRef(from, byVersion)
+	} else {
+		result["message"] = "to is the first version; every property is reported as added"
+	}
+	return mcp.JSONResult(result)
+}
+
+// changeVersion returns the newest version that the given change (ID or
+// name) is linked to.
+func changeVersion(ctx context.Context, client *emergent.Client, versions []*graph.GraphObject, byVersion map[string][]versionChange, ref string) (*graph.GraphObject, error) {
+	change, err := client.FindChange(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("looking up change %q: %v", ref, err)
+	}
+	canonical := ""
+	if change != nil {
+		canonical = change.CanonicalID
+	} else {
+		obj, err := client.GetObject(ctx, ref)
+		if err != nil || obj.Type != emergent.TypeChange {
+			return nil, fmt.Errorf("change %q not found", ref)
+		}
+		canonical = obj.CanonicalID
+	}
+	for i := len(versions) - 1; i >= 0; i-- {
+		for _, ch := range byVersion[versions[i].ID] {
+			if ch.CanonicalID == canonical {
+				return versions[i], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("change %q did not create, modify, or reference this entity", ref)
+}
+
+// versionNumbered returns the version with the given number, or nil.
+func versionNumbered(versions []*graph.GraphObject, n int) *graph.GraphObject {
+	for _, v := range versions {
+		if v.Version == n {
+			return v
+		}
+	}
+	return nil
+}
+
+// versionRef summarizes one side of a diff.
+func versionRef(v *graph.GraphObject, byVersion map[string][]versionChange) map[string]any {
+	ref := map[string]any{
+		"id":         v.ID,
+		"version":    v.Version,
+		"created_at": v.CreatedAt,
+	}
+	if chs := byVersion[v.ID]; len(chs) > 0 {
+		ref["changes"] = chs
+	}
+	return ref
+}
+
+// diffProperties appends the differences between from and to, recursing into
+// nested objects. Keys are visited in sorted order for stable output.
+func diffProperties(prefix string, from, to map[string]any, out *[]propertyDiff) {
+	keys := make([]string, 0, len(from)+len(to))
+	for k := range from {
+		keys = append(keys, k)
+	}
+	for k := range to {
+		if _, ok := from[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		a, inFrom := from[k]
+		b, inTo := to[k]
+		switch {
+		case !inFrom:
+			*out = append(*out, propertyDiff{Path: path, Op: "added", To: b})
+		case !inTo:
+			*out = append(*out, propertyDiff{Path: path, Op: "removed", From: a})
+		default:
+			am, aIsMap := a.(map[string]any)
+			bm, bIsMap := b.(map[string]any)
+			if aIsMap && bIsMap {
+				diffProperties(path, am, bm, out)
+			} else if !reflect.DeepEqual(a, b) {
+				*out = append(*out, propertyDiff{Path: path, Op: "changed", From: a, To: b})
+			}
+		}
+	}
+}
+
+// missingFrom returns the labels in a that are not in b.
+func missingFrom(a, b []string) []string {
+	out := []string{}
+	for _, l := range a {
+		if !slices.Contains(b, l) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
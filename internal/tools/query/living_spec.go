@@ -0,0 +1,182 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/mcp"
+)
+
+// --- spec_get_living_spec ---
+
+type getLivingSpecParams struct {
+	Domain           string `json:"domain,omitempty"`
+	IncludeHistory   bool   `json:"include_history,omitempty"`
+	ExcludeScenarios bool   `json:"exclude_scenarios,omitempty"`
+}
+
+// GetLivingSpec returns the current truth for a domain: the requirements its
+// living spec includes after merging every archived change.
+type GetLivingSpec struct {
+	factory *emergent.ClientFactory
+}
+
+func NewGetLivingSpec(factory *emergent.ClientFactory) *GetLivingSpec {
+	return &GetLivingSpec{factory: factory}
+}
+
+func (t *GetLivingSpec) Name() string { return "spec_get_living_spec" }
+func (t *GetLivingSpec) Description() string {
+	return "Get the living spec of a domain: the current requirements and scenarios after merging the delta specs of all archived changes, with the change each requirement came from. Omit domain to list all living specs."
+}
+func (t *GetLivingSpec) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "domain": {"type": "string", "description": "Domain of the living spec (a Spec's domain, or its name if it has none). Omit to list all living specs."},
+    "include_history": {"type": "boolean", "description": "Also list the merges that produced each revision (default: false)"},
+    "exclude_scenarios": {"type": "boolean", "description": "Omit the scenarios of each requirement (default: false)"}
+  }
+}`)
+}
+
+func (t *GetLivingSpec) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p getLivingSpecParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if p.Domain == "" {
+		return t.list(ctx, client)
+	}
+
+	living, err := client.FindByTypeAndKey(ctx, emergent.TypeLivingSpec, p.Domain)
+	if err != nil {
+		return nil, fmt.Errorf("looking up living spec: %w", err)
+	}
+	if living == nil {
+		return mcp.ErrorResult(fmt.Sprintf("no living spec for domain %q; living specs are created when a change with a Spec in that domain is archived", p.Domain)), nil
+	}
+
+	edges, err := client.GetObjectEdges(ctx, living.ID, &graph.GetObjectEdgesOptions{
+		Types: []string{emergent.RelIncludesRequirement, emergent.RelMergedInto},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting living spec relationships: %w", err)
+	}
+
+	requirements := make([]map[string]any, 0, len(edges.Outgoing))
+	for _, e := range edges.Outgoing {
+		if e.Type != emergent.RelIncludesRequirement {
+			continue
+		}
+		req, err := client.GetObject(ctx, e.DstID)
+		if err != nil {
+			return nil, fmt.Errorf("getting requirement %s: %w", e.DstID, err)
+		}
+		entry := map[string]any{
+			"id":          req.ID,
+			"name":        req.Properties["name"],
+			"description": req.Properties["description"],
+			"strength":    req.Properties["strength"],
+			"change":      e.Properties["change"],
+			"revision":    e.Properties["revision"],
+			"delta":       e.Properties["delta"],
+		}
+		if !p.ExcludeScenarios {
+			scenarios, err := client.GetRelatedObjects(ctx, req.ID, emergent.RelHasScenario)
+			if err != nil {
+				return nil, fmt.Errorf("getting scenarios of %s: %w", req.ID, err)
+			}
+			scens := make([]map[string]any, 0, len(scenarios))
+			for _, s := range scenarios {
+				scens = append(scens, map[string]any{
+					"id":    s.ID,
+					"name":  s.Properties["name"],
+					"given": s.Properties["given"],
+					"when":  s.Properties["when"],
+					"then":  s.Properties["then"],
+				})
+			}
+			entry["scenarios"] = scens
+		}
+		requirements = append(requirements, entry)
+	}
+	sort.Slice(requirements, func(i, j int) bool {
+		a, _ := requirements[i]["name"].(string)
+		b, _ := requirements[j]["name"].(string)
+		return a < b
+	})
+
+	result := map[string]any{
+		"id":           living.ID,
+		"canonical_id": living.CanonicalID,
+		"domain":       p.Domain,
+		"name":         living.Properties["name"],
+		"purpose":      living.Properties["purpose"],
+		"status":       living.Properties["status"],
+		"revision":     living.Properties["revision"],
+		"last_change":  living.Properties["last_change"],
+		"requirements": requirements,
+		"count":        len(requirements),
+	}
+
+	if p.IncludeHistory {
+		history := make([]map[string]any, 0, len(edges.Incoming))
+		for _, e := range edges.Incoming {
+			if e.Type != emergent.RelMergedInto {
+				continue
+			}
+			history = append(history, map[string]any{
+				"spec_id":  e.SrcID,
+				"change":   e.Properties["change"],
+				"revision": e.Properties["revision"],
+			})
+		}
+		sort.Slice(history, func(i, j int) bool {
+			return revisionOf(history[i]) < revisionOf(history[j])
+		})
+		result["history"] = history
+	}
+
+	return mcp.JSONResult(result)
+}
+
+// list returns a summary of every living spec.
+func (t *GetLivingSpec) list(ctx context.Context, client *emergent.Client) (*mcp.ToolsCallResult, error) {
+	items, truncated, err := client.ListAllObjects(ctx, &graph.ListObjectsOptions{Type: emergent.TypeLivingSpec}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing living specs: %w", err)
+	}
+	results := make([]map[string]any, 0, len(items))
+	for _, obj := range items {
+		results = append(results, map[string]any{
+			"id":          obj.ID,
+			"domain":      obj.Properties["domain"],
+			"name":        obj.Properties["name"],
+			"status":      obj.Properties["status"],
+			"revision":    obj.Properties["revision"],
+			"last_change": obj.Properties["last_change"],
+		})
+	}
+	return mcp.JSONResult(map[string]any{
+		"living_specs": results,
+		"count":        len(results),
+		"truncated":    truncated,
+	})
+}
+
+// revisionOf reads a numeric revision from a decoded JSON map.
+func revisionOf(m map[string]any) float64 {
+	v, _ := m["revision"].(float64)
+	return v
+}
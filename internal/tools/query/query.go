@@ -653,6 +653,7 @@ func (t *ImpactAnalysis) Execute(ctx context.Context, params json.RawMessage) (*
 type listChangesParams struct {
 	Status string `json:"status,omitempty"`
 	Limit  int    `json:"limit,omitempty"`
+	Order  string `json:"order,omitempty"`
 }
 
 type ListChanges struct {
@@ -665,7 +666,7 @@ func NewListChanges(factory *emergent.ClientFactory) *ListChanges {
 
 func (t *ListChanges) Name() string { return "spec_list_changes" }
 func (t *ListChanges) Description() string {
-	return "List all changes, optionally filtered by status (active, archived)."
+	return "List all changes, optionally filtered by status (active, archived). With order=dependency, changes come after the changes they depend on, each listing its depends_on and the unarchived dependencies it is waiting_on."
 }
 func (t *ListChanges) InputSchema() json.RawMessage {
 	return json.RawMessage(`{
@@ -678,7 +679,12 @@ func (t *ListChanges) InputSchema() json.RawMessage {
     },
     "limit": {
       "type": "integer",
-      "description": "Max results (default 50)"
+      "description": "Max results (default: all changes, up to the server's list cap). The result sets truncated=true when more changes exist."
+    },
+    "order": {
+      "type": "string",
+      "description": "dependency: list each change after the changes it depends on (default: server order)",
+      "enum": ["dependency"]
     }
   }
 }`)
@@ -695,7 +701,11 @@ func (t *ListChanges) Execute(ctx context.Context, params json.RawMessage) (*mcp
 		return nil, fmt.Errorf("creating client: %w", err)
 	}
 
-	changes, err := client.ListChanges(ctx, p.Status)
+	if p.Order == "dependency" {
+		return t.dependencyOrder(ctx, client, p)
+	}
+
+	changes, truncated, err := client.ListChanges(ctx, p.Status, p.Limit)
 	if err != nil {
 		return nil, fmt.Errorf("listing changes: %w", err)
 	}
@@ -712,11 +722,59 @@ func (t *ListChanges) Execute(ctx context.Context, params json.RawMessage) (*mcp
 	}
 
 	return mcp.JSONResult(map[string]any{
-		"changes": results,
-		"count":   len(results),
+		"changes":   results,
+		"count":     len(results),
+		"truncated": truncated,
 	})
 }
 
+// dependencyOrder lists changes topologically by depends_on_change.
+func (t *ListChanges) dependencyOrder(ctx context.Context, client *emergent.Client, p listChangesParams) (*mcp.ToolsCallResult, error) {
+	g, err := client.LoadChangeGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []map[string]any{}
+	truncated := false
+	for _, id := range g.Order() {
+		ch := g.Changes[id]
+		if p.Status != "" && ch.Status != p.Status {
+			continue
+		}
+		if p.Limit > 0 && len(results) == p.Limit {
+			truncated = true
+			break
+		}
+		var waitingOn []string
+		for _, dep := range g.DependsOn[id] {
+			if d, ok := g.Changes[dep]; !ok || d.Status != emergent.StatusArchived {
+				waitingOn = append(waitingOn, g.Name(dep))
+			}
+		}
+		results = append(results, map[string]any{
+			"id":           ch.ID,
+			"canonical_id": ch.CanonicalID,
+			"name":         ch.Name,
+			"status":       ch.Status,
+			"tags":         ch.Tags,
+			"depends_on":   g.Names(g.DependsOn[id]),
+			"waiting_on":   waitingOn,
+		})
+	}
+
+	result := map[string]any{
+		"changes":   results,
+		"count":     len(results),
+		"order":     "dependency",
+		"truncated": truncated || g.Truncated,
+	}
+	if len(g.Dangling) > 0 {
+		result["dangling_dependencies"] = g.Dangling
+	}
+	return mcp.JSONResult(result)
+}
+
 // --- spec_get_change ---
 
 type getChangeParams struct {
@@ -0,0 +1,412 @@
+// Package review implements the SpecMCP review tools:
+// spec_request_review, spec_approve_review, spec_reject_review.
+//
+// Reviews record who approved or rejected a workflow artifact. The review
+// policy (config or Constitution review_policy) says how many approvals an
+// artifact type needs, and spec_mark_ready enforces it.
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/mcp"
+)
+
+// loadArtifact fetches a workflow artifact and scopes ctx to its branch.
+// A non-empty message reports why the entity cannot be reviewed.
+func loadArtifact(ctx context.Context, client *emergent.Client, id string) (context.Context, *graph.GraphObject, string) {
+	if id == "" {
+		return ctx, nil, "entity_id is required"
+	}
+	obj, err := client.GetObject(ctx, id)
+	if err != nil {
+		return ctx, nil, fmt.Sprintf("entity not found: %v", err)
+	}
+	if !emergent.IsWorkflowArtifactType(obj.Type) {
+		return ctx, nil, fmt.Sprintf("entity type %q is not a workflow artifact. Only Proposal, Spec, Requirement, Scenario, and Design can be reviewed.", obj.Type)
+	}
+	return emergent.WithBranch(ctx, emergent.ObjectBranch(obj)), obj, ""
+}
+
+// artifactName returns an artifact's name, or its ID when it has none.
+func artifactName(obj *graph.GraphObject) string {
+	if name, _ := obj.Properties["name"].(string); name != "" {
+		return name
+	}
+	return obj.ID
+}
+
+// latestReview returns the reviewer's most recent Review, or nil.
+func latestReview(reviews []*emergent.Review, name string) *emergent.Review {
+	var latest *emergent.Review
+	for _, r := range reviews {
+		if r.Reviewer == name {
+			latest = r
+		}
+	}
+	return latest
+}
+
+// resolveReviewer finds the participant a decision is recorded as and checks
+// that the caller may act as it. Without a name, the caller's own Agent or
+// Actor (bound by subject) is used. A participant bound to a user can only
+// be used with that user's token, and a human participant must be bound to
+// one, so nobody can decide as a human they are not. A non-empty message
+// reports why the caller cannot review as name.
+func resolveReviewer(ctx context.Context, client *emergent.Client, caller *emergent.Caller, name string) (*emergent.Participant, string, error) {
+	if name == "" {
+		if caller == nil {
+			return nil, "reviewer is required: the token is not tied to an Emergent user", nil
+		}
+		r, err := client.FindParticipantBySubject(ctx, caller.Subject)
+		if errors.Is(err, emergent.ErrSubjectBound) {
+			return nil, err.Error() + "; clear the subject on all but one", nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if r == nil {
+			return nil, fmt.Sprintf("no Agent or Actor is bound to your Emergent user %s; set its subject to %s, or name an AI agent as reviewer", caller.Email, caller.Subject), nil
+		}
+		return r, "", nil
+	}
+
+	r, err := client.FindParticipant(ctx, name)
+	if err != nil {
+		return nil, "", err
+	}
+	if r == nil {
+		return nil, unknownReviewer(name), nil
+	}
+	switch {
+	case r.Subject != "" && (caller == nil || caller.Subject != r.Subject):
+		return nil, fmt.Sprintf("reviewer %q is bound to another Emergent user; decide as yourself or as an AI agent", name), nil
+	case r.Subject == "" && r.Type == "human":
+		return nil, fmt.Sprintf("reviewer %q is human but not bound to an Emergent user, so the decision cannot be attributed; set its subject to the user's ID first", name), nil
+	}
+	return r, "", nil
+}
+
+func unknownReviewer(name string) string {
+	return fmt.Sprintf(
+		"reviewer %q is not an Agent or Actor. Create one with spec_artifact (artifact_type 'agent' with type 'human' or 'ai', or 'actor').",
+		name,
+	)
+}
+
+// --- spec_request_review ---
+
+type requestReviewParams struct {
+	EntityID    string   `json:"entity_id"`
+	Reviewers   []string `json:"reviewers"`
+	RequestedBy string   `json:"requested_by,omitempty"`
+	Comment     string   `json:"comment,omitempty"`
+}
+
+// RequestReview asks Agents or Actors to review a workflow artifact.
+type RequestReview struct {
+	factory *emergent.ClientFactory
+}
+
+// NewRequestReview creates a RequestReview tool.
+func NewRequestReview(factory *emergent.ClientFactory) *RequestReview {
+	return &RequestReview{factory: factory}
+}
+
+func (t *RequestReview) Name() string { return "spec_request_review" }
+
+func (t *RequestReview) Description() string {
+	return "Request reviews of a workflow artifact (Proposal, Spec, Requirement, Scenario, Design) from Agents or Actors by name. Creates a Review in status requested per reviewer, linked to the artifact (reviews) and the reviewer (reviewed_by). Reviewers with a pending request are skipped. Reviewers answer with spec_approve_review or spec_reject_review; spec_mark_ready enforces the project's review policy."
+}
+
+func (t *RequestReview) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entity_id": {
+      "type": "string",
+      "description": "ID of the workflow artifact to review"
+    },
+    "reviewers": {
+      "type": "array",
+      "items": {"type": "string"},
+      "description": "Names of the Agents or Actors asked to review"
+    },
+    "requested_by": {
+      "type": "string",
+      "description": "Who is asking for the review"
+    },
+    "comment": {
+      "type": "string",
+      "description": "Note for the reviewers"
+    }
+  },
+  "required": ["entity_id", "reviewers"]
+}`))
+}
+
+func (t *RequestReview) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p requestReviewParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if len(p.Reviewers) == 0 {
+		return mcp.ErrorResult("reviewers is required"), nil
+	}
+	ctx, obj, msg := loadArtifact(ctx, client, p.EntityID)
+	if msg != "" {
+		return mcp.ErrorResult(msg), nil
+	}
+
+	// Resolve every reviewer before creating anything.
+	resolved := make([]*emergent.Participant, 0, len(p.Reviewers))
+	for _, name := range p.Reviewers {
+		r, err := client.FindParticipant(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if r == nil {
+			return mcp.ErrorResult(unknownReviewer(name)), nil
+		}
+		resolved = append(resolved, r)
+	}
+
+	reviews, err := client.ListReviews(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	digest := emergent.ArtifactDigest(obj.Properties)
+	name := artifactName(obj)
+	now := time.Now().Format(time.RFC3339)
+
+	var requested []map[string]any
+	var alreadyPending []string
+	for _, r := range resolved {
+		if prev := latestReview(reviews, r.Name); prev != nil && prev.Status == emergent.ReviewRequested {
+			alreadyPending = append(alreadyPending, r.Name)
+			continue
+		}
+		review, err := client.CreateReview(ctx, obj.ID, r.ID, &emergent.Review{
+			Name:           fmt.Sprintf("%s review of %s", r.Name, name),
+			Status:         emergent.ReviewRequested,
+			Reviewer:       r.Name,
+			ReviewerType:   r.Type,
+			ArtifactType:   obj.Type,
+			ArtifactDigest: digest,
+			Comment:        p.Comment,
+			RequestedBy:    p.RequestedBy,
+			RequestedAt:    now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		requested = append(requested, map[string]any{
+			"review_id":     review.ID,
+			"reviewer":      r.Name,
+			"reviewer_type": r.Type,
+		})
+	}
+
+	result := map[string]any{
+		"entity_id": obj.ID,
+		"type":      obj.Type,
+		"requested": requested,
+		"message":   fmt.Sprintf("Requested %d review(s) of %s %q", len(requested), obj.Type, name),
+	}
+	if len(alreadyPending) > 0 {
+		result["already_pending"] = alreadyPending
+	}
+	return mcp.JSONResult(result)
+}
+
+// --- spec_approve_review / spec_reject_review ---
+
+type decideParams struct {
+	EntityID string `json:"entity_id"`
+	Reviewer string `json:"reviewer"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+// decide records a reviewer's decision on an artifact. It completes the
+// reviewer's pending request if there is one, otherwise creates a Review.
+func decide(ctx context.Context, factory *emergent.ClientFactory, params json.RawMessage, status string) (*mcp.ToolsCallResult, error) {
+	var p decideParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if status == emergent.ReviewRejected && p.Comment == "" {
+		return mcp.ErrorResult("comment is required when rejecting: say what needs to change"), nil
+	}
+	ctx, obj, msg := loadArtifact(ctx, client, p.EntityID)
+	if msg != "" {
+		return mcp.ErrorResult(msg), nil
+	}
+	caller, err := client.Caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r, msg, err := resolveReviewer(ctx, client, caller, p.Reviewer)
+	if err != nil {
+		return nil, err
+	}
+	if msg != "" {
+		return mcp.ErrorResult(msg), nil
+	}
+	// Only a decision by the user the reviewer is bound to is attributed.
+	decidedBy := ""
+	if r.Subject != "" {
+		decidedBy = caller.Subject
+	}
+
+	reviews, err := client.ListReviews(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	digest := emergent.ArtifactDigest(obj.Properties)
+	now := time.Now().Format(time.RFC3339)
+
+	var review *emergent.Review
+	if prev := latestReview(reviews, r.Name); prev != nil && prev.Status == emergent.ReviewRequested {
+		props := map[string]any{
+			"status":          status,
+			"reviewer_type":   r.Type,
+			"artifact_digest": digest,
+			"decided_at":      now,
+			"decided_by":      decidedBy,
+		}
+		if p.Comment != "" {
+			props["comment"] = p.Comment
+		}
+		review, err = client.UpdateReview(ctx, prev.ID, props)
+		if err != nil {
+			return nil, fmt.Errorf("updating review: %w", err)
+		}
+		*prev = *review
+	} else {
+		review, err = client.CreateReview(ctx, obj.ID, r.ID, &emergent.Review{
+			Name:           fmt.Sprintf("%s review of %s", r.Name, artifactName(obj)),
+			Status:         status,
+			Reviewer:       r.Name,
+			ReviewerType:   r.Type,
+			ArtifactType:   obj.Type,
+			ArtifactDigest: digest,
+			Comment:        p.Comment,
+			DecidedAt:      now,
+			DecidedBy:      decidedBy,
+		})
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	return mcp.JSONResult(map[string]any{
+		"entity_id": obj.ID,
+		"type":      obj.Type,
+		"review_id": review.ID,
+		"reviewer":  r.Name,
+		"status":    status,
+		"reviews":   emergent.SummarizeReviews(reviews, digest),
+		"message":   fmt.Sprintf("%s %s %s %q", r.Name, status, obj.Type, artifactName(obj)),
+	})
+}
+
+// ApproveReview records a reviewer's approval of a workflow artifact.
+type ApproveReview struct {
+	factory *emergent.ClientFactory
+}
+
+// NewApproveReview creates an ApproveReview tool.
+func NewApproveReview(factory *emergent.ClientFactory) *ApproveReview {
+	return &ApproveReview{factory: factory}
+}
+
+func (t *ApproveReview) Name() string { return "spec_approve_review" }
+
+func (t *ApproveReview) Description() string {
+	return "Approve a workflow artifact, optionally with a comment, as the Agent or Actor bound to your Emergent user (its subject) or as a named AI agent. Completes the reviewer's pending request or records a new Review. Approvals count toward the review policy spec_mark_ready enforces; they go stale if the artifact is edited afterwards. Only approvals recorded by the user a human Actor or Agent is bound to count as human approvals; naming a reviewer bound to someone else, or an unbound human, is refused."
+}
+
+func (t *ApproveReview) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entity_id": {
+      "type": "string",
+      "description": "ID of the workflow artifact being approved"
+    },
+    "reviewer": {
+      "type": "string",
+      "description": "Name of the approving Agent or Actor. Default: the one bound to your Emergent user"
+    },
+    "comment": {
+      "type": "string",
+      "description": "Review comment"
+    }
+  },
+  "required": ["entity_id"]
+}`))
+}
+
+func (t *ApproveReview) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	return decide(ctx, t.factory, params, emergent.ReviewApproved)
+}
+
+// RejectReview records a reviewer's rejection of a workflow artifact.
+type RejectReview struct {
+	factory *emergent.ClientFactory
+}
+
+// NewRejectReview creates a RejectReview tool.
+func NewRejectReview(factory *emergent.ClientFactory) *RejectReview {
+	return &RejectReview{factory: factory}
+}
+
+func (t *RejectReview) Name() string { return "spec_reject_review" }
+
+func (t *RejectReview) Description() string {
+	return "Reject a workflow artifact, with a comment saying what needs to change, as the Agent or Actor bound to your Emergent user or as a named AI agent (the same identity rules as spec_approve_review). While the rejection stands, spec_mark_ready refuses artifact types covered by the review policy; it is cleared when the same reviewer later approves, or goes stale once the artifact is edited."
+}
+
+func (t *RejectReview) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entity_id": {
+      "type": "string",
+      "description": "ID of the workflow artifact being rejected"
+    },
+    "reviewer": {
+      "type": "string",
+      "description": "Name of the rejecting Agent or Actor. Default: the one bound to your Emergent user"
+    },
+    "comment": {
+      "type": "string",
+      "description": "What needs to change"
+    }
+  },
+  "required": ["entity_id", "comment"]
+}`))
+}
+
+func (t *RejectReview) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	return decide(ctx, t.factory, params, emergent.ReviewRejected)
+}
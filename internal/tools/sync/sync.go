@@ -222,7 +222,7 @@ func (t *Sync) Description() string {
 	return "Record a sync point between the codebase and the graph. Creates or updates a GraphSync entity with the current commit hash and timestamp."
 }
 func (t *Sync) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "change_id": {
@@ -238,7 +238,7 @@ func (t *Sync) InputSchema() json.RawMessage {
       "description": "If true, only report what would be synced without making changes"
     }
   }
-}`)
+}`))
 }
 
 func (t *Sync) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
@@ -6,6 +6,7 @@ package tasks
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/emergent-company/specmcp/internal/emergent"
 	"github.com/emergent-company/specmcp/internal/guards"
 	"github.com/emergent-company/specmcp/internal/mcp"
+	"github.com/emergent-company/specmcp/internal/validation"
 )
 
 // --- spec_generate_tasks ---
@@ -48,7 +50,7 @@ func (t *GenerateTasks) Description() string {
 	return "Generate tasks for a change from a task list. Creates Task entities with dependencies (blocks/blocked_by), subtask relationships, and implements links. Tasks are created in order; blocking references use task numbers."
 }
 func (t *GenerateTasks) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "change_id": {
@@ -76,7 +78,7 @@ func (t *GenerateTasks) InputSchema() json.RawMessage {
     }
   },
   "required": ["change_id", "tasks"]
-}`)
+}`))
 }
 
 func (t *GenerateTasks) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
@@ -101,6 +103,7 @@ func (t *GenerateTasks) Execute(ctx context.Context, params json.RawMessage) (*m
 	if err != nil {
 		return mcp.ErrorResult(fmt.Sprintf("change not found: %v", err)), nil
 	}
+	ctx = emergent.WithBranch(ctx, change.BranchID)
 
 	// Run artifact guards to ensure design is ready before generating tasks
 	gctx := &guards.GuardContext{
@@ -110,12 +113,21 @@ func (t *GenerateTasks) Execute(ctx context.Context, params json.RawMessage) (*m
 	if err := guards.PopulateChangeState(ctx, client, gctx); err != nil {
 		return nil, fmt.Errorf("populating change state: %w", err)
 	}
+	artifactGuards, err := guards.WithRules(ctx, client, gctx, guards.RulesArtifact, guards.ArtifactGuards(gctx.Workflow))
+	if err != nil {
+		return nil, fmt.Errorf("loading guard rules: %w", err)
+	}
 	runner := guards.NewRunner()
-	outcome := runner.Run(ctx, gctx, guards.ArtifactGuards())
+	outcome := runner.Run(ctx, gctx, artifactGuards)
 	if outcome.Blocked {
 		return mcp.ErrorResult(outcome.FormatBlockMessage()), nil
 	}
 
+	// Create tasks and relationships in a unit of work so a failure partway
+	// through deletes what was already created instead of leaving orphans.
+	uow := client.NewUnitOfWork()
+	ctx = emergent.WithUnitOfWork(ctx, uow)
+
 	// Create all tasks first to build a number→ID map
 	numberToID := make(map[string]string)
 	created := make([]map[string]any, 0, len(p.Tasks))
@@ -124,7 +136,7 @@ func (t *GenerateTasks) Execute(ctx context.Context, params json.RawMessage) (*m
 		// Check for context cancellation between task creations
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("task generation cancelled: %w", ctx.Err())
+			return nil, uow.Fail(ctx, fmt.Errorf("task generation cancelled: %w", ctx.Err()))
 		default:
 		}
 
@@ -138,7 +150,7 @@ func (t *GenerateTasks) Execute(ctx context.Context, params json.RawMessage) (*m
 			Tags:               td.Tags,
 		})
 		if err != nil {
-			return nil, fmt.Errorf("creating task %s: %w", td.Number, err)
+			return nil, uow.Fail(ctx, fmt.Errorf("creating task %s: %w", td.Number, err))
 		}
 		numberToID[td.Number] = task.ID
 		created = append(created, map[string]any{
@@ -156,7 +168,7 @@ func (t *GenerateTasks) Execute(ctx context.Context, params json.RawMessage) (*m
 		// Check for context cancellation between relationship batches
 		select {
 		case <-ctx.Done():
-			return nil, fmt.Errorf("task relationship creation cancelled: %w", ctx.Err())
+			return nil, uow.Fail(ctx, fmt.Errorf("task relationship creation cancelled: %w", ctx.Err()))
 		default:
 		}
 
@@ -169,7 +181,7 @@ func (t *GenerateTasks) Execute(ctx context.Context, params json.RawMessage) (*m
 				continue // silently skip unknown task numbers
 			}
 			if _, err := client.CreateRelationship(ctx, emergent.RelBlocks, taskID, blockedID, nil); err != nil {
-				return nil, fmt.Errorf("creating blocks %s→%s: %w", td.Number, blocksNum, err)
+				return nil, uow.Fail(ctx, fmt.Errorf("creating blocks %s→%s: %w", td.Number, blocksNum, err))
 			}
 			relCount++
 		}
@@ -179,7 +191,7 @@ func (t *GenerateTasks) Execute(ctx context.Context, params json.RawMessage) (*m
 			parentID, ok := numberToID[td.ParentTaskNumber]
 			if ok {
 				if _, err := client.CreateRelationship(ctx, emergent.RelHasSubtask, parentID, taskID, nil); err != nil {
-					return nil, fmt.Errorf("creating subtask %s→%s: %w", td.ParentTaskNumber, td.Number, err)
+					return nil, uow.Fail(ctx, fmt.Errorf("creating subtask %s→%s: %w", td.ParentTaskNumber, td.Number, err))
 				}
 				relCount++
 			}
@@ -188,7 +200,7 @@ func (t *GenerateTasks) Execute(ctx context.Context, params json.RawMessage) (*m
 		// Create implements relationship
 		if td.Implements != "" {
 			if _, err := client.CreateRelationship(ctx, emergent.RelImplements, taskID, td.Implements, nil); err != nil {
-				return nil, fmt.Errorf("creating implements for %s: %w", td.Number, err)
+				return nil, uow.Fail(ctx, fmt.Errorf("creating implements for %s: %w", td.Number, err))
 			}
 			relCount++
 		}
@@ -259,6 +271,7 @@ func (t *GetAvailableTasks) Execute(ctx context.Context, params json.RawMessage)
 	if err != nil {
 		return mcp.ErrorResult(fmt.Sprintf("change not found: %v", err)), nil
 	}
+	ctx = emergent.WithBranch(ctx, change.BranchID)
 
 	tasks, err := client.ListTasks(ctx, change.ID)
 	if err != nil {
@@ -426,16 +439,18 @@ func (t *GetAvailableTasks) isAssigned(ctx context.Context, client *emergent.Cli
 // --- spec_assign_task ---
 
 type assignTaskParams struct {
-	TaskID  string `json:"task_id"`
-	AgentID string `json:"agent_id"`
+	TaskID          string `json:"task_id"`
+	AgentID         string `json:"agent_id"`
+	ExpectedVersion int    `json:"expected_version,omitempty"`
 }
 
 type AssignTask struct {
-	factory *emergent.ClientFactory
+	factory     *emergent.ClientFactory
+	transitions *validation.Registry
 }
 
 func NewAssignTask(factory *emergent.ClientFactory) *AssignTask {
-	return &AssignTask{factory: factory}
+	return &AssignTask{factory: factory, transitions: validation.NewRegistry()}
 }
 
 func (t *AssignTask) Name() string { return "spec_assign_task" }
@@ -443,14 +458,15 @@ func (t *AssignTask) Description() string {
 	return "Assign a task to an Agent. Updates task status to in_progress and creates assigned_to relationship."
 }
 func (t *AssignTask) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "task_id": {"type": "string", "description": "ID of the task to assign"},
-    "agent_id": {"type": "string", "description": "ID of the Agent to assign to"}
+    "agent_id": {"type": "string", "description": "ID of the Agent to assign to"},
+    "expected_version": {"type": "integer", "description": "Only assign if the task is still at this version; a conflict is reported instead of retried"}
   },
   "required": ["task_id", "agent_id"]
-}`)
+}`))
 }
 
 func (t *AssignTask) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
@@ -468,10 +484,11 @@ func (t *AssignTask) Execute(ctx context.Context, params json.RawMessage) (*mcp.
 	}
 
 	// Get task to verify it exists and is assignable
-	task, err := client.GetTask(ctx, p.TaskID)
+	obj, task, err := getTask(ctx, client, p.TaskID)
 	if err != nil {
-		return mcp.ErrorResult(fmt.Sprintf("task not found: %v", err)), nil
+		return mcp.ErrorResult(err.Error()), nil
 	}
+	ctx = emergent.WithBranch(ctx, task.BranchID)
 	if task.Status != emergent.StatusPending {
 		return mcp.ErrorResult(fmt.Sprintf("task %s is %s, can only assign pending tasks", task.Number, task.Status)), nil
 	}
@@ -482,18 +499,36 @@ func (t *AssignTask) Execute(ctx context.Context, params json.RawMessage) (*mcp.
 		return mcp.ErrorResult(fmt.Sprintf("agent not found: %v", err)), nil
 	}
 
-	// Create assigned_to relationship using resolved IDs
-	if _, err := client.CreateRelationship(ctx, emergent.RelAssignedTo, task.ID, agentObj.ID, nil); err != nil {
+	// Create the assigned_to relationship first, in a unit of work, so the
+	// task never shows in_progress without an assignee: if the status update
+	// below fails, the relationship is deleted again.
+	uow := client.NewUnitOfWork()
+	ctx = emergent.WithUnitOfWork(ctx, uow)
+	if _, err := client.CreateRelationship(ctx, emergent.RelAssignedTo, obj.ID, agentObj.ID, nil); err != nil {
 		return nil, fmt.Errorf("creating assignment: %w", err)
 	}
 
-	// Update task status to in_progress with started_at
+	// Move the task to in_progress with started_at. The update is guarded
+	// by the task version so two agents cannot both claim the same task;
+	// without an explicit expected_version, a conflict re-reads the task and
+	// retries only if it is still pending.
 	now := time.Now()
-	task, err = client.UpdateTaskStatus(ctx, task.ID, emergent.StatusInProgress, map[string]any{
-		"started_at": now.Format(time.RFC3339),
+	updated, err := t.transitions.Apply(ctx, client, obj, validation.Transition{
+		To:  emergent.StatusInProgress,
+		Via: t.Name(),
+		Properties: map[string]any{
+			"started_at": now.Format(time.RFC3339),
+		},
+		ExpectedVersion: p.ExpectedVersion,
 	})
+	if errors.Is(err, emergent.ErrVersionConflict) || errors.Is(err, validation.ErrRejected) {
+		return mcp.ErrorResult(uow.Fail(ctx, err).Error()), nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("updating task status: %w", err)
+		return nil, uow.Fail(ctx, fmt.Errorf("updating task status: %w", err))
+	}
+	if task, err = emergent.TaskFromObject(updated); err != nil {
+		return nil, fmt.Errorf("reading task: %w", err)
 	}
 
 	return mcp.JSONResult(map[string]any{
@@ -513,22 +548,26 @@ type completeTaskParams struct {
 	TaskID            string   `json:"task_id"`
 	Artifacts         []string `json:"artifacts,omitempty"`
 	VerificationNotes string   `json:"verification_notes,omitempty"`
+	Force             bool     `json:"force,omitempty"`
+	Reason            string   `json:"reason,omitempty"`
+	ExpectedVersion   int      `json:"expected_version,omitempty"`
 }
 
 type CompleteTask struct {
-	factory *emergent.ClientFactory
+	factory     *emergent.ClientFactory
+	transitions *validation.Registry
 }
 
 func NewCompleteTask(factory *emergent.ClientFactory) *CompleteTask {
-	return &CompleteTask{factory: factory}
+	return &CompleteTask{factory: factory, transitions: validation.NewRegistry()}
 }
 
 func (t *CompleteTask) Name() string { return "spec_complete_task" }
 func (t *CompleteTask) Description() string {
-	return "Mark a task as completed. Records artifacts and verification notes. Checks if any blocked tasks become available."
+	return "Mark an in_progress task as completed. Records artifacts and verification notes. Subtasks must be completed first unless force=true; a pending task that was never assigned also needs force=true. Checks if any blocked tasks become available."
 }
 func (t *CompleteTask) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "task_id": {"type": "string", "description": "ID of the task to complete"},
@@ -540,10 +579,22 @@ func (t *CompleteTask) InputSchema() json.RawMessage {
     "verification_notes": {
       "type": "string",
       "description": "Notes on how the task was verified"
+    },
+    "force": {
+      "type": "boolean",
+      "description": "Complete even though subtasks are not completed, or the task is still pending (default: false)"
+    },
+    "reason": {
+      "type": "string",
+      "description": "Recorded in the task's transition history"
+    },
+    "expected_version": {
+      "type": "integer",
+      "description": "Only complete if the task is still at this version; a conflict is reported instead of retried"
     }
   },
   "required": ["task_id"]
-}`)
+}`))
 }
 
 func (t *CompleteTask) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
@@ -561,10 +612,11 @@ func (t *CompleteTask) Execute(ctx context.Context, params json.RawMessage) (*mc
 	}
 
 	// Get task
-	task, err := client.GetTask(ctx, p.TaskID)
+	obj, task, err := getTask(ctx, client, p.TaskID)
 	if err != nil {
-		return mcp.ErrorResult(fmt.Sprintf("task not found: %v", err)), nil
+		return mcp.ErrorResult(err.Error()), nil
 	}
+	ctx = emergent.WithBranch(ctx, task.BranchID)
 	if task.Status == emergent.StatusCompleted {
 		return mcp.ErrorResult(fmt.Sprintf("task %s is already completed", task.Number)), nil
 	}
@@ -587,10 +639,29 @@ func (t *CompleteTask) Execute(ctx context.Context, params json.RawMessage) (*mc
 		props["actual_hours"] = hours
 	}
 
-	task, err = client.UpdateTaskStatus(ctx, task.ID, emergent.StatusCompleted, props)
+	// Complete through the task state machine: only in_progress tasks can
+	// be completed, and pending tasks and open subtasks block unless forced. A concurrent
+	// completion is reported rather than repeated.
+	updated, err := t.transitions.Apply(ctx, client, obj, validation.Transition{
+		To:              emergent.StatusCompleted,
+		Force:           p.Force,
+		Reason:          p.Reason,
+		Via:             t.Name(),
+		Properties:      props,
+		ExpectedVersion: p.ExpectedVersion,
+	})
+	if errors.Is(err, validation.ErrRejected) && task.Status == emergent.StatusPending {
+		return mcp.ErrorResult(fmt.Sprintf("%v; assign task %s with spec_assign_task first, or use force=true to complete it unassigned", err, task.Number)), nil
+	}
+	if errors.Is(err, emergent.ErrVersionConflict) || errors.Is(err, validation.ErrRejected) {
+		return mcp.ErrorResult(err.Error()), nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("completing task: %w", err)
 	}
+	if task, err = emergent.TaskFromObject(updated); err != nil {
+		return nil, fmt.Errorf("reading task: %w", err)
+	}
 
 	// Find tasks that this task was blocking and check if they're now unblocked
 	// Use ExpandGraph to batch-fetch blocked tasks and all their blockers in one call.
@@ -733,6 +804,7 @@ func (t *GetCriticalPath) Execute(ctx context.Context, params json.RawMessage) (
 	if err != nil {
 		return mcp.ErrorResult(fmt.Sprintf("change not found: %v", err)), nil
 	}
+	ctx = emergent.WithBranch(ctx, change.BranchID)
 
 	// Get all tasks for the change
 	tasks, err := client.ListTasks(ctx, change.ID)
@@ -933,3 +1005,20 @@ func safePercent(num, denom int) float64 {
 	}
 	return float64(num) / float64(denom) * 100
 }
+
+// getTask reads a Task as both the graph object, for state transitions, and
+// the typed Task.
+func getTask(ctx context.Context, client *emergent.Client, id string) (*graph.GraphObject, *emergent.Task, error) {
+	obj, err := client.GetObject(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("task not found: %v", err)
+	}
+	if obj.Type != emergent.TypeTask {
+		return nil, nil, fmt.Errorf("entity %s is a %s, not a Task", id, obj.Type)
+	}
+	task, err := emergent.TaskFromObject(obj)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading task: %v", err)
+	}
+	return obj, task, nil
+}
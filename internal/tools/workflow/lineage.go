@@ -0,0 +1,167 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/guards"
+)
+
+// parentRelTypes maps an artifact type to the relationship type that links
+// it to its parent: ScenarioStep ← Scenario ← Requirement ← Spec ← Change,
+// and Proposal, Design, and Task directly under the Change.
+var parentRelTypes = map[string]string{
+	emergent.TypeScenarioStep: emergent.RelHasStep,
+	emergent.TypeScenario:     emergent.RelHasScenario,
+	emergent.TypeRequirement:  emergent.RelHasRequirement,
+	emergent.TypeSpec:         emergent.RelHasSpec,
+	emergent.TypeProposal:     emergent.RelHasProposal,
+	emergent.TypeDesign:       emergent.RelHasDesign,
+	emergent.TypeTask:         emergent.RelHasTask,
+}
+
+// staleStage describes a downstream stage whose artifacts were built on an
+// artifact that is now draft again.
+type staleStage struct {
+	Stage   string   `json:"stage"`
+	IDs     []string `json:"ids"`
+	Message string   `json:"message"`
+}
+
+// artifactRef identifies an artifact in tool results.
+type artifactRef struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+// refOf builds an artifactRef from a graph object.
+func refOf(obj *graph.GraphObject) artifactRef {
+	name, _ := obj.Properties["name"].(string)
+	if name == "" && obj.Key != nil {
+		name = *obj.Key
+	}
+	return artifactRef{ID: obj.ID, Type: obj.Type, Name: name}
+}
+
+// findParent returns the artifact's parent via its parent relationship, or
+// nil if it has none (e.g. an orphan).
+func findParent(ctx context.Context, client *emergent.Client, obj *graph.GraphObject) (*graph.GraphObject, error) {
+	relType, ok := parentRelTypes[obj.Type]
+	if !ok {
+		return nil, nil
+	}
+	edges, err := client.GetObjectEdges(ctx, obj.ID, &graph.GetObjectEdgesOptions{
+		Type:      relType,
+		Direction: "incoming",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting %s parent of %s: %w", relType, obj.ID, err)
+	}
+	if len(edges.Incoming) == 0 {
+		return nil, nil
+	}
+	parent, err := client.GetObject(ctx, edges.Incoming[0].SrcID)
+	if err != nil {
+		return nil, fmt.Errorf("getting parent %s: %w", edges.Incoming[0].SrcID, err)
+	}
+	return parent, nil
+}
+
+// artifactLineage walks from an artifact up to its Change. ancestors lists
+// the artifacts in between, nearest first (e.g. Requirement, Spec for a
+// Scenario). change is nil if the chain is broken before reaching a Change.
+func artifactLineage(ctx context.Context, client *emergent.Client, obj *graph.GraphObject) (ancestors []*graph.GraphObject, change *graph.GraphObject, err error) {
+	cur := obj
+	for range len(parentRelTypes) {
+		parent, err := findParent(ctx, client, cur)
+		if err != nil {
+			return nil, nil, err
+		}
+		if parent == nil {
+			return ancestors, nil, nil
+		}
+		if parent.Type == emergent.TypeChange {
+			return ancestors, parent, nil
+		}
+		ancestors = append(ancestors, parent)
+		cur = parent
+	}
+	return ancestors, nil, nil
+}
+
+// changeArtifact returns the change-level artifact obj falls under: its
+// farthest ancestor, or obj itself when it hangs off the change directly.
+func changeArtifact(obj *graph.GraphObject, ancestors []*graph.GraphObject) *graph.GraphObject {
+	if len(ancestors) > 0 {
+		return ancestors[len(ancestors)-1]
+	}
+	return obj
+}
+
+// stageArtifactType returns the workflow stage artifact type of a graph
+// type, e.g. "scenario_step" for ScenarioStep.
+func stageArtifactType(typeName string) string {
+	var b strings.Builder
+	for i, r := range typeName {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// downstreamStages lists the stages of the project's workflow that build on
+// the stage artifact belongs to (they require it, directly or through
+// another stage) and already have artifacts in the change. Those artifacts
+// may need revisiting when it changes. artifact is the change-level artifact
+// (Proposal, Spec, Design, Task) an edit falls under, so its stage: tags
+// count.
+func downstreamStages(ctx context.Context, client *emergent.Client, changeID string, artifact *graph.GraphObject) ([]staleStage, error) {
+	if changeID == "" {
+		return nil, nil
+	}
+	gctx := &guards.GuardContext{ChangeID: changeID}
+	if err := guards.PopulateChangeState(ctx, client, gctx); err != nil {
+		return nil, err
+	}
+	from := gctx.Workflow.StageFor(stageArtifactType(artifact.Type), getStringSlice(artifact.Properties, "tags"))
+	if from == nil {
+		return nil, nil
+	}
+
+	// Stages only require stages defined before them, so one pass in order
+	// collects everything built on from.
+	builtOn := map[string]bool{from.Name: true}
+	var stale []staleStage
+	for _, st := range gctx.Workflow.Stages {
+		if builtOn[st.Name] || !slices.ContainsFunc(st.Requires, func(r string) bool { return builtOn[r] }) {
+			continue
+		}
+		builtOn[st.Name] = true
+		var ids []string
+		for _, a := range gctx.Artifacts {
+			if s := gctx.Workflow.StageFor(a.Type, a.Tags); s != nil && s.Name == st.Name {
+				ids = append(ids, a.ID)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		stale = append(stale, staleStage{
+			Stage:   st.Name,
+			IDs:     ids,
+			Message: fmt.Sprintf("%d %s artifact(s) were built on the %s stage and may be stale; review and update them", len(ids), st.Name, from.Name),
+		})
+	}
+	return stale, nil
+}
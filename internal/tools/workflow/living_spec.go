@@ -0,0 +1,398 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+)
+
+// livingSpecRemoved is the status of a living spec whose domain a Change
+// removed.
+const livingSpecRemoved = "removed"
+
+// livingSpecMerge reports how one delta Spec was merged into its domain's
+// living spec.
+type livingSpecMerge struct {
+	Domain       string   `json:"domain"`
+	SpecID       string   `json:"spec_id"`
+	LivingSpecID string   `json:"living_spec_id,omitempty"`
+	Revision     int      `json:"revision"`
+	Created      bool     `json:"created,omitempty"`
+	SpecRemoved  bool     `json:"spec_removed,omitempty"`
+	Added        []string `json:"added,omitempty"`
+	Modified     []string `json:"modified,omitempty"`
+	Removed      []string `json:"removed,omitempty"`
+	Renamed      []string `json:"renamed,omitempty"` // "old → new"
+	Warnings     []string `json:"warnings,omitempty"`
+}
+
+// changed reports whether the merge altered the living spec.
+func (m *livingSpecMerge) changed() bool {
+	return m.Created || m.SpecRemoved || len(m.Added)+len(m.Modified)+len(m.Removed)+len(m.Renamed) > 0
+}
+
+// livingRequirement is a requirement currently included in a living spec.
+type livingRequirement struct {
+	edgeID string // empty if the merge being planned includes it
+	obj    *graph.GraphObject
+}
+
+// mergeLivingSpecs merges the delta Specs of a change into the living spec of
+// each Spec's domain (its domain property, or its name if unset):
+//
+//   - added / modified / unset: the requirement is included, replacing any
+//     current requirement of the same name (linked with supersedes)
+//   - removed: the current requirement of that name is dropped
+//   - renamed: the current requirement named renamed_from is replaced
+//
+// A removed Spec drops every requirement and marks the living spec removed.
+// Merging is idempotent, so an archive retried after a failure does not
+// apply a delta twice.
+func mergeLivingSpecs(ctx context.Context, client *emergent.Client, change *emergent.Change) ([]livingSpecMerge, error) {
+	specs, err := client.GetRelatedObjects(ctx, change.ID, emergent.RelHasSpec)
+	if err != nil {
+		return nil, fmt.Errorf("getting specs: %w", err)
+	}
+	merges := make([]livingSpecMerge, 0, len(specs))
+	for _, spec := range specs {
+		m, err := mergeSpec(ctx, client, change, spec)
+		if err != nil {
+			return nil, fmt.Errorf("merging spec %s: %w", spec.ID, err)
+		}
+		merges = append(merges, *m)
+	}
+	return merges, nil
+}
+
+// mergeSpec merges one delta Spec into its domain's living spec. The merge
+// is planned from the living spec as read, then the revision bump is written
+// guarded by that read's version before any includes_requirement edge
+// changes, so two archives touching the same domain cannot interleave their
+// edges: the one that loses the race re-reads and plans again.
+func mergeSpec(ctx context.Context, client *emergent.Client, change *emergent.Change, spec *graph.GraphObject) (*livingSpecMerge, error) {
+	for attempt := 0; ; attempt++ {
+		m, err := mergeSpecOnce(ctx, client, change, spec)
+		if errors.Is(err, emergent.ErrVersionConflict) && attempt < emergent.MaxConflictRetries {
+			continue
+		}
+		return m, err
+	}
+}
+
+// mergeSpecOnce is one attempt of mergeSpec. It returns a version conflict
+// if the living spec changed since it was read; nothing has been merged then.
+func mergeSpecOnce(ctx context.Context, client *emergent.Client, change *emergent.Change, spec *graph.GraphObject) (*livingSpecMerge, error) {
+	name, _ := spec.Properties["name"].(string)
+	domain, _ := spec.Properties["domain"].(string)
+	if domain == "" {
+		domain = name
+	}
+	delta, _ := spec.Properties["delta_type"].(string)
+	m := &livingSpecMerge{Domain: domain, SpecID: spec.ID}
+
+	living, err := client.FindByTypeAndKey(ctx, emergent.TypeLivingSpec, domain)
+	if err != nil {
+		return nil, fmt.Errorf("finding living spec: %w", err)
+	}
+	if living == nil && delta == emergent.DeltaRemoved {
+		m.Warnings = append(m.Warnings, fmt.Sprintf("no living spec for domain %q; nothing to remove", domain))
+		return m, nil
+	}
+
+	props := map[string]any{}
+	if living == nil {
+		living, err = client.CreateObject(ctx, emergent.TypeLivingSpec, &domain, map[string]any{
+			"name":     name,
+			"domain":   domain,
+			"purpose":  spec.Properties["purpose"],
+			"status":   emergent.StatusActive,
+			"revision": 0,
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating living spec: %w", err)
+		}
+		m.Created = true
+	} else if delta == emergent.DeltaAdded && livingStatus(living) == emergent.StatusActive {
+		m.Warnings = append(m.Warnings, fmt.Sprintf("spec is marked added but domain %q already has a living spec; merged as modified", domain))
+	}
+	m.LivingSpecID = living.ID
+	revision := getInt(living.Properties, "revision") + 1
+
+	current, err := livingRequirements(ctx, client, living.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &livingSpecPlan{}
+	if delta == emergent.DeltaRemoved {
+		for reqName, cur := range current {
+			plan.drop(cur)
+			m.Removed = append(m.Removed, reqName)
+		}
+		slices.Sort(m.Removed)
+		if livingStatus(living) != livingSpecRemoved {
+			m.SpecRemoved = true
+			props["status"] = livingSpecRemoved
+		}
+	} else {
+		if livingStatus(living) != emergent.StatusActive {
+			props["status"] = emergent.StatusActive
+		}
+		if p, _ := spec.Properties["purpose"].(string); p != "" && p != living.Properties["purpose"] {
+			props["purpose"] = p
+		}
+		if name != "" && name != living.Properties["name"] {
+			props["name"] = name
+		}
+
+		reqs, err := client.GetRelatedObjects(ctx, spec.ID, emergent.RelHasRequirement)
+		if err != nil {
+			return nil, fmt.Errorf("getting requirements: %w", err)
+		}
+		for _, req := range reqs {
+			mergeRequirement(plan, req, current, m)
+		}
+	}
+
+	if !m.changed() && len(props) == 0 {
+		m.Revision = revision - 1
+		return m, nil
+	}
+
+	// Claim the revision first, guarded by the version the plan was made
+	// from, so concurrent archives touching the same domain don't interleave.
+	props["revision"] = revision
+	props["last_change"] = change.Name
+	updated, err := client.UpdateObjectIfVersion(ctx, living.ID, living.Version, props, nil)
+	if err != nil {
+		return nil, fmt.Errorf("updating living spec: %w", err)
+	}
+	m.LivingSpecID = updated.ID
+	m.Revision = revision
+
+	if err := plan.apply(ctx, client, living.ID, map[string]any{
+		"change":    change.Name,
+		"change_id": change.ID,
+		"revision":  revision,
+	}); err != nil {
+		return nil, err
+	}
+
+	// Link the delta Spec to the living spec version its merge produced.
+	if _, err := client.CreateRelationship(ctx, emergent.RelMergedInto, spec.ID, updated.ID, map[string]any{
+		"change":   change.Name,
+		"revision": revision,
+	}); err != nil {
+		return nil, fmt.Errorf("linking spec to living spec: %w", err)
+	}
+	return m, nil
+}
+
+// livingSpecPlan is the set of edge changes a merge makes to a living spec,
+// collected before anything is written.
+type livingSpecPlan struct {
+	drops      []string           // includes_requirement edges to delete
+	includes   []livingInclude    // requirements to include
+	supersedes []livingSupersedes // new requirement → the one it replaces
+}
+
+type livingInclude struct {
+	reqID string
+	delta string
+}
+
+type livingSupersedes struct {
+	newID, oldID string
+}
+
+// drop removes a current requirement from the living spec.
+func (p *livingSpecPlan) drop(cur livingRequirement) {
+	if cur.edgeID != "" {
+		p.drops = append(p.drops, cur.edgeID)
+		return
+	}
+	// Included earlier in this plan: just don't include it.
+	p.includes = slices.DeleteFunc(p.includes, func(in livingInclude) bool { return in.reqID == cur.obj.ID })
+}
+
+// include adds req to the living spec and returns its planned entry.
+func (p *livingSpecPlan) include(req *graph.GraphObject, delta string) livingRequirement {
+	p.includes = append(p.includes, livingInclude{reqID: req.ID, delta: delta})
+	return livingRequirement{obj: req}
+}
+
+// replace swaps a current requirement for its new version, linked with
+// supersedes.
+func (p *livingSpecPlan) replace(old livingRequirement, req *graph.GraphObject) livingRequirement {
+	p.drop(old)
+	delta, _ := req.Properties["delta_type"].(string)
+	if delta == "" || delta == emergent.DeltaAdded {
+		delta = emergent.DeltaModified
+	}
+	p.supersedes = append(p.supersedes, livingSupersedes{newID: req.ID, oldID: old.obj.ID})
+	return p.include(req, delta)
+}
+
+// apply writes the plan: dropped edges first, then the new includes (with
+// edgeProps and their delta), then the supersedes links.
+func (p *livingSpecPlan) apply(ctx context.Context, client *emergent.Client, livingID string, edgeProps map[string]any) error {
+	for _, id := range p.drops {
+		if err := client.DeleteRelationship(ctx, id); err != nil {
+			return err
+		}
+	}
+	for _, in := range p.includes {
+		if _, err := client.CreateRelationship(ctx, emergent.RelIncludesRequirement, livingID, in.reqID, withDelta(edgeProps, in.delta)); err != nil {
+			return fmt.Errorf("including requirement %s: %w", in.reqID, err)
+		}
+	}
+	for _, sup := range p.supersedes {
+		if _, err := client.CreateRelationship(ctx, emergent.RelSupersedes, sup.newID, sup.oldID, map[string]any{
+			"change": edgeProps["change"],
+		}); err != nil {
+			return fmt.Errorf("linking superseded requirement: %w", err)
+		}
+	}
+	return nil
+}
+
+// mergeRequirement plans one Requirement's delta against the living spec's
+// current requirements, updating current to match.
+func mergeRequirement(plan *livingSpecPlan, req *graph.GraphObject, current map[string]livingRequirement, m *livingSpecMerge) {
+	name, _ := req.Properties["name"].(string)
+	delta, _ := req.Properties["delta_type"].(string)
+
+	switch delta {
+	case emergent.DeltaRemoved:
+		cur, ok := current[name]
+		if !ok {
+			m.Warnings = append(m.Warnings, fmt.Sprintf("requirement %q is marked removed but is not in the living spec", name))
+			return
+		}
+		plan.drop(cur)
+		delete(current, name)
+		m.Removed = append(m.Removed, name)
+		return
+
+	case emergent.DeltaRenamed:
+		from, _ := req.Properties["renamed_from"].(string)
+		if cur, ok := current[name]; ok && sameEntity(cur.obj, req) {
+			return // already merged
+		}
+		old, ok := current[from]
+		if from == "" || !ok {
+			m.Warnings = append(m.Warnings, fmt.Sprintf("requirement %q is marked renamed but renamed_from %q is not in the living spec; merged as added", name, from))
+			break
+		}
+		delete(current, from)
+		current[name] = plan.replace(old, req)
+		m.Renamed = append(m.Renamed, from+" → "+name)
+		return
+	}
+
+	cur, ok := current[name]
+	switch {
+	case ok && sameEntity(cur.obj, req):
+		return // already merged
+	case ok:
+		if delta == emergent.DeltaAdded {
+			m.Warnings = append(m.Warnings, fmt.Sprintf("requirement %q is marked added but already in the living spec; merged as modified", name))
+		}
+		current[name] = plan.replace(cur, req)
+		m.Modified = append(m.Modified, name)
+	default:
+		if delta == emergent.DeltaModified {
+			m.Warnings = append(m.Warnings, fmt.Sprintf("requirement %q is marked modified but not in the living spec; merged as added", name))
+		}
+		current[name] = plan.include(req, emergent.DeltaAdded)
+		m.Added = append(m.Added, name)
+	}
+}
+
+// livingSpecContribution is one merge of a change's Spec into a living spec,
+// recorded by its merged_into relationship.
+type livingSpecContribution struct {
+	SpecID       string `json:"spec_id"`
+	Spec         string `json:"spec"`
+	LivingSpecID string `json:"living_spec_id"`
+	Revision     int    `json:"revision"`
+}
+
+// livingSpecContributions lists the merges of a change's Specs into living
+// specs.
+func livingSpecContributions(ctx context.Context, client *emergent.Client, changeID string) ([]livingSpecContribution, error) {
+	specs, err := client.GetRelatedObjects(ctx, changeID, emergent.RelHasSpec)
+	if err != nil {
+		return nil, fmt.Errorf("getting specs: %w", err)
+	}
+	var out []livingSpecContribution
+	for _, spec := range specs {
+		edges, err := client.GetObjectEdges(ctx, spec.ID, &graph.GetObjectEdgesOptions{
+			Type:      emergent.RelMergedInto,
+			Direction: "outgoing",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("getting merges of spec %s: %w", spec.ID, err)
+		}
+		name, _ := spec.Properties["name"].(string)
+		for _, e := range edges.Outgoing {
+			out = append(out, livingSpecContribution{
+				SpecID:       spec.ID,
+				Spec:         name,
+				LivingSpecID: e.DstID,
+				Revision:     getInt(e.Properties, "revision"),
+			})
+		}
+	}
+	return out, nil
+}
+
+// livingRequirements returns the requirements a living spec currently
+// includes, by name.
+func livingRequirements(ctx context.Context, client *emergent.Client, livingID string) (map[string]livingRequirement, error) {
+	edges, err := client.GetObjectEdges(ctx, livingID, &graph.GetObjectEdgesOptions{
+		Type:      emergent.RelIncludesRequirement,
+		Direction: "outgoing",
+	})
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]livingRequirement, len(edges.Outgoing))
+	for _, e := range edges.Outgoing {
+		obj, err := client.GetObject(ctx, e.DstID)
+		if err != nil {
+			return nil, fmt.Errorf("getting requirement %s: %w", e.DstID, err)
+		}
+		name, _ := obj.Properties["name"].(string)
+		current[name] = livingRequirement{edgeID: e.ID, obj: obj}
+	}
+	return current, nil
+}
+
+// sameEntity reports whether a and b are versions of the same entity.
+func sameEntity(a, b *graph.GraphObject) bool {
+	ids := emergent.NewIDSet(a.ID, a.CanonicalID)
+	return ids[b.ID] || ids[b.CanonicalID]
+}
+
+// withDelta returns a copy of props with the delta property set.
+func withDelta(props map[string]any, delta string) map[string]any {
+	out := make(map[string]any, len(props)+1)
+	for k, v := range props {
+		out[k] = v
+	}
+	out["delta"] = delta
+	return out
+}
+
+// livingStatus returns a living spec's status, treating missing as active.
+func livingStatus(obj *graph.GraphObject) string {
+	if s, ok := obj.Properties["status"].(string); ok && s != "" {
+		return s
+	}
+	return emergent.StatusActive
+}
@@ -3,41 +3,49 @@ package workflow
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
 	"github.com/emergent-company/specmcp/internal/emergent"
 	"github.com/emergent-company/specmcp/internal/guards"
 	"github.com/emergent-company/specmcp/internal/mcp"
+	"github.com/emergent-company/specmcp/internal/validation"
 )
 
 // specArchiveParams defines the input for spec_archive.
 type specArchiveParams struct {
-	ChangeID string `json:"change_id"`
-	Force    bool   `json:"force,omitempty"`
+	ChangeID        string `json:"change_id"`
+	Force           bool   `json:"force,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+	ExpectedVersion int    `json:"expected_version,omitempty"`
 }
 
 // SpecArchive archives a completed change.
 type SpecArchive struct {
-	factory *emergent.ClientFactory
-	runner  *guards.Runner
+	factory     *emergent.ClientFactory
+	runner      *guards.Runner
+	transitions *validation.Registry
 }
 
 // NewSpecArchive creates a SpecArchive tool.
 func NewSpecArchive(factory *emergent.ClientFactory) *SpecArchive {
 	return &SpecArchive{
-		factory: factory,
-		runner:  guards.NewRunner(),
+		factory:     factory,
+		runner:      guards.NewRunner(),
+		transitions: validation.NewRegistry(),
 	}
 }
 
 func (t *SpecArchive) Name() string { return "spec_archive" }
 
 func (t *SpecArchive) Description() string {
-	return "Archive a completed change. Runs guards to verify artifact completeness and task completion. Use force=true to override soft blocks."
+	return "Archive a completed change. Runs guards to verify artifact completeness and task completion. Use force=true to override soft blocks. If the change has its own branch (spec_new with branch=true), merge conflicts with the main graph block archiving and are reported. Once the change is archived, its branch is merged into the main graph and its delta Specs and Requirements (delta_type added/modified/removed/renamed) are merged into the living spec of each domain; if a merge fails the change stays archived with merge_pending set, and calling spec_archive again finishes it. Undo with spec_unarchive."
 }
 
 func (t *SpecArchive) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "change_id": {
@@ -47,10 +55,18 @@ func (t *SpecArchive) InputSchema() json.RawMessage {
     "force": {
       "type": "boolean",
       "description": "Override soft blocks like incomplete tasks or missing artifacts (default: false)"
+    },
+    "reason": {
+      "type": "string",
+      "description": "Why the change is being archived; recorded in the transition history"
+    },
+    "expected_version": {
+      "type": "integer",
+      "description": "Only archive if the change is still at this version; a conflict is reported instead of retried"
     }
   },
   "required": ["change_id"]
-}`)
+}`))
 }
 
 func (t *SpecArchive) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
@@ -69,50 +85,211 @@ func (t *SpecArchive) Execute(ctx context.Context, params json.RawMessage) (*mcp
 	}
 
 	// Get the change
-	change, err := client.GetChange(ctx, p.ChangeID)
+	obj, err := client.GetObject(ctx, p.ChangeID)
 	if err != nil {
 		return mcp.ErrorResult(fmt.Sprintf("change not found: %v", err)), nil
 	}
+	if obj.Type != emergent.TypeChange {
+		return mcp.ErrorResult(fmt.Sprintf("entity %s is a %s, not a Change", p.ChangeID, obj.Type)), nil
+	}
+	change, err := emergent.ChangeFromObject(obj)
+	if err != nil {
+		return nil, fmt.Errorf("reading change: %w", err)
+	}
+
+	// A change archived by an earlier call whose merges failed only needs
+	// them finished.
+	if change.Status == emergent.StatusArchived && change.MergePending {
+		return t.finishMerges(ctx, client, change, nil)
+	}
 
-	if change.Status == emergent.StatusArchived {
+	// Check the transition up front, before anything is merged. Task
+	// completion is left to the archive guards, which honour the guard
+	// policy.
+	err = t.transitions.Validate(obj.Type, validation.Status(obj), emergent.StatusArchived, &validation.TransitionContext{
+		Client: client,
+		Ctx:    ctx,
+		Force:  true,
+	}, obj.ID)
+	if errors.Is(err, validation.ErrAlreadyInState) {
 		return mcp.ErrorResult("change is already archived"), nil
 	}
+	if err != nil {
+		return mcp.ErrorResult(err.Error()), nil
+	}
 
-	// Build guard context and populate change state
+	// Build guard context and populate change state. Guards see the change
+	// as it stands on its branch, before anything is merged.
 	gctx := &guards.GuardContext{
 		ChangeID: change.ID,
 		Force:    p.Force,
 	}
-	if err := guards.PopulateChangeState(ctx, client, gctx); err != nil {
+	branchCtx := emergent.WithBranch(ctx, change.BranchID)
+	if err := guards.PopulateChangeState(branchCtx, client, gctx); err != nil {
 		return nil, fmt.Errorf("populating change state for guards: %w", err)
 	}
 
-	// Run archive guards
-	outcome := t.runner.Run(ctx, gctx, guards.ArchiveGuards())
+	// Run archive guards and custom rules
+	archiveGuards, err := guards.WithRules(branchCtx, client, gctx, guards.RulesArchive, guards.ArchiveGuards())
+	if err != nil {
+		return nil, fmt.Errorf("loading guard rules: %w", err)
+	}
+	outcome := t.runner.Run(ctx, gctx, archiveGuards)
 	if outcome.Blocked {
 		return mcp.ErrorResult(outcome.FormatBlockMessage()), nil
 	}
 
-	// Archive the change using resolved ID
-	_, err = client.UpdateObject(ctx, change.ID, map[string]any{
-		"status": emergent.StatusArchived,
-	}, nil)
+	// Preview the branch merge so conflicts are reported before anything is
+	// written; force does not override them.
+	if change.BranchID != "" {
+		preview, err := client.MergeBranch(ctx, change.BranchID, false)
+		if err != nil {
+			return nil, err
+		}
+		if conflicts := branchConflicts(preview); len(conflicts) > 0 {
+			b, _ := json.MarshalIndent(conflicts, "", "  ")
+			return mcp.ErrorResult(fmt.Sprintf(
+				"cannot archive change %q: merging its branch conflicts with the main graph. Reconcile the conflicting properties on the branch or on main, then retry spec_archive.\n\n%s",
+				change.Name, b,
+			)), nil
+		}
+	}
+
+	// Archive the change first, guarded by the version the guards ran
+	// against, so a conflicting or rejected archive merges nothing and a
+	// concurrent archive is reported rather than repeated. The archive
+	// guards above already covered task completion, so the registry only
+	// checks the transition itself. archived_from remembers the status
+	// being left so spec_unarchive can restore it; merge_pending stays set
+	// until the merges below are done, so a retry finishes them.
+	_, err = t.transitions.Apply(ctx, client, obj, validation.Transition{
+		To:      emergent.StatusArchived,
+		Force:   p.Force,
+		Guarded: true,
+		Reason:  p.Reason,
+		Via:     t.Name(),
+		Properties: map[string]any{
+			"archived_from": validation.Status(obj),
+			"archived_at":   time.Now().Format(time.RFC3339),
+			"merge_pending": true,
+		},
+		ExpectedVersion: p.ExpectedVersion,
+	})
+	if errors.Is(err, emergent.ErrVersionConflict) || errors.Is(err, validation.ErrRejected) {
+		return mcp.ErrorResult(err.Error()), nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("archiving change: %w", err)
 	}
 
+	return t.finishMerges(ctx, client, change, outcome)
+}
+
+// finishMerges brings an archived change's branch into the main graph and
+// merges its delta specs into the living specs, then clears merge_pending.
+// Both merges are idempotent, so a retry after a failure picks up where the
+// failed call stopped.
+func (t *SpecArchive) finishMerges(ctx context.Context, client *emergent.Client, change *emergent.Change, outcome *guards.Outcome) (*mcp.ToolsCallResult, error) {
+	incomplete := func(format string, args ...any) *mcp.ToolsCallResult {
+		return mcp.ErrorResult(fmt.Sprintf("change %q is archived but its merges are not finished: %s. Retry spec_archive to finish them, or spec_unarchive to reopen the change.",
+			change.Name, fmt.Sprintf(format, args...)))
+	}
+
+	done := map[string]any{"merge_pending": false}
+	var branchMerge map[string]any
+	if change.BranchID != "" {
+		applied, err := client.MergeBranch(ctx, change.BranchID, true)
+		if err != nil {
+			return incomplete("merging branch: %v", err), nil
+		}
+		if conflicts := branchConflicts(applied); len(conflicts) > 0 {
+			b, _ := json.MarshalIndent(conflicts, "", "  ")
+			return incomplete("merging its branch hit conflicts introduced since the preview\n\n%s", b), nil
+		}
+		branchMerge = map[string]any{
+			"branch_id":     change.BranchID,
+			"added":         applied.AddedCount,
+			"fast_forwards": applied.FastForwardCount,
+			"unchanged":     applied.UnchangedCount,
+		}
+		done["branch_merged_at"] = time.Now().Format(time.RFC3339)
+	}
+
+	merges, err := mergeLivingSpecs(ctx, client, change)
+	if err != nil {
+		return incomplete("merging into living specs: %v", err), nil
+	}
+	if _, err := client.UpdateObject(ctx, change.ID, done, nil); err != nil {
+		return nil, fmt.Errorf("clearing merge_pending: %w", err)
+	}
+
+	message := fmt.Sprintf("Archived change %q", change.Name)
+	if outcome == nil {
+		message = fmt.Sprintf("Finished the merges of archived change %q", change.Name)
+	}
 	result := map[string]any{
 		"change_id":    change.ID,
 		"canonical_id": change.CanonicalID,
 		"name":         change.Name,
 		"status":       emergent.StatusArchived,
-		"message":      fmt.Sprintf("Archived change %q", change.Name),
+		"message":      message,
+	}
+
+	if branchMerge != nil {
+		result["branch_merge"] = branchMerge
+	}
+	if len(merges) > 0 {
+		result["living_specs"] = merges
 	}
 
 	// Include advisory messages if any
-	if advisory := outcome.FormatAdvisoryMessage(); advisory != "" {
-		result["advisories"] = advisory
+	if outcome != nil {
+		if advisory := outcome.FormatAdvisoryMessage(); advisory != "" {
+			result["advisories"] = advisory
+		}
 	}
 
 	return mcp.JSONResult(result)
 }
+
+// branchConflict is one object or relationship that a branch merge could not
+// apply cleanly, or a note about conflicts the merge did not list.
+type branchConflict struct {
+	Kind        string   `json:"kind"` // "object", "relationship", or "truncated"
+	CanonicalID string   `json:"canonical_id,omitempty"`
+	Paths       []string `json:"conflicting_paths,omitempty"`
+	Note        string   `json:"note,omitempty"`
+}
+
+// branchConflicts lists the conflicts a merge reported. Conflicts counted but
+// not listed, and a truncated listing that may hide some, are reported too,
+// so any of them refuses the merge.
+func branchConflicts(resp *graph.BranchMergeResponse) []branchConflict {
+	var out []branchConflict
+	objects := 0
+	for _, o := range resp.Objects {
+		if o.Status == "conflict" || len(o.Conflicts) > 0 {
+			out = append(out, branchConflict{Kind: "object", CanonicalID: o.CanonicalID, Paths: o.Conflicts})
+			objects++
+		}
+	}
+	if n := resp.ConflictCount - objects; n > 0 {
+		out = append(out, branchConflict{Kind: "object", Note: fmt.Sprintf("%d more conflicting object(s) not listed", n)})
+	}
+	relationships := 0
+	for _, r := range resp.Relationships {
+		if r.Status == "conflict" || len(r.Conflicts) > 0 {
+			out = append(out, branchConflict{Kind: "relationship", CanonicalID: r.CanonicalID, Paths: r.Conflicts})
+			relationships++
+		}
+	}
+	if resp.RelationshipsConflictCount != nil {
+		if n := *resp.RelationshipsConflictCount - relationships; n > 0 {
+			out = append(out, branchConflict{Kind: "relationship", Note: fmt.Sprintf("%d more conflicting relationship(s) not listed", n)})
+		}
+	}
+	if resp.Truncated {
+		out = append(out, branchConflict{Kind: "truncated", Note: "the merge listing was truncated, so further objects may conflict unlisted"})
+	}
+	return out
+}
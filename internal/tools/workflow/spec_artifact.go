@@ -3,12 +3,14 @@ package workflow
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
 	"github.com/emergent-company/specmcp/internal/emergent"
 	"github.com/emergent-company/specmcp/internal/guards"
 	"github.com/emergent-company/specmcp/internal/mcp"
+	"github.com/emergent-company/specmcp/internal/validation"
 )
 
 // specArtifactParams defines the input for spec_artifact.
@@ -35,11 +37,11 @@ func NewSpecArtifact(factory *emergent.ClientFactory) *SpecArtifact {
 func (t *SpecArtifact) Name() string { return "spec_artifact" }
 
 func (t *SpecArtifact) Description() string {
-	return "Add an artifact to an existing change. Supports: spec (with requirements and scenarios), design, task, actor, pattern, test_case, api_contract, context, ui_component, action, data_model, app, scenario_step. Enforces workflow ordering guards: Proposal → Spec → Design → Tasks. Automatically creates version-aware change tracking relationships (change_creates, change_modifies, change_references) for shared entities."
+	return "Add an artifact to an existing change. Supports: spec (with requirements and scenarios), design, task, actor, pattern, test_case, api_contract, context, ui_component, action, data_model, app, scenario_step. Enforces the ordering guards of the project's workflow stages (default Proposal → Spec → Design → Tasks; a tag stage:<name> puts an artifact in that stage). Automatically creates version-aware change tracking relationships (change_creates, change_modifies, change_references) for shared entities. To edit an existing Proposal, Spec, Requirement, Scenario, or Design, use spec_update_artifact instead of adding a new one."
 }
 
 func (t *SpecArtifact) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "change_id": {
@@ -53,11 +55,11 @@ func (t *SpecArtifact) InputSchema() json.RawMessage {
     },
     "content": {
       "type": "object",
-      "description": "Artifact-specific content. Fields depend on artifact_type. For spec: name, domain, purpose, requirements (array), scenarios (array). For design: approach, decisions, file_changes. For task: number, description, task_type, complexity_points."
+      "description": "Artifact-specific content. Fields depend on artifact_type. For spec: name, domain, purpose, delta_type (added/modified/removed/renamed, merged into the domain's living spec on archive), requirements (array; each may set delta_type and renamed_from), scenarios (array). For design: approach, decisions, file_changes. For task: number, description, task_type, complexity_points. When a shared entity with the same name already exists it is updated; set expected_version to only update it if it is still at that version."
     }
   },
   "required": ["change_id", "artifact_type", "content"]
-}`)
+}`))
 }
 
 func (t *SpecArtifact) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
@@ -86,20 +88,28 @@ func (t *SpecArtifact) Execute(ctx context.Context, params json.RawMessage) (*mc
 	if err != nil {
 		return mcp.ErrorResult(fmt.Sprintf("change not found: %v", err)), nil
 	}
+	// Route writes to the change's branch, if it has one.
+	ctx = emergent.WithBranch(ctx, change.BranchID)
 	if change.Status == emergent.StatusArchived {
 		return mcp.ErrorResult("cannot add artifacts to an archived change"), nil
 	}
 
 	// Run workflow ordering guards
 	gctx := &guards.GuardContext{
-		ChangeID:     change.ID,
-		ArtifactType: p.ArtifactType,
+		ChangeID:           change.ID,
+		ArtifactType:       p.ArtifactType,
+		ArtifactTags:       getStringSlice(p.Content, "tags"),
+		ArtifactProperties: p.Content,
 	}
 	if err := guards.PopulateChangeState(ctx, client, gctx); err != nil {
 		return nil, fmt.Errorf("populating change state for guards: %w", err)
 	}
 
-	outcome := t.runner.Run(ctx, gctx, guards.ArtifactGuards())
+	artifactGuards, err := guards.WithRules(ctx, client, gctx, guards.RulesArtifact, guards.ArtifactGuards(gctx.Workflow))
+	if err != nil {
+		return nil, fmt.Errorf("loading guard rules: %w", err)
+	}
+	outcome := t.runner.Run(ctx, gctx, artifactGuards)
 	if outcome.Blocked {
 		return mcp.ErrorResult(outcome.FormatBlockMessage()), nil
 	}
@@ -177,16 +187,22 @@ func (t *SpecArtifact) addProposal(ctx context.Context, client *emergent.Client,
 }
 
 // addSpec creates a Spec with optional Requirements and Scenarios.
+// The hierarchy is built in a unit of work: if any step fails, everything
+// created so far is deleted again and the error reports the rollback.
 func (t *SpecArtifact) addSpec(ctx context.Context, client *emergent.Client, changeID string, content map[string]any) (*mcp.ToolsCallResult, error) {
+	uow := client.NewUnitOfWork()
+	ctx = emergent.WithUnitOfWork(ctx, uow)
+
 	spec, err := client.CreateSpec(ctx, changeID, &emergent.Spec{
-		Name:      getString(content, "name"),
-		Domain:    getString(content, "domain"),
-		Purpose:   getString(content, "purpose"),
-		DeltaType: getString(content, "delta_type"),
-		Tags:      getStringSlice(content, "tags"),
+		Name:        getString(content, "name"),
+		Domain:      getString(content, "domain"),
+		Purpose:     getString(content, "purpose"),
+		DeltaType:   getString(content, "delta_type"),
+		RenamedFrom: getString(content, "renamed_from"),
+		Tags:        getStringSlice(content, "tags"),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("creating spec: %w", err)
+		return nil, uow.Fail(ctx, fmt.Errorf("creating spec: %w", err))
 	}
 
 	result := map[string]any{
@@ -210,10 +226,11 @@ func (t *SpecArtifact) addSpec(ctx context.Context, client *emergent.Client, cha
 				Description: getString(reqMap, "description"),
 				Strength:    getString(reqMap, "strength"),
 				DeltaType:   getString(reqMap, "delta_type"),
+				RenamedFrom: getString(reqMap, "renamed_from"),
 				Tags:        getStringSlice(reqMap, "tags"),
 			})
 			if err != nil {
-				return nil, fmt.Errorf("creating requirement: %w", err)
+				return nil, uow.Fail(ctx, fmt.Errorf("creating requirement: %w", err))
 			}
 
 			reqResult := map[string]any{
@@ -239,7 +256,7 @@ func (t *SpecArtifact) addSpec(ctx context.Context, client *emergent.Client, cha
 						Tags:  getStringSlice(scenMap, "tags"),
 					})
 					if err != nil {
-						return nil, fmt.Errorf("creating scenario: %w", err)
+						return nil, uow.Fail(ctx, fmt.Errorf("creating scenario: %w", err))
 					}
 					scenResults = append(scenResults, map[string]any{
 						"id":           scen.ID,
@@ -349,7 +366,7 @@ func (t *SpecArtifact) addRequirement(ctx context.Context, client *emergent.Clie
 	}
 
 	// Revert parent Spec to draft if it's currently ready
-	parentReverted, err := t.revertParentToDraft(ctx, client, specID)
+	parentReverted, err := revertToDraft(ctx, client, specID, "Requirement added")
 	if err != nil {
 		return nil, fmt.Errorf("checking parent readiness: %w", err)
 	}
@@ -359,6 +376,7 @@ func (t *SpecArtifact) addRequirement(ctx context.Context, client *emergent.Clie
 		Description: getString(content, "description"),
 		Strength:    getString(content, "strength"),
 		DeltaType:   getString(content, "delta_type"),
+		RenamedFrom: getString(content, "renamed_from"),
 		Tags:        getStringSlice(content, "tags"),
 	})
 	if err != nil {
@@ -389,7 +407,7 @@ func (t *SpecArtifact) addScenario(ctx context.Context, client *emergent.Client,
 	}
 
 	// Revert parent Requirement to draft if it's currently ready
-	parentReverted, err := t.revertParentToDraft(ctx, client, reqID)
+	parentReverted, err := revertToDraft(ctx, client, reqID, "Scenario added")
 	if err != nil {
 		return nil, fmt.Errorf("checking parent readiness: %w", err)
 	}
@@ -561,6 +579,7 @@ func (t *SpecArtifact) addGenericEntity(ctx context.Context, client *emergent.Cl
 		props[k] = v
 	}
 	delete(props, "tags")
+	delete(props, "expected_version")
 
 	// Remove relationship fields from properties — they are handled separately
 	delete(props, "patterns")
@@ -576,6 +595,16 @@ func (t *SpecArtifact) addGenericEntity(ctx context.Context, client *emergent.Cl
 		labels = getStringSlice(content, "tags")
 	}
 
+	if typeName == emergent.TypeActor || typeName == emergent.TypeAgent {
+		err := client.CheckSubjectUnbound(ctx, getString(content, "subject"), typeName, key)
+		if errors.Is(err, emergent.ErrSubjectBound) {
+			return mcp.ErrorResult(err.Error() + "; unbind it there first"), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Dedup: check if entity with same type+key already exists
 	var obj *graph.GraphObject
 	action := "Created"
@@ -587,7 +616,20 @@ func (t *SpecArtifact) addGenericEntity(ctx context.Context, client *emergent.Cl
 		}
 		if existing != nil {
 			// Entity exists — try to update it. UpdateObject returns the new version's ID.
-			obj, err = client.UpdateObject(ctx, existing.ID, props, labels)
+			// The update is guarded by the version we just read; a pinned
+			// expected_version reports conflicts instead of retrying.
+			expected, reload := existing.Version, reloadObject(ctx, client, existing.ID, nil)
+			if v := getInt(content, "expected_version"); v > 0 {
+				expected, reload = v, nil
+			}
+			err = emergent.RetryOnConflict(expected, func(version int) error {
+				var updateErr error
+				obj, updateErr = client.UpdateObjectIfVersion(ctx, existing.ID, version, props, labels)
+				return updateErr
+			}, reload)
+			if errors.Is(err, emergent.ErrVersionConflict) {
+				return mcp.ErrorResult(err.Error()), nil
+			}
 			if err != nil {
 				return nil, fmt.Errorf("updating existing %s %q: %w", typeName, key, err)
 			}
@@ -956,30 +998,58 @@ func (t *SpecArtifact) hasRelType(ctx context.Context, client *emergent.Client,
 
 // --- Helpers ---
 
-// revertParentToDraft checks if the parent entity is status=ready, and if so,
-// reverts it to draft. This is called when adding a child artifact (e.g.,
-// adding a Requirement to a Spec or a Scenario to a Requirement).
-// Returns true if the parent was reverted, false if it was already draft.
-func (t *SpecArtifact) revertParentToDraft(ctx context.Context, client *emergent.Client, parentID string) (bool, error) {
-	obj, err := client.GetObject(ctx, parentID)
+// revertToDraft checks if the entity is status=ready, and if so, reverts it
+// to draft, recording reason in its transition history. This is called when
+// adding a child artifact (e.g., adding a Requirement to a Spec or a Scenario
+// to a Requirement) and when an edited artifact's parents must be re-reviewed.
+// Returns true if the entity was reverted, false if it was already draft.
+func revertToDraft(ctx context.Context, client *emergent.Client, id, reason string) (bool, error) {
+	obj, err := client.GetObject(ctx, id)
 	if err != nil {
-		return false, fmt.Errorf("getting parent object %s: %w", parentID, err)
+		return false, fmt.Errorf("getting object %s: %w", id, err)
 	}
 
-	status, _ := obj.Properties["status"].(string)
-	if status != emergent.StatusReady {
+	if statusOf(obj) != emergent.StatusReady {
 		return false, nil
 	}
 
-	// Parent is ready — revert to draft
-	_, err = client.UpdateObject(ctx, obj.ID, map[string]any{"status": emergent.StatusDraft}, nil)
+	// Entity is ready — revert to draft. If someone else updated it in the
+	// meantime, the retry stops once it is no longer ready.
+	_, err = cascadeTransitions.Apply(ctx, client, obj, validation.Transition{
+		To:     emergent.StatusDraft,
+		Reason: reason,
+	})
+	if errors.Is(err, validation.ErrAlreadyInState) {
+		return false, nil
+	}
 	if err != nil {
-		return false, fmt.Errorf("reverting parent %s to draft: %w", parentID, err)
+		return false, fmt.Errorf("reverting %s to draft: %w", id, err)
 	}
 
 	return true, nil
 }
 
+// cascadeTransitions validates the readiness resets revertToDraft makes on
+// behalf of other tools.
+var cascadeTransitions = validation.NewRegistry()
+
+// reloadObject returns a RetryOnConflict reload func that re-reads the object
+// and lets check reject the retry based on the fresh state.
+func reloadObject(ctx context.Context, client *emergent.Client, id string, check func(*graph.GraphObject) error) func() (int, error) {
+	return func() (int, error) {
+		fresh, err := client.GetObject(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		if check != nil {
+			if err := check(fresh); err != nil {
+				return 0, err
+			}
+		}
+		return fresh.Version, nil
+	}
+}
+
 func getString(m map[string]any, key string) string {
 	v, _ := m[key].(string)
 	return v
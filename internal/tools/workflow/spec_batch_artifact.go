@@ -36,7 +36,7 @@ func (t *SpecBatchArtifact) Description() string {
 }
 
 func (t *SpecBatchArtifact) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "change_id": {
@@ -64,7 +64,7 @@ func (t *SpecBatchArtifact) InputSchema() json.RawMessage {
     }
   },
   "required": ["change_id", "artifacts"]
-}`)
+}`))
 }
 
 func (t *SpecBatchArtifact) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
@@ -0,0 +1,159 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/mcp"
+)
+
+// specSetChangeDependenciesParams defines the input for spec_set_change_dependencies.
+type specSetChangeDependenciesParams struct {
+	ChangeID string   `json:"change_id"`
+	Add      []string `json:"add,omitempty"`
+	Remove   []string `json:"remove,omitempty"`
+}
+
+// SpecSetChangeDependencies adds or removes depends_on_change relationships.
+type SpecSetChangeDependencies struct {
+	factory *emergent.ClientFactory
+}
+
+// NewSpecSetChangeDependencies creates a SpecSetChangeDependencies tool.
+func NewSpecSetChangeDependencies(factory *emergent.ClientFactory) *SpecSetChangeDependencies {
+	return &SpecSetChangeDependencies{factory: factory}
+}
+
+func (t *SpecSetChangeDependencies) Name() string { return "spec_set_change_dependencies" }
+
+func (t *SpecSetChangeDependencies) Description() string {
+	return "Declare which changes a change builds on (e.g. add-roles depends on add-auth), or drop such dependencies. spec_archive soft-blocks a change until its dependencies are archived. Dependencies that would form a cycle are rejected. Returns the change's dependencies and dependents."
+}
+
+func (t *SpecSetChangeDependencies) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "change_id": {
+      "type": "string",
+      "description": "ID of the change whose dependencies to edit"
+    },
+    "add": {
+      "type": "array",
+      "items": {"type": "string"},
+      "description": "IDs or names of changes this change depends on"
+    },
+    "remove": {
+      "type": "array",
+      "items": {"type": "string"},
+      "description": "IDs or names of changes this change no longer depends on"
+    }
+  },
+  "required": ["change_id"]
+}`))
+}
+
+func (t *SpecSetChangeDependencies) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p specSetChangeDependenciesParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if p.ChangeID == "" {
+		return mcp.ErrorResult("change_id is required"), nil
+	}
+
+	change, err := client.GetChange(ctx, p.ChangeID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("change not found: %v", err)), nil
+	}
+	if (len(p.Add) > 0 || len(p.Remove) > 0) && change.Status == emergent.StatusArchived {
+		return mcp.ErrorResult("cannot change the dependencies of an archived change"), nil
+	}
+
+	resolve := func(refs []string) ([]*emergent.Change, error) {
+		out := make([]*emergent.Change, 0, len(refs))
+		for _, ref := range refs {
+			ch, err := client.ResolveChange(ctx, ref)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, ch)
+		}
+		return out, nil
+	}
+	add, err := resolve(p.Add)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("add: %v", err)), nil
+	}
+	remove, err := resolve(p.Remove)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("remove: %v", err)), nil
+	}
+
+	g, err := client.LoadChangeGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Removals first, so the cycle check on additions ignores dependencies
+	// dropped in the same call.
+	removed := 0
+	for _, dep := range remove {
+		n, err := client.RemoveChangeDependency(ctx, g, change, dep)
+		if err != nil {
+			return nil, err
+		}
+		removed += n
+	}
+	for _, dep := range add {
+		if err := client.AddChangeDependency(ctx, g, change, dep); err != nil {
+			if errors.Is(err, emergent.ErrDependencyCycle) || errors.Is(err, emergent.ErrChangeGraphTruncated) {
+				return mcp.ErrorResult(err.Error()), nil
+			}
+			return nil, err
+		}
+	}
+
+	dependsOn := make([]map[string]any, 0, len(g.DependsOn[change.CanonicalID]))
+	for _, id := range g.DependsOn[change.CanonicalID] {
+		entry := map[string]any{"canonical_id": id, "name": g.Name(id)}
+		if dep, ok := g.Changes[id]; ok {
+			entry["status"] = dep.Status
+		}
+		dependsOn = append(dependsOn, entry)
+	}
+	var dependents []string
+	for _, id := range g.Order() {
+		for _, dep := range g.DependsOn[id] {
+			if dep == change.CanonicalID {
+				dependents = append(dependents, g.Name(id))
+				break
+			}
+		}
+	}
+
+	result := map[string]any{
+		"change_id":  change.ID,
+		"name":       change.Name,
+		"depends_on": dependsOn,
+		"dependents": dependents,
+		"removed":    removed,
+		"message":    fmt.Sprintf("Change %q depends on %d change(s)", change.Name, len(dependsOn)),
+	}
+	if len(g.Dangling) > 0 {
+		result["dangling_dependencies"] = g.Dangling
+	}
+	if g.Truncated {
+		result["truncated"] = true
+	}
+	return mcp.JSONResult(result)
+}
@@ -0,0 +1,295 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/mcp"
+)
+
+// childRelTypes maps an artifact type to the relationship type that owns its
+// children. Children are deleted together with their parent.
+var childRelTypes = map[string]string{
+	emergent.TypeSpec:        emergent.RelHasRequirement,
+	emergent.TypeRequirement: emergent.RelHasScenario,
+	emergent.TypeScenario:    emergent.RelHasStep,
+	emergent.TypeTask:        emergent.RelHasSubtask,
+}
+
+// specDeleteArtifactParams defines the input for spec_delete_artifact.
+type specDeleteArtifactParams struct {
+	EntityID string `json:"entity_id"`
+	DryRun   bool   `json:"dry_run,omitempty"`
+	Force    bool   `json:"force,omitempty"`
+}
+
+// deletionItem is an object in the subtree being deleted.
+type deletionItem struct {
+	artifactRef
+	Status string   `json:"status,omitempty"`
+	Depth  int      `json:"depth"`
+	edges  []string // IDs of relationships touching the object
+}
+
+// deletionEdge is a relationship removed along with the subtree.
+type deletionEdge struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	SrcID string `json:"src_id"`
+	DstID string `json:"dst_id"`
+}
+
+// SpecDeleteArtifact removes a workflow artifact together with the artifacts
+// it owns, so mistakes don't linger as orphans for the janitor to report.
+type SpecDeleteArtifact struct {
+	factory *emergent.ClientFactory
+}
+
+// NewSpecDeleteArtifact creates a SpecDeleteArtifact tool.
+func NewSpecDeleteArtifact(factory *emergent.ClientFactory) *SpecDeleteArtifact {
+	return &SpecDeleteArtifact{factory: factory}
+}
+
+func (t *SpecDeleteArtifact) Name() string { return "spec_delete_artifact" }
+
+func (t *SpecDeleteArtifact) Description() string {
+	return "Delete a workflow artifact (Proposal, Spec, Requirement, Scenario, ScenarioStep, Design, Task) and the subtree it owns: Spec → Requirements → Scenarios → ScenarioSteps, Task → subtasks. All relationships of the deleted objects, including blocks edges, are removed. Use dry_run to preview. Ready artifacts and started or completed tasks require force; artifacts of archived changes cannot be deleted."
+}
+
+func (t *SpecDeleteArtifact) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entity_id": {
+      "type": "string",
+      "description": "ID of the artifact to delete"
+    },
+    "dry_run": {
+      "type": "boolean",
+      "description": "Only report what would be deleted (default: false)"
+    },
+    "force": {
+      "type": "boolean",
+      "description": "Delete even if the subtree contains ready artifacts or started/completed tasks (default: false)"
+    }
+  },
+  "required": ["entity_id"]
+}`))
+}
+
+func (t *SpecDeleteArtifact) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p specDeleteArtifactParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if p.EntityID == "" {
+		return mcp.ErrorResult("entity_id is required"), nil
+	}
+
+	obj, err := client.GetObject(ctx, p.EntityID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("entity not found: %v", err)), nil
+	}
+	ctx = emergent.WithBranch(ctx, emergent.ObjectBranch(obj))
+	if _, ok := parentRelTypes[obj.Type]; !ok {
+		return mcp.ErrorResult(fmt.Sprintf(
+			"entity type %q cannot be deleted with spec_delete_artifact. Only Proposal, Spec, Requirement, Scenario, ScenarioStep, Design, and Task are supported.",
+			obj.Type,
+		)), nil
+	}
+
+	ancestors, change, err := artifactLineage(ctx, client, obj)
+	if err != nil {
+		return nil, fmt.Errorf("resolving parents: %w", err)
+	}
+	if change != nil {
+		if s, _ := change.Properties["status"].(string); s == emergent.StatusArchived {
+			return mcp.ErrorResult("cannot delete artifacts of an archived change"), nil
+		}
+	}
+
+	items, edges, err := collectSubtree(ctx, client, obj)
+	if err != nil {
+		return nil, fmt.Errorf("collecting subtree: %w", err)
+	}
+
+	var protected []deletionItem
+	for _, it := range items {
+		if isProtected(it.Type, it.Status) {
+			protected = append(protected, it)
+		}
+	}
+
+	var blocks []deletionEdge
+	for _, e := range edges {
+		if e.Type == emergent.RelBlocks || e.Type == emergent.RelBlockedBy {
+			blocks = append(blocks, e)
+		}
+	}
+
+	result := map[string]any{
+		"entity_id":     obj.ID,
+		"type":          obj.Type,
+		"objects":       items,
+		"relationships": len(edges),
+	}
+	if len(blocks) > 0 {
+		result["blocks_edges"] = blocks
+	}
+	if len(protected) > 0 {
+		result["protected"] = protected
+	}
+
+	if p.DryRun {
+		result["dry_run"] = true
+		result["message"] = fmt.Sprintf("Would delete %d object(s) and %d relationship(s)", len(items), len(edges))
+		if len(protected) > 0 && !p.Force {
+			result["message"] = result["message"].(string) + fmt.Sprintf("; %d ready or started artifact(s) require force", len(protected))
+		}
+		return mcp.JSONResult(result)
+	}
+
+	if len(protected) > 0 && !p.Force {
+		parts := make([]string, 0, len(protected))
+		for _, it := range protected {
+			parts = append(parts, fmt.Sprintf("%s %s (%s)", it.Type, it.ID, it.Status))
+		}
+		return mcp.ErrorResult(fmt.Sprintf(
+			"cannot delete: the subtree contains ready or started artifacts: %s. Move them back to draft with spec_mark_draft, or use force=true",
+			strings.Join(parts, ", "),
+		)), nil
+	}
+
+	// Bottom-up: each object's relationships, then the object, from the
+	// deepest level to the root. Stop at the first failure so the rest of the
+	// subtree stays reachable from the root for a retry.
+	deletedObjects, deletedEdges := 0, 0
+	removed := map[string]bool{}
+	var failure error
+walk:
+	for i := len(items) - 1; i >= 0; i-- {
+		for _, e := range items[i].edges {
+			if removed[e] {
+				continue
+			}
+			if err := client.DeleteRelationship(ctx, e); err != nil {
+				failure = err
+				break walk
+			}
+			removed[e] = true
+			deletedEdges++
+		}
+		if err := client.DeleteObject(ctx, items[i].ID); err != nil {
+			failure = err
+			break
+		}
+		deletedObjects++
+	}
+
+	// A ready parent's readiness covered the deleted child.
+	var reverted []artifactRef
+	for _, a := range ancestors {
+		ok, err := revertToDraft(ctx, client, a.ID, fmt.Sprintf("%s %s deleted", obj.Type, obj.ID))
+		if err != nil {
+			return nil, fmt.Errorf("resetting parent readiness: %w", err)
+		}
+		if ok {
+			reverted = append(reverted, refOf(a))
+		}
+	}
+
+	delete(result, "relationships")
+	result["deleted_objects"] = deletedObjects
+	result["deleted_relationships"] = deletedEdges
+	if len(reverted) > 0 {
+		result["reverted_parents"] = reverted
+	}
+	result["message"] = fmt.Sprintf("Deleted %d object(s) and %d relationship(s)", deletedObjects, deletedEdges)
+	if failure != nil {
+		result["error"] = failure.Error()
+		result["message"] = result["message"].(string) + "; stopped at a failed deletion, run spec_delete_artifact again to finish"
+	}
+
+	return mcp.JSONResult(result)
+}
+
+// collectSubtree walks the owned children of root breadth-first. items are
+// ordered by depth with root first; edges holds every relationship touching
+// an item, each once, for reporting.
+func collectSubtree(ctx context.Context, client *emergent.Client, root *graph.GraphObject) ([]deletionItem, []deletionEdge, error) {
+	var (
+		items   []deletionItem
+		edges   []deletionEdge
+		seen    = emergent.NewIDSet(root.ID, root.CanonicalID)
+		visited = map[string]bool{}
+		edged   = map[string]bool{}
+	)
+
+	type node struct {
+		id    string
+		depth int
+	}
+	queue := []node{{root.ID, 0}}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		obj := root
+		if n.id != root.ID {
+			var err error
+			if obj, err = client.GetObject(ctx, n.id); err != nil {
+				return nil, nil, fmt.Errorf("getting %s: %w", n.id, err)
+			}
+		}
+		// Edges may name a child by its version or canonical ID; visit
+		// each object once.
+		if visited[obj.CanonicalID] {
+			continue
+		}
+		visited[obj.CanonicalID] = true
+		seen[obj.ID], seen[obj.CanonicalID] = true, true
+		status, _ := obj.Properties["status"].(string)
+		item := deletionItem{artifactRef: refOf(obj), Status: status, Depth: n.depth}
+
+		resp, err := client.GetObjectEdges(ctx, obj.ID, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		childRel := childRelTypes[obj.Type]
+		for _, e := range slices.Concat(resp.Outgoing, resp.Incoming) {
+			item.edges = append(item.edges, e.ID)
+			if !edged[e.ID] {
+				edged[e.ID] = true
+				edges = append(edges, deletionEdge{ID: e.ID, Type: e.Type, SrcID: e.SrcID, DstID: e.DstID})
+			}
+		}
+		for _, e := range resp.Outgoing {
+			if childRel != "" && e.Type == childRel && !seen[e.DstID] {
+				seen[e.DstID] = true
+				queue = append(queue, node{e.DstID, n.depth + 1})
+			}
+		}
+		items = append(items, item)
+	}
+	return items, edges, nil
+}
+
+// isProtected reports whether deleting an artifact in this state discards
+// reviewed or started work.
+func isProtected(typeName, status string) bool {
+	if typeName == emergent.TypeTask {
+		return status == emergent.StatusInProgress || status == emergent.StatusCompleted
+	}
+	return status == emergent.StatusReady
+}
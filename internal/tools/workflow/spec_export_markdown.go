@@ -0,0 +1,274 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/markdown"
+	"github.com/emergent-company/specmcp/internal/mcp"
+)
+
+// Export formats.
+const (
+	exportFormatTree = "tree"
+	exportFormatPR   = "pr"
+)
+
+// specExportMarkdownParams defines the input for spec_export_markdown.
+type specExportMarkdownParams struct {
+	ChangeID string `json:"change_id"`
+	Format   string `json:"format,omitempty"`
+}
+
+// SpecExportMarkdown renders a change as markdown.
+type SpecExportMarkdown struct {
+	factory *emergent.ClientFactory
+}
+
+// NewSpecExportMarkdown creates a SpecExportMarkdown tool.
+func NewSpecExportMarkdown(factory *emergent.ClientFactory) *SpecExportMarkdown {
+	return &SpecExportMarkdown{factory: factory}
+}
+
+func (t *SpecExportMarkdown) Name() string { return "spec_export_markdown" }
+
+func (t *SpecExportMarkdown) Description() string {
+	return "Render a change as markdown for code review or readers without an MCP client: its proposal, specs with requirements (MUST/SHOULD/MAY) and Given/When/Then scenarios, design, and task checklist. format=tree produces the proposal.md / design.md / tasks.md / specs/<capability>/spec.md layout that spec_import_markdown reads back; format=pr produces a single pull request description. Returns the content; the specmcp export command writes it to disk."
+}
+
+func (t *SpecExportMarkdown) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "change_id": {
+      "type": "string",
+      "description": "ID or name of the change to export"
+    },
+    "format": {
+      "type": "string",
+      "enum": ["tree", "pr"],
+      "description": "tree: a change directory; pr: one pull request description. Default: tree"
+    }
+  },
+  "required": ["change_id"]
+}`)
+}
+
+func (t *SpecExportMarkdown) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p specExportMarkdownParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if p.ChangeID == "" {
+		return mcp.ErrorResult("change_id is required"), nil
+	}
+	if p.Format == "" {
+		p.Format = exportFormatTree
+	}
+	if p.Format != exportFormatTree && p.Format != exportFormatPR {
+		return mcp.ErrorResult(fmt.Sprintf("unknown format %q (want tree or pr)", p.Format)), nil
+	}
+
+	change, err := client.ResolveChange(ctx, p.ChangeID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("change not found: %v", err)), nil
+	}
+	ctx = emergent.WithBranch(ctx, change.BranchID)
+
+	doc, err := loadMarkdownChange(ctx, client, change)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]any{
+		"change_id": change.ID,
+		"name":      change.Name,
+		"format":    p.Format,
+	}
+	if p.Format == exportFormatPR {
+		result["markdown"] = markdown.RenderPR(doc)
+	} else {
+		result["files"] = markdown.Files(doc)
+	}
+	result["message"] = fmt.Sprintf("Exported change %q: %d spec(s), %d requirement(s), %d task(s)",
+		change.Name, len(doc.Specs), countRequirements(doc), countTasks(doc.Tasks))
+	return mcp.JSONResult(result)
+}
+
+// loadMarkdownChange reads a change's artifacts into the markdown model.
+// Children are ordered as they were linked, tasks by number.
+func loadMarkdownChange(ctx context.Context, client *emergent.Client, change *emergent.Change) (*markdown.Change, error) {
+	doc := &markdown.Change{Name: change.Name}
+
+	proposals, err := linkedObjects(ctx, client, change.ID, emergent.RelHasProposal)
+	if err != nil {
+		return nil, err
+	}
+	if len(proposals) > 0 {
+		props := proposals[0].Properties
+		doc.Proposal = &markdown.Proposal{
+			Intent: getString(props, "intent"),
+			Scope:  getString(props, "scope"),
+			Impact: getString(props, "impact"),
+		}
+	}
+
+	specs, err := linkedObjects(ctx, client, change.ID, emergent.RelHasSpec)
+	if err != nil {
+		return nil, err
+	}
+	for _, specObj := range specs {
+		spec := &markdown.Spec{
+			Name:      getString(specObj.Properties, "name"),
+			Purpose:   getString(specObj.Properties, "purpose"),
+			DeltaType: getString(specObj.Properties, "delta_type"),
+		}
+		reqs, err := linkedObjects(ctx, client, specObj.ID, emergent.RelHasRequirement)
+		if err != nil {
+			return nil, err
+		}
+		for _, reqObj := range reqs {
+			req := &markdown.Requirement{
+				Name:        getString(reqObj.Properties, "name"),
+				Description: getString(reqObj.Properties, "description"),
+				Strength:    getString(reqObj.Properties, "strength"),
+				DeltaType:   getString(reqObj.Properties, "delta_type"),
+				RenamedFrom: getString(reqObj.Properties, "renamed_from"),
+			}
+			scenarios, err := linkedObjects(ctx, client, reqObj.ID, emergent.RelHasScenario)
+			if err != nil {
+				return nil, err
+			}
+			for _, sc := range scenarios {
+				req.Scenarios = append(req.Scenarios, &markdown.Scenario{
+					Name:    getString(sc.Properties, "name"),
+					Given:   getString(sc.Properties, "given"),
+					When:    getString(sc.Properties, "when"),
+					Then:    getString(sc.Properties, "then"),
+					AndAlso: getStringSlice(sc.Properties, "and_also"),
+				})
+			}
+			spec.Requirements = append(spec.Requirements, req)
+		}
+		doc.Specs = append(doc.Specs, spec)
+	}
+	sort.SliceStable(doc.Specs, func(i, j int) bool { return doc.Specs[i].Name < doc.Specs[j].Name })
+
+	designs, err := linkedObjects(ctx, client, change.ID, emergent.RelHasDesign)
+	if err != nil {
+		return nil, err
+	}
+	if len(designs) > 0 {
+		props := designs[0].Properties
+		doc.Design = &markdown.Design{
+			Approach:    getString(props, "approach"),
+			Decisions:   getString(props, "decisions"),
+			DataFlow:    getString(props, "data_flow"),
+			FileChanges: getStringSlice(props, "file_changes"),
+		}
+	}
+
+	tasks, err := client.ListTasks(ctx, change.ID)
+	if err != nil {
+		return nil, fmt.Errorf("listing tasks: %w", err)
+	}
+	doc.Tasks = nestTasks(tasks)
+	return doc, nil
+}
+
+// nestTasks builds the task tree from task numbers: each task goes under the
+// task whose number is its longest dotted prefix ("1.2.1" under "1.2", else
+// "1"), and tasks without one are top-level.
+func nestTasks(tasks []*emergent.Task) []*markdown.Task {
+	byNumber := make(map[string]*markdown.Task, len(tasks))
+	for _, t := range tasks {
+		if _, dup := byNumber[t.Number]; dup {
+			continue
+		}
+		byNumber[t.Number] = &markdown.Task{Number: t.Number, Description: t.Description, Status: t.Status}
+	}
+	var top []*markdown.Task
+	for number, mt := range byNumber {
+		parent := number
+		var found *markdown.Task
+		for found == nil {
+			i := strings.LastIndex(parent, ".")
+			if i < 0 {
+				break
+			}
+			parent = parent[:i]
+			found = byNumber[parent]
+		}
+		if found != nil {
+			found.Subtasks = append(found.Subtasks, mt)
+		} else {
+			top = append(top, mt)
+		}
+	}
+	markdown.SortTasks(top)
+	return top
+}
+
+// linkedObjects returns the objects linked from srcID by relType, in the
+// order the relationships were created, one per canonical ID.
+func linkedObjects(ctx context.Context, client *emergent.Client, srcID, relType string) ([]*graph.GraphObject, error) {
+	if srcID == "" {
+		return nil, nil
+	}
+	rels, _, err := client.ListAllRelationships(ctx, &graph.ListRelationshipsOptions{Type: relType, SrcID: srcID}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("listing %s of %s: %w", relType, srcID, err)
+	}
+	sort.SliceStable(rels, func(i, j int) bool { return rels[i].CreatedAt.Before(rels[j].CreatedAt) })
+	ids := make([]string, len(rels))
+	for i, rel := range rels {
+		ids[i] = rel.DstID
+	}
+	objs, err := client.GetObjects(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("getting %s of %s: %w", relType, srcID, err)
+	}
+	byID := make(map[string]*graph.GraphObject, 2*len(objs))
+	for _, obj := range objs {
+		byID[obj.ID] = obj
+		byID[obj.CanonicalID] = obj
+	}
+	seen := make(map[string]bool, len(objs))
+	out := make([]*graph.GraphObject, 0, len(objs))
+	for _, id := range ids {
+		obj, ok := byID[id]
+		if !ok || seen[obj.CanonicalID] {
+			continue
+		}
+		seen[obj.CanonicalID] = true
+		out = append(out, obj)
+	}
+	return out, nil
+}
+
+func countRequirements(doc *markdown.Change) int {
+	n := 0
+	for _, s := range doc.Specs {
+		n += len(s.Requirements)
+	}
+	return n
+}
+
+func countTasks(tasks []*markdown.Task) int {
+	n := len(tasks)
+	for _, t := range tasks {
+		n += countTasks(t.Subtasks)
+	}
+	return n
+}
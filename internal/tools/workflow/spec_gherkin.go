@@ -0,0 +1,513 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/gherkin"
+	"github.com/emergent-company/specmcp/internal/markdown"
+	"github.com/emergent-company/specmcp/internal/mcp"
+	"github.com/emergent-company/specmcp/internal/validation"
+)
+
+// Gherkin tag prefixes that carry graph data rather than plain Tags.
+const (
+	actorTagPrefix    = "actor:"    // executed_by Actor, by name
+	strengthTagPrefix = "strength:" // Requirement strength the description doesn't imply
+)
+
+// --- spec_export_gherkin ---
+
+// specExportGherkinParams defines the input for spec_export_gherkin.
+type specExportGherkinParams struct {
+	SpecID   string `json:"spec_id,omitempty"`
+	ChangeID string `json:"change_id,omitempty"`
+}
+
+// SpecExportGherkin renders specs as Gherkin feature files.
+type SpecExportGherkin struct {
+	factory *emergent.ClientFactory
+}
+
+// NewSpecExportGherkin creates a SpecExportGherkin tool.
+func NewSpecExportGherkin(factory *emergent.ClientFactory) *SpecExportGherkin {
+	return &SpecExportGherkin{factory: factory}
+}
+
+func (t *SpecExportGherkin) Name() string { return "spec_export_gherkin" }
+
+func (t *SpecExportGherkin) Description() string {
+	return "Export a spec, or every spec of a change, as Gherkin .feature files for Cucumber: one Feature per spec, a Rule per requirement, and a Scenario per scenario with its Given/When/Then, steps, and and_also lines. Scenarios with variants (variant_of) become Scenario Outlines with an Examples row per variant. Tags become @tags, executed_by actors @actor:<name>. Returns the files by name; the caller writes them."
+}
+
+func (t *SpecExportGherkin) InputSchema() json.RawMessage {
+	return json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "spec_id": {
+      "type": "string",
+      "description": "ID of the spec to export"
+    },
+    "change_id": {
+      "type": "string",
+      "description": "ID or name of a change whose specs to export (instead of spec_id)"
+    }
+  }
+}`)
+}
+
+func (t *SpecExportGherkin) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p specExportGherkinParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if (p.SpecID == "") == (p.ChangeID == "") {
+		return mcp.ErrorResult("exactly one of spec_id or change_id is required"), nil
+	}
+
+	var specs []*graph.GraphObject
+	if p.SpecID != "" {
+		obj, err := client.GetObject(ctx, p.SpecID)
+		if err != nil || obj.Type != emergent.TypeSpec {
+			return mcp.ErrorResult(fmt.Sprintf("spec %q not found", p.SpecID)), nil
+		}
+		ctx = emergent.WithBranch(ctx, emergent.ObjectBranch(obj))
+		specs = []*graph.GraphObject{obj}
+	} else {
+		change, err := client.ResolveChange(ctx, p.ChangeID)
+		if err != nil {
+			return mcp.ErrorResult(fmt.Sprintf("change not found: %v", err)), nil
+		}
+		ctx = emergent.WithBranch(ctx, change.BranchID)
+		if specs, err = linkedObjects(ctx, client, change.ID, emergent.RelHasSpec); err != nil {
+			return nil, err
+		}
+	}
+
+	files := make(map[string]string, len(specs))
+	scenarios := 0
+	for _, spec := range specs {
+		feature, n, err := loadFeature(ctx, client, spec)
+		if err != nil {
+			return nil, err
+		}
+		files[featureFileName(feature.Name)] = gherkin.Render(feature)
+		scenarios += n
+	}
+
+	return mcp.JSONResult(map[string]any{
+		"files":   files,
+		"message": fmt.Sprintf("Exported %d feature(s) with %d scenario(s)", len(files), scenarios),
+	})
+}
+
+// featureFileName is the .feature file for a spec: its name with anything
+// awkward in a file name replaced by hyphens.
+func featureFileName(spec string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r == '/' || r == '\\' || r == ':' || r == ' ':
+			return '-'
+		default:
+			return r
+		}
+	}, spec)
+	return name + ".feature"
+}
+
+// loadFeature builds a spec's Feature and counts the scenarios in it,
+// variants included.
+func loadFeature(ctx context.Context, client *emergent.Client, spec *graph.GraphObject) (*gherkin.Feature, int, error) {
+	feature := &gherkin.Feature{
+		Name:        getString(spec.Properties, "name"),
+		Description: getString(spec.Properties, "purpose"),
+		Tags:        getStringSlice(spec.Properties, "tags"),
+	}
+	count := 0
+	reqs, err := linkedObjects(ctx, client, spec.ID, emergent.RelHasRequirement)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, req := range reqs {
+		rule := &gherkin.Rule{
+			Name:        getString(req.Properties, "name"),
+			Description: getString(req.Properties, "description"),
+			Tags:        getStringSlice(req.Properties, "tags"),
+		}
+		if s := getString(req.Properties, "strength"); s != "" && s != markdown.ImpliedStrength(rule.Description) {
+			rule.Tags = append(rule.Tags, strengthTagPrefix+strings.ToLower(s))
+		}
+		scenarios, err := linkedObjects(ctx, client, req.ID, emergent.RelHasScenario)
+		if err != nil {
+			return nil, 0, err
+		}
+		inRequirement := emergent.IDSet{}
+		for _, sc := range scenarios {
+			inRequirement[sc.ID] = true
+			inRequirement[sc.CanonicalID] = true
+		}
+		for _, sc := range scenarios {
+			edges, err := client.GetObjectEdges(ctx, sc.ID, &graph.GetObjectEdgesOptions{Type: emergent.RelVariantOf})
+			if err != nil {
+				return nil, 0, fmt.Errorf("getting variants of %s: %w", sc.ID, err)
+			}
+			// A variant of a scenario in this requirement is written in its
+			// base's Examples table instead.
+			isVariant := false
+			for _, e := range edges.Outgoing {
+				if inRequirement[e.DstID] {
+					isVariant = true
+				}
+			}
+			if isVariant {
+				continue
+			}
+			gs, err := loadGherkinScenario(ctx, client, sc)
+			if err != nil {
+				return nil, 0, err
+			}
+			var variantIDs []string
+			for _, e := range edges.Incoming {
+				variantIDs = append(variantIDs, e.SrcID)
+			}
+			variants, err := client.GetObjects(ctx, variantIDs)
+			if err != nil {
+				return nil, 0, fmt.Errorf("getting variants of %s: %w", sc.ID, err)
+			}
+			seen := map[string]bool{}
+			for _, v := range variants {
+				if seen[v.CanonicalID] {
+					continue
+				}
+				seen[v.CanonicalID] = true
+				gv, err := loadGherkinScenario(ctx, client, v)
+				if err != nil {
+					return nil, 0, err
+				}
+				gs.Variants = append(gs.Variants, gv)
+			}
+			rule.Scenarios = append(rule.Scenarios, gs)
+			count += 1 + len(gs.Variants)
+		}
+		feature.Rules = append(feature.Rules, rule)
+	}
+	return feature, count, nil
+}
+
+// loadGherkinScenario reads a scenario with its ScenarioSteps, in sequence
+// order, and executed_by actors as @actor: tags.
+func loadGherkinScenario(ctx context.Context, client *emergent.Client, sc *graph.GraphObject) (*gherkin.Scenario, error) {
+	gs := &gherkin.Scenario{
+		Name:    getString(sc.Properties, "name"),
+		Tags:    getStringSlice(sc.Properties, "tags"),
+		Given:   getString(sc.Properties, "given"),
+		When:    getString(sc.Properties, "when"),
+		Then:    getString(sc.Properties, "then"),
+		AndAlso: getStringSlice(sc.Properties, "and_also"),
+	}
+	steps, err := linkedObjects(ctx, client, sc.ID, emergent.RelHasStep)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		return getInt(steps[i].Properties, "sequence") < getInt(steps[j].Properties, "sequence")
+	})
+	for _, s := range steps {
+		gs.Steps = append(gs.Steps, getString(s.Properties, "description"))
+	}
+	actors, err := linkedObjects(ctx, client, sc.ID, emergent.RelExecutedBy)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range actors {
+		gs.Tags = append(gs.Tags, actorTagPrefix+getString(a.Properties, "name"))
+	}
+	return gs, nil
+}
+
+// --- spec_import_gherkin ---
+
+// specImportGherkinParams defines the input for spec_import_gherkin.
+type specImportGherkinParams struct {
+	ChangeID string            `json:"change_id"`
+	Files    map[string]string `json:"files"`
+	DryRun   bool              `json:"dry_run,omitempty"`
+}
+
+// SpecImportGherkin imports Gherkin feature files into a change.
+type SpecImportGherkin struct {
+	factory *emergent.ClientFactory
+}
+
+// NewSpecImportGherkin creates a SpecImportGherkin tool.
+func NewSpecImportGherkin(factory *emergent.ClientFactory) *SpecImportGherkin {
+	return &SpecImportGherkin{factory: factory}
+}
+
+func (t *SpecImportGherkin) Name() string { return "spec_import_gherkin" }
+
+func (t *SpecImportGherkin) Description() string {
+	return "Import Gherkin .feature files, passed as content keyed by file name, into a change: each Feature becomes a Spec, each Rule a Requirement (scenarios outside a Rule go to a requirement named after the Feature), and each Scenario a Scenario with its And steps after When as ScenarioSteps. Scenario Outline rows become variant scenarios linked by variant_of to the first row. @actor:<name> tags link executed_by to existing Actors; other tags become Tags. Re-importing is idempotent: entities are matched by name under their parent and only updated when they differ."
+}
+
+func (t *SpecImportGherkin) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "change_id": {
+      "type": "string",
+      "description": "ID or name of the change to import into"
+    },
+    "files": {
+      "type": "object",
+      "additionalProperties": {"type": "string"},
+      "description": "Feature file content keyed by file name, e.g. {\"login.feature\": \"Feature: Login ...\"}"
+    },
+    "dry_run": {
+      "type": "boolean",
+      "description": "Report what would be created or updated without writing anything. Default: false"
+    }
+  },
+  "required": ["change_id", "files"]
+}`))
+}
+
+func (t *SpecImportGherkin) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p specImportGherkinParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if p.ChangeID == "" {
+		return mcp.ErrorResult("change_id is required"), nil
+	}
+	if len(p.Files) == 0 {
+		return mcp.ErrorResult("files is required"), nil
+	}
+
+	names := make([]string, 0, len(p.Files))
+	for name := range p.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var features []*gherkin.Feature
+	for _, name := range names {
+		f, err := gherkin.Parse(p.Files[name])
+		if err != nil {
+			return mcp.ErrorResult(fmt.Sprintf("%s: %v", name, err)), nil
+		}
+		features = append(features, f)
+	}
+
+	change, err := client.ResolveChange(ctx, p.ChangeID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("change not found: %v", err)), nil
+	}
+	if change.Status == emergent.StatusArchived {
+		return mcp.ErrorResult(fmt.Sprintf("change %q is archived; unarchive it before importing", change.Name)), nil
+	}
+	ctx = emergent.WithBranch(ctx, change.BranchID)
+
+	// Record creates in a unit of work so a failure partway through deletes
+	// what this import created.
+	uow := client.NewUnitOfWork()
+	ctx = emergent.WithUnitOfWork(ctx, uow)
+
+	im := newArtifactImporter(client, t.Name(), p.DryRun)
+	warnings, err := im.importFeatures(ctx, change.ID, features)
+	if err != nil {
+		if errors.Is(err, emergent.ErrVersionConflict) || errors.Is(err, validation.ErrInvalidTransition) {
+			return mcp.ErrorResult(uow.Fail(ctx, err).Error()), nil
+		}
+		return nil, uow.Fail(ctx, err)
+	}
+	warnings = append(warnings, im.warnings...)
+
+	result := map[string]any{
+		"change_id": change.ID,
+		"files":     names,
+		"dry_run":   p.DryRun,
+		"counts":    im.counts,
+		"message": fmt.Sprintf("Imported %d feature file(s) into %q: %d created, %d updated, %d unchanged",
+			len(names), change.Name, im.total(func(c *importCount) int { return c.Created }),
+			im.total(func(c *importCount) int { return c.Updated }),
+			im.total(func(c *importCount) int { return c.Unchanged })),
+	}
+	if len(im.revertedParents) > 0 {
+		result["reverted_parents"] = im.revertedParents
+	}
+	if len(warnings) > 0 {
+		result["warnings"] = warnings
+	}
+	return mcp.JSONResult(result)
+}
+
+// importFeatures upserts a Spec per feature with a Requirement per Rule,
+// returning warnings for actors that don't exist.
+func (im *artifactImporter) importFeatures(ctx context.Context, changeID string, features []*gherkin.Feature) ([]string, error) {
+	specs, err := im.children(ctx, changeID, emergent.RelHasSpec, "name")
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	for _, f := range features {
+		specID, err := im.upsert(ctx, changeID, emergent.RelHasSpec, emergent.TypeSpec, f.Name, specs, map[string]any{
+			"name":    f.Name,
+			"purpose": f.Description,
+			"tags":    f.Tags,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("importing feature %q: %w", f.Name, err)
+		}
+		reqs, err := im.children(ctx, specID, emergent.RelHasRequirement, "name")
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range f.Rules {
+			w, err := im.importRule(ctx, specID, reqs, f, r)
+			if err != nil {
+				return nil, err
+			}
+			warnings = append(warnings, w...)
+		}
+	}
+	return warnings, nil
+}
+
+// importRule upserts a Rule's requirement and its scenarios, returning
+// warnings for actors that don't exist.
+func (im *artifactImporter) importRule(ctx context.Context, specID string, reqs map[string]*graph.GraphObject, f *gherkin.Feature, r *gherkin.Rule) ([]string, error) {
+	name := r.Name
+	if name == "" {
+		name = f.Name
+	}
+	tags, strength := splitTags(r.Tags, strengthTagPrefix)
+	props := map[string]any{
+		"name":        name,
+		"description": r.Description,
+		"tags":        tags,
+		"strength":    markdown.ImpliedStrength(r.Description),
+	}
+	if len(strength) > 0 {
+		props["strength"] = strings.ToUpper(strength[0])
+	}
+	reqID, err := im.upsert(ctx, specID, emergent.RelHasRequirement, emergent.TypeRequirement, name, reqs, props)
+	if err != nil {
+		return nil, fmt.Errorf("importing rule %q: %w", name, err)
+	}
+	scenarios, err := im.children(ctx, reqID, emergent.RelHasScenario, "name")
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	for _, sc := range r.Scenarios {
+		baseID, w, err := im.importScenario(ctx, reqID, scenarios, sc)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, w...)
+		for _, v := range sc.Variants {
+			_, existed := scenarios[v.Name]
+			variantID, w, err := im.importScenario(ctx, reqID, scenarios, v)
+			if err != nil {
+				return nil, err
+			}
+			warnings = append(warnings, w...)
+			if !existed && variantID != "" && baseID != "" {
+				if _, err := im.client.CreateRelationship(ctx, emergent.RelVariantOf, variantID, baseID, nil); err != nil {
+					return nil, fmt.Errorf("linking variant %q to %q: %w", v.Name, sc.Name, err)
+				}
+			}
+		}
+	}
+	return warnings, nil
+}
+
+// importScenario upserts a scenario, its steps by sequence, and its
+// executed_by actors.
+func (im *artifactImporter) importScenario(ctx context.Context, reqID string, existing map[string]*graph.GraphObject, sc *gherkin.Scenario) (string, []string, error) {
+	tags, actors := splitTags(sc.Tags, actorTagPrefix)
+	id, err := im.upsert(ctx, reqID, emergent.RelHasScenario, emergent.TypeScenario, sc.Name, existing, map[string]any{
+		"name":     sc.Name,
+		"given":    sc.Given,
+		"when":     sc.When,
+		"then":     sc.Then,
+		"and_also": sc.AndAlso,
+		"tags":     tags,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("importing scenario %q: %w", sc.Name, err)
+	}
+
+	steps, err := im.children(ctx, id, emergent.RelHasStep, "sequence")
+	if err != nil {
+		return "", nil, err
+	}
+	for i, s := range sc.Steps {
+		seq := fmt.Sprint(i + 1)
+		if _, err := im.upsert(ctx, id, emergent.RelHasStep, emergent.TypeScenarioStep, seq, steps, map[string]any{
+			"sequence":    i + 1,
+			"description": s,
+		}); err != nil {
+			return "", nil, fmt.Errorf("importing step %d of %q: %w", i+1, sc.Name, err)
+		}
+	}
+
+	var warnings []string
+	if len(actors) == 0 || id == "" {
+		return id, warnings, nil
+	}
+	linked, err := im.children(ctx, id, emergent.RelExecutedBy, "name")
+	if err != nil {
+		return "", nil, err
+	}
+	for _, name := range actors {
+		if _, ok := linked[name]; ok {
+			continue
+		}
+		actor, err := im.client.FindByTypeAndKey(ctx, emergent.TypeActor, name)
+		if err != nil {
+			return "", nil, fmt.Errorf("looking up actor %q: %w", name, err)
+		}
+		if actor == nil {
+			warnings = append(warnings, fmt.Sprintf("scenario %q: actor %q not found, executed_by not linked", sc.Name, name))
+			continue
+		}
+		if !im.dryRun {
+			if _, err := im.client.CreateRelationship(ctx, emergent.RelExecutedBy, id, actor.ID, nil); err != nil {
+				return "", nil, fmt.Errorf("linking %q to actor %q: %w", sc.Name, name, err)
+			}
+		}
+	}
+	return id, warnings, nil
+}
+
+// splitTags separates tags with the given prefix, returned without it, from
+// the rest.
+func splitTags(tags []string, prefix string) (rest, matched []string) {
+	for _, t := range tags {
+		if v, ok := strings.CutPrefix(t, prefix); ok {
+			matched = append(matched, v)
+		} else {
+			rest = append(rest, t)
+		}
+	}
+	return rest, matched
+}
@@ -0,0 +1,522 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/markdown"
+	"github.com/emergent-company/specmcp/internal/mcp"
+	"github.com/emergent-company/specmcp/internal/validation"
+)
+
+// specImportMarkdownParams defines the input for spec_import_markdown.
+type specImportMarkdownParams struct {
+	Files  map[string]string `json:"files"`
+	Name   string            `json:"name"`
+	DryRun bool              `json:"dry_run,omitempty"`
+}
+
+// SpecImportMarkdown imports an OpenSpec-style markdown change directory.
+type SpecImportMarkdown struct {
+	factory *emergent.ClientFactory
+}
+
+// NewSpecImportMarkdown creates a SpecImportMarkdown tool.
+func NewSpecImportMarkdown(factory *emergent.ClientFactory) *SpecImportMarkdown {
+	return &SpecImportMarkdown{factory: factory}
+}
+
+func (t *SpecImportMarkdown) Name() string { return "spec_import_markdown" }
+
+func (t *SpecImportMarkdown) Description() string {
+	return "Import a markdown change directory, passed as file contents keyed by path (proposal.md, design.md, tasks.md, specs/<capability>/spec.md), into a Change with its Proposal, Specs, Requirements, Scenarios, Design, and Tasks. Task checkboxes set task status and '1.1'-style numbers become task numbers. Re-importing is idempotent: entities are matched by key (spec, requirement, and scenario name; task number) under their parent and only updated when their content differs. Entities missing from the markdown are left in place."
+}
+
+func (t *SpecImportMarkdown) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "files": {
+      "type": "object",
+      "additionalProperties": {"type": "string"},
+      "description": "File content keyed by slash separated path within the change directory, e.g. {\"proposal.md\": \"...\", \"specs/auth/spec.md\": \"...\"}"
+    },
+    "name": {
+      "type": "string",
+      "description": "Change name"
+    },
+    "dry_run": {
+      "type": "boolean",
+      "description": "Report what would be created or updated without writing anything. Default: false"
+    }
+  },
+  "required": ["files", "name"]
+}`))
+}
+
+func (t *SpecImportMarkdown) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p specImportMarkdownParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if len(p.Files) == 0 || p.Name == "" {
+		return mcp.ErrorResult("files and name are required"), nil
+	}
+	doc, err := markdown.ParseFiles(p.Files)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("reading markdown: %v", err)), nil
+	}
+	doc.Name = p.Name
+
+	change, err := client.FindChange(ctx, doc.Name)
+	if err != nil {
+		return nil, fmt.Errorf("looking up change: %w", err)
+	}
+	if change != nil && change.Status == emergent.StatusArchived {
+		return mcp.ErrorResult(fmt.Sprintf("change %q is archived; unarchive it before re-importing", doc.Name)), nil
+	}
+
+	// Record creates in a unit of work so a failure partway through deletes
+	// what this import created instead of leaving a half-imported change.
+	uow := client.NewUnitOfWork()
+	ctx = emergent.WithUnitOfWork(ctx, uow)
+
+	im := newArtifactImporter(client, t.Name(), p.DryRun)
+	changeID := ""
+	switch {
+	case change != nil:
+		changeID = change.ID
+		im.count(emergent.TypeChange).Unchanged++
+		ctx = emergent.WithBranch(ctx, change.BranchID)
+	case p.DryRun:
+		im.count(emergent.TypeChange).Created++
+	default:
+		change, err = client.CreateChange(ctx, &emergent.Change{
+			Name:   doc.Name,
+			Status: emergent.StatusActive,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating change: %w", err)
+		}
+		changeID = change.ID
+		im.count(emergent.TypeChange).Created++
+	}
+
+	if err := im.importChange(ctx, changeID, doc); err != nil {
+		if errors.Is(err, emergent.ErrVersionConflict) || errors.Is(err, validation.ErrInvalidTransition) {
+			return mcp.ErrorResult(uow.Fail(ctx, err).Error()), nil
+		}
+		return nil, uow.Fail(ctx, err)
+	}
+
+	result := map[string]any{
+		"name":    doc.Name,
+		"dry_run": p.DryRun,
+		"counts":  im.counts,
+	}
+	if changeID != "" {
+		result["change_id"] = changeID
+	}
+	if len(im.revertedParents) > 0 {
+		result["reverted_parents"] = im.revertedParents
+	}
+	if len(im.warnings) > 0 {
+		result["warnings"] = im.warnings
+	}
+	verb := "Imported"
+	if p.DryRun {
+		verb = "Dry run of importing"
+	}
+	result["message"] = fmt.Sprintf("%s %q: %d created, %d updated, %d unchanged",
+		verb, doc.Name, im.total(func(c *importCount) int { return c.Created }),
+		im.total(func(c *importCount) int { return c.Updated }),
+		im.total(func(c *importCount) int { return c.Unchanged }))
+	return mcp.JSONResult(result)
+}
+
+// importCount tallies what an import did to one entity type.
+type importCount struct {
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+}
+
+// artifactImporter reconciles imported artifacts (markdown, Gherkin) with
+// the graph.
+type artifactImporter struct {
+	client *emergent.Client
+	via    string // importing tool, recorded in transition histories
+	dryRun bool
+	counts map[string]*importCount
+
+	objects         map[string]*graph.GraphObject // by ID, as read or created
+	parents         map[string]string             // artifact ID → enclosing artifact ID
+	reverted        map[string]bool               // parents already reset to draft
+	revertedParents []artifactRef
+	warnings        []string
+}
+
+func newArtifactImporter(client *emergent.Client, via string, dryRun bool) *artifactImporter {
+	return &artifactImporter{
+		client:   client,
+		via:      via,
+		dryRun:   dryRun,
+		counts:   map[string]*importCount{},
+		objects:  map[string]*graph.GraphObject{},
+		parents:  map[string]string{},
+		reverted: map[string]bool{},
+	}
+}
+
+func (im *artifactImporter) count(typeName string) *importCount {
+	c, ok := im.counts[typeName]
+	if !ok {
+		c = &importCount{}
+		im.counts[typeName] = c
+	}
+	return c
+}
+
+func (im *artifactImporter) total(field func(*importCount) int) int {
+	n := 0
+	for _, c := range im.counts {
+		n += field(c)
+	}
+	return n
+}
+
+// importChange writes every artifact under the change. changeID is empty
+// only on a dry run for a change that doesn't exist yet.
+func (im *artifactImporter) importChange(ctx context.Context, changeID string, doc *markdown.Change) error {
+	if doc.Proposal != nil {
+		existing, err := im.children(ctx, changeID, emergent.RelHasProposal, "")
+		if err != nil {
+			return err
+		}
+		if _, err := im.upsert(ctx, changeID, emergent.RelHasProposal, emergent.TypeProposal, "", existing, map[string]any{
+			"intent": doc.Proposal.Intent,
+			"scope":  doc.Proposal.Scope,
+			"impact": doc.Proposal.Impact,
+		}); err != nil {
+			return fmt.Errorf("importing proposal: %w", err)
+		}
+	}
+
+	specs, err := im.children(ctx, changeID, emergent.RelHasSpec, "name")
+	if err != nil {
+		return err
+	}
+	for _, s := range doc.Specs {
+		specID, err := im.upsert(ctx, changeID, emergent.RelHasSpec, emergent.TypeSpec, s.Name, specs, map[string]any{
+			"name":       s.Name,
+			"domain":     s.Name,
+			"purpose":    s.Purpose,
+			"delta_type": s.DeltaType,
+		})
+		if err != nil {
+			return fmt.Errorf("importing spec %q: %w", s.Name, err)
+		}
+		if err := im.importRequirements(ctx, specID, s); err != nil {
+			return err
+		}
+	}
+
+	if doc.Design != nil {
+		existing, err := im.children(ctx, changeID, emergent.RelHasDesign, "")
+		if err != nil {
+			return err
+		}
+		if _, err := im.upsert(ctx, changeID, emergent.RelHasDesign, emergent.TypeDesign, "", existing, map[string]any{
+			"approach":     doc.Design.Approach,
+			"decisions":    doc.Design.Decisions,
+			"data_flow":    doc.Design.DataFlow,
+			"file_changes": doc.Design.FileChanges,
+		}); err != nil {
+			return fmt.Errorf("importing design: %w", err)
+		}
+	}
+
+	tasks, err := im.children(ctx, changeID, emergent.RelHasTask, "number")
+	if err != nil {
+		return err
+	}
+	for _, t := range doc.Tasks {
+		parentID, err := im.importTask(ctx, changeID, tasks, t)
+		if err != nil {
+			return err
+		}
+		for _, sub := range t.Subtasks {
+			_, existed := tasks[sub.Number]
+			subID, err := im.importTask(ctx, changeID, tasks, sub)
+			if err != nil {
+				return err
+			}
+			if !existed && subID != "" && parentID != "" {
+				if _, err := im.client.CreateRelationship(ctx, emergent.RelHasSubtask, parentID, subID, nil); err != nil {
+					return fmt.Errorf("linking subtask %s to %s: %w", sub.Number, t.Number, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (im *artifactImporter) importRequirements(ctx context.Context, specID string, s *markdown.Spec) error {
+	reqs, err := im.children(ctx, specID, emergent.RelHasRequirement, "name")
+	if err != nil {
+		return err
+	}
+	for _, r := range s.Requirements {
+		reqID, err := im.upsert(ctx, specID, emergent.RelHasRequirement, emergent.TypeRequirement, r.Name, reqs, map[string]any{
+			"name":         r.Name,
+			"description":  r.Description,
+			"strength":     r.Strength,
+			"delta_type":   r.DeltaType,
+			"renamed_from": r.RenamedFrom,
+		})
+		if err != nil {
+			return fmt.Errorf("importing requirement %q: %w", r.Name, err)
+		}
+		scenarios, err := im.children(ctx, reqID, emergent.RelHasScenario, "name")
+		if err != nil {
+			return err
+		}
+		for _, sc := range r.Scenarios {
+			if _, err := im.upsert(ctx, reqID, emergent.RelHasScenario, emergent.TypeScenario, sc.Name, scenarios, map[string]any{
+				"name":     sc.Name,
+				"given":    sc.Given,
+				"when":     sc.When,
+				"then":     sc.Then,
+				"and_also": sc.AndAlso,
+			}); err != nil {
+				return fmt.Errorf("importing scenario %q: %w", sc.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// importTask upserts one task under the change and returns its ID. The
+// checkbox status is applied through the task transition table.
+func (im *artifactImporter) importTask(ctx context.Context, changeID string, existing map[string]*graph.GraphObject, t *markdown.Task) (string, error) {
+	id, err := im.upsert(ctx, changeID, emergent.RelHasTask, emergent.TypeTask, t.Number, existing, map[string]any{
+		"number":      t.Number,
+		"description": t.Description,
+		"status":      t.Status,
+	})
+	if err != nil {
+		return "", fmt.Errorf("importing task %s: %w", t.Number, err)
+	}
+	return id, nil
+}
+
+// children returns the objects linked from parentID by relType, indexed by
+// the keyProp property ("" indexes the first one under "").
+func (im *artifactImporter) children(ctx context.Context, parentID, relType, keyProp string) (map[string]*graph.GraphObject, error) {
+	out := map[string]*graph.GraphObject{}
+	objs, err := linkedObjects(ctx, im.client, parentID, relType)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objs {
+		key := ""
+		if v, ok := obj.Properties[keyProp]; ok && keyProp != "" {
+			key = fmt.Sprint(v)
+		}
+		if _, dup := out[key]; !dup {
+			out[key] = obj
+		}
+	}
+	return out, nil
+}
+
+// upsert updates the child of parentID stored under key in existing when
+// its properties differ from props, or creates and links it when there is
+// none. A key of "" creates an unkeyed object (Proposal, Design), and
+// ScenarioSteps, matched by sequence, are created unkeyed too. Changing a
+// ready artifact puts it back to draft, as spec_update_artifact does, and
+// changing or adding a child resets its ready parents. An imported task
+// status is applied only if the transition table allows it. Returns the
+// object ID, which is empty on a dry-run create.
+func (im *artifactImporter) upsert(ctx context.Context, parentID, relType, typeName, key string, existing map[string]*graph.GraphObject, props map[string]any) (string, error) {
+	props, err := normalizeProps(props)
+	if err != nil {
+		return "", err
+	}
+	if obj, ok := existing[key]; ok {
+		im.track(obj, parentID, relType)
+		changed := map[string]any{}
+		for k, v := range props {
+			if !sameProp(obj.Properties[k], v) {
+				changed[k] = v
+			}
+		}
+		if err := im.transition(ctx, obj, changed); err != nil {
+			return "", err
+		}
+		if len(changed) == 0 {
+			im.count(typeName).Unchanged++
+			return obj.ID, nil
+		}
+		im.count(typeName).Updated++
+		if im.dryRun {
+			return obj.ID, nil
+		}
+		updated, err := im.client.UpdateObjectIfVersion(emergent.WithBranch(ctx, emergent.ObjectBranch(obj)), obj.ID, obj.Version, changed, nil)
+		if err != nil {
+			return "", fmt.Errorf("updating %s %s: %w", typeName, obj.ID, err)
+		}
+		if err := im.revertParents(ctx, obj.ID, fmt.Sprintf("%s %s re-imported", typeName, obj.ID)); err != nil {
+			return "", err
+		}
+		return updated.ID, nil
+	}
+
+	im.count(typeName).Created++
+	if im.dryRun {
+		return "", nil
+	}
+	if emergent.IsWorkflowArtifactType(typeName) {
+		props["status"] = emergent.StatusDraft
+	}
+	var keyPtr *string
+	if key != "" && typeName != emergent.TypeScenarioStep {
+		keyPtr = &key
+	}
+	obj, err := im.client.CreateObject(ctx, typeName, keyPtr, props, nil)
+	if err != nil {
+		return "", err
+	}
+	if _, err := im.client.CreateRelationship(ctx, relType, parentID, obj.ID, nil); err != nil {
+		return "", fmt.Errorf("linking %s: %w", typeName, err)
+	}
+	existing[key] = obj
+	im.track(obj, parentID, relType)
+	if err := im.revertParents(ctx, obj.ID, fmt.Sprintf("%s added", typeName)); err != nil {
+		return "", err
+	}
+	return obj.ID, nil
+}
+
+// nestedRelTypes are the relationships whose parent is itself an artifact
+// whose readiness covers the child's content.
+var nestedRelTypes = map[string]bool{
+	emergent.RelHasRequirement: true,
+	emergent.RelHasScenario:    true,
+	emergent.RelHasStep:        true,
+}
+
+// track remembers obj and, for artifacts nested in another artifact, its
+// parent, so revertParents can walk up without re-reading the graph.
+func (im *artifactImporter) track(obj *graph.GraphObject, parentID, relType string) {
+	im.objects[obj.ID] = obj
+	if nestedRelTypes[relType] {
+		im.parents[obj.ID] = parentID
+	}
+}
+
+// transition folds the status change in changed into the update the way
+// the transition registry would: a ready artifact whose content changed
+// goes back to draft, and an imported task status is validated and
+// recorded in the task's history, or dropped with a warning if the table
+// doesn't allow it.
+func (im *artifactImporter) transition(ctx context.Context, obj *graph.GraphObject, changed map[string]any) error {
+	from := validation.Status(obj)
+	tctx := &validation.TransitionContext{Client: im.client, Ctx: ctx}
+	if to, ok := changed["status"].(string); ok {
+		// The markdown is the source of this task's state, so the soft
+		// guards (e.g. subtasks completed first) don't apply.
+		tctx.Force = true
+		if err := cascadeTransitions.Validate(obj.Type, from, to, tctx, obj.ID); err != nil {
+			delete(changed, "status")
+			im.warnings = append(im.warnings, fmt.Sprintf("%s %s: kept status %s: %v", obj.Type, obj.ID, from, err))
+		} else {
+			validation.AppendHistory(changed, obj, from, validation.Transition{
+				To:     to,
+				Force:  true,
+				Reason: "imported",
+				Via:    im.via,
+			})
+		}
+		return nil
+	}
+	if len(changed) == 0 || !emergent.IsWorkflowArtifactType(obj.Type) || from != emergent.StatusReady {
+		return nil
+	}
+	if err := cascadeTransitions.Validate(obj.Type, from, emergent.StatusDraft, tctx, obj.ID); err != nil {
+		return err
+	}
+	changed["status"] = emergent.StatusDraft
+	validation.AppendHistory(changed, obj, from, validation.Transition{
+		To:     emergent.StatusDraft,
+		Reason: "re-imported",
+		Via:    im.via,
+	})
+	return nil
+}
+
+// revertParents resets the ready artifacts above id to draft: their
+// readiness covered the old content.
+func (im *artifactImporter) revertParents(ctx context.Context, id, reason string) error {
+	for parentID := im.parents[id]; parentID != ""; parentID = im.parents[parentID] {
+		parent := im.objects[parentID]
+		if im.reverted[parentID] || validation.Status(parent) != emergent.StatusReady {
+			continue
+		}
+		ok, err := revertToDraft(ctx, im.client, parentID, reason)
+		if err != nil {
+			return fmt.Errorf("resetting parent readiness: %w", err)
+		}
+		im.reverted[parentID] = true
+		if ok {
+			im.revertedParents = append(im.revertedParents, refOf(parent))
+		}
+	}
+	return nil
+}
+
+// normalizeProps round-trips props through JSON so they compare equal to
+// properties read back from the graph.
+func normalizeProps(props map[string]any) (map[string]any, error) {
+	b, err := json.Marshal(props)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// sameProp reports whether a stored property equals an imported one,
+// treating missing, empty string, and empty list alike.
+func sameProp(stored, imported any) bool {
+	if isEmptyProp(stored) && isEmptyProp(imported) {
+		return true
+	}
+	return reflect.DeepEqual(stored, imported)
+}
+
+func isEmptyProp(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return true
+	case string:
+		return x == ""
+	case []any:
+		return len(x) == 0
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/mcp"
+	"github.com/emergent-company/specmcp/internal/validation"
+)
+
+// specMarkDraftParams defines the input for spec_mark_draft.
+type specMarkDraftParams struct {
+	EntityID        string `json:"entity_id"`
+	ExpectedVersion int    `json:"expected_version,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+}
+
+// SpecMarkDraft reopens a ready workflow artifact. It is the reverse of
+// spec_mark_ready: since a parent can only be ready while all its children
+// are, reopening a child also reopens its ready parents.
+type SpecMarkDraft struct {
+	factory     *emergent.ClientFactory
+	transitions *validation.Registry
+}
+
+// NewSpecMarkDraft creates a SpecMarkDraft tool.
+func NewSpecMarkDraft(factory *emergent.ClientFactory) *SpecMarkDraft {
+	return &SpecMarkDraft{
+		factory:     factory,
+		transitions: validation.NewRegistry(),
+	}
+}
+
+func (t *SpecMarkDraft) Name() string { return "spec_mark_draft" }
+
+func (t *SpecMarkDraft) Description() string {
+	return "Move a ready workflow artifact (Proposal, Spec, Requirement, Scenario, Design) back to draft so it can be reworked. Applies the readiness cascade in reverse: ready parents (Scenario → Requirement → Spec) return to draft as well. Reports the Design and Tasks that were built on top of the reopened artifact."
+}
+
+func (t *SpecMarkDraft) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entity_id": {
+      "type": "string",
+      "description": "ID of the workflow artifact to move back to draft"
+    },
+    "expected_version": {
+      "type": "integer",
+      "description": "Only reopen if the artifact is still at this version; a conflict is reported instead of retried"
+    },
+    "reason": {
+      "type": "string",
+      "description": "Why the artifact is being reopened; recorded in the transition history"
+    }
+  },
+  "required": ["entity_id"]
+}`))
+}
+
+func (t *SpecMarkDraft) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p specMarkDraftParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if p.EntityID == "" {
+		return mcp.ErrorResult("entity_id is required"), nil
+	}
+
+	obj, err := client.GetObject(ctx, p.EntityID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("entity not found: %v", err)), nil
+	}
+	ctx = emergent.WithBranch(ctx, emergent.ObjectBranch(obj))
+	if !emergent.IsWorkflowArtifactType(obj.Type) {
+		return mcp.ErrorResult(fmt.Sprintf(
+			"entity type %q is not a workflow artifact. Only Proposal, Spec, Requirement, Scenario, and Design support readiness tracking.",
+			obj.Type,
+		)), nil
+	}
+
+	tctx := &validation.TransitionContext{Client: client, Ctx: ctx}
+	err = t.transitions.Validate(obj.Type, statusOf(obj), emergent.StatusDraft, tctx, obj.ID)
+	if errors.Is(err, validation.ErrAlreadyInState) {
+		return mcp.JSONResult(map[string]any{
+			"entity_id": obj.ID,
+			"type":      obj.Type,
+			"status":    emergent.StatusDraft,
+			"message":   "Already draft",
+		})
+	}
+	if err != nil {
+		return mcp.ErrorResult(err.Error()), nil
+	}
+
+	ancestors, change, err := artifactLineage(ctx, client, obj)
+	if err != nil {
+		return nil, fmt.Errorf("resolving parents: %w", err)
+	}
+	if change != nil {
+		if s, _ := change.Properties["status"].(string); s == emergent.StatusArchived {
+			return mcp.ErrorResult("cannot reopen artifacts of an archived change; unarchive the change first"), nil
+		}
+	}
+
+	// Reopen the artifact, guarded by the version we validated. On a
+	// conflict without a pinned expected_version, re-read and retry unless
+	// another writer already reopened it.
+	_, err = t.transitions.Apply(ctx, client, obj, validation.Transition{
+		To:              emergent.StatusDraft,
+		Reason:          p.Reason,
+		Via:             t.Name(),
+		ExpectedVersion: p.ExpectedVersion,
+	})
+	switch {
+	case errors.Is(err, validation.ErrAlreadyInState):
+	case errors.Is(err, emergent.ErrVersionConflict), errors.Is(err, validation.ErrRejected):
+		return mcp.ErrorResult(err.Error()), nil
+	case err != nil:
+		return nil, fmt.Errorf("updating status to draft: %w", err)
+	}
+
+	// Reverse cascade: a ready parent asserts that all its children are
+	// ready, which no longer holds.
+	var reverted []artifactRef
+	for _, a := range ancestors {
+		if statusOf(a) != emergent.StatusReady {
+			continue
+		}
+		if err := t.transitions.Validate(a.Type, emergent.StatusReady, emergent.StatusDraft, tctx, a.ID); err != nil {
+			return mcp.ErrorResult(fmt.Sprintf("cannot reopen parent %s %s: %v", a.Type, a.ID, err)), nil
+		}
+		ok, err := revertToDraft(ctx, client, a.ID, fmt.Sprintf("%s %s reopened", obj.Type, obj.ID))
+		if err != nil {
+			return nil, fmt.Errorf("reopening parent: %w", err)
+		}
+		if ok {
+			reverted = append(reverted, refOf(a))
+		}
+	}
+
+	result := map[string]any{
+		"entity_id": obj.ID,
+		"type":      obj.Type,
+		"status":    emergent.StatusDraft,
+		"message":   fmt.Sprintf("Moved %s back to draft", obj.Type),
+	}
+	if ref := refOf(obj); ref.Name != "" {
+		result["name"] = ref.Name
+	}
+	if len(reverted) > 0 {
+		result["reverted_parents"] = reverted
+	}
+	if change != nil {
+		builtOnTop, err := downstreamStages(ctx, client, change.ID, changeArtifact(obj, ancestors))
+		if err != nil {
+			return nil, fmt.Errorf("checking downstream stages: %w", err)
+		}
+		if len(builtOnTop) > 0 {
+			result["built_on_top"] = builtOnTop
+		}
+	}
+
+	return mcp.JSONResult(result)
+}
@@ -3,46 +3,93 @@ package workflow
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"strings"
 
 	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/config"
 	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/guards"
 	"github.com/emergent-company/specmcp/internal/mcp"
+	"github.com/emergent-company/specmcp/internal/validation"
 )
 
 // specMarkReadyParams defines the input for spec_mark_ready.
 type specMarkReadyParams struct {
-	EntityID string `json:"entity_id"`
+	EntityID        string `json:"entity_id"`
+	ExpectedVersion int    `json:"expected_version,omitempty"`
+	Force           bool   `json:"force,omitempty"`
+	Reason          string `json:"reason,omitempty"`
 }
 
 // SpecMarkReady marks a workflow artifact as ready after validating
-// that all its children (if any) are already ready.
+// that all its children (if any) are already ready. Requirements are also
+// checked by the requirement quality linter, artifact types covered by the
+// review policy need their approvals, and open comment threads soft-block.
 type SpecMarkReady struct {
-	factory *emergent.ClientFactory
+	factory        *emergent.ClientFactory
+	runner         *guards.Runner
+	lint           guards.LintOptions
+	linting        bool
+	reviewPolicy   guards.ReviewPolicy
+	reviewSeverity guards.Severity
+	transitions    *validation.Registry
 }
 
-// NewSpecMarkReady creates a SpecMarkReady tool.
-func NewSpecMarkReady(factory *emergent.ClientFactory) *SpecMarkReady {
-	return &SpecMarkReady{factory: factory}
+// NewSpecMarkReady creates a SpecMarkReady tool. An optional LintConfig
+// configures the requirement quality guard; without one it runs with the
+// defaults.
+func NewSpecMarkReady(factory *emergent.ClientFactory, cfg ...config.LintConfig) *SpecMarkReady {
+	lint, linting := lintOptions(cfg)
+	return &SpecMarkReady{
+		factory:        factory,
+		runner:         guards.NewRunner(),
+		lint:           lint,
+		linting:        linting,
+		reviewSeverity: guards.HardBlock,
+		transitions:    validation.NewRegistry(),
+	}
+}
+
+// SetReviewPolicy sets the configured review policy and the severity of the
+// review_approval guard. A Constitution's review_policy overrides the rules
+// per artifact type.
+func (t *SpecMarkReady) SetReviewPolicy(policy guards.ReviewPolicy, severity guards.Severity) {
+	t.reviewPolicy = policy
+	t.reviewSeverity = severity
 }
 
 func (t *SpecMarkReady) Name() string { return "spec_mark_ready" }
 
 func (t *SpecMarkReady) Description() string {
-	return "Mark a workflow artifact (Proposal, Spec, Requirement, Scenario, Design) as ready. Validates that all children are ready before allowing the transition: a Spec requires all its Requirements to be ready, and a Requirement requires all its Scenarios to be ready. Artifacts must be marked ready bottom-up before the next workflow stage unlocks."
+	return "Mark a workflow artifact (Proposal, Spec, Requirement, Scenario, Design) as ready. Validates that all children are ready before allowing the transition: a Spec requires all its Requirements to be ready, and a Requirement requires all its Scenarios to be ready. Artifacts must be marked ready bottom-up before the next workflow stage unlocks. Requirements are linted for RFC 2119 keywords consistent with strength, vague terms, and non-testable phrasing; issues come back with suggested rewrites. Artifact types covered by the review policy (config or Constitution review_policy, e.g. 'Proposal: 1 human') need that many approvals from spec_approve_review and no outstanding rejection. Unresolved comment threads on the artifact soft-block. Depending on configuration these guards may block (use force=true to override a soft block)."
 }
 
 func (t *SpecMarkReady) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "entity_id": {
       "type": "string",
       "description": "ID of the workflow artifact to mark as ready"
+    },
+    "expected_version": {
+      "type": "integer",
+      "description": "Only mark ready if the artifact is still at this version; a conflict is reported instead of retried"
+    },
+    "force": {
+      "type": "boolean",
+      "description": "Override soft blocks from the requirement quality, review approval, and open comments guards"
+    },
+    "reason": {
+      "type": "string",
+      "description": "Why the artifact is ready; recorded in the transition history"
     }
   },
   "required": ["entity_id"]
-}`)
+}`))
 }
 
 // blocker describes a child entity that is not yet ready.
@@ -73,6 +120,7 @@ func (t *SpecMarkReady) Execute(ctx context.Context, params json.RawMessage) (*m
 	if err != nil {
 		return mcp.ErrorResult(fmt.Sprintf("entity not found: %v", err)), nil
 	}
+	ctx = emergent.WithBranch(ctx, emergent.ObjectBranch(obj))
 
 	// Validate it's a workflow artifact type
 	if !emergent.IsWorkflowArtifactType(obj.Type) {
@@ -122,9 +170,82 @@ func (t *SpecMarkReady) Execute(ctx context.Context, params json.RawMessage) (*m
 		})
 	}
 
-	// All children ready (or no children) — mark as ready
-	_, err = client.UpdateObject(ctx, obj.ID, map[string]any{"status": emergent.StatusReady}, nil)
+	// Run the mark-ready guards: requirement quality for Requirements, the
+	// review policy for artifact types it covers, and open comment threads.
+	gctx := &guards.GuardContext{ArtifactType: strings.ToLower(obj.Type), ArtifactID: obj.ID, Force: p.Force}
+	var readyGuards []guards.Guard
+	details := map[string]any{}
+	if obj.Type == emergent.TypeRequirement && t.linting {
+		gctx.RequirementName = getString(obj.Properties, "name")
+		gctx.RequirementDescription = getString(obj.Properties, "description")
+		gctx.RequirementStrength = getString(obj.Properties, "strength")
+		if issues := guards.LintRequirement(gctx.RequirementDescription, gctx.RequirementStrength, t.lint); len(issues) > 0 {
+			details["lint_issues"] = issues
+			details["suggested_rewrite"] = guards.SuggestRewrite(gctx.RequirementDescription, gctx.RequirementStrength, t.lint)
+		}
+		readyGuards = append(readyGuards, guards.RequirementQuality(t.lint))
+	}
+	policy, err := guards.LoadReviewPolicy(ctx, client, t.reviewPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("loading review policy: %w", err)
+	}
+	if rule, ok := policy.For(obj.Type); ok {
+		if err := guards.PopulateReviewState(ctx, client, gctx, obj); err != nil {
+			return nil, fmt.Errorf("loading reviews: %w", err)
+		}
+		details["review_policy"] = rule.String()
+		details["reviews"] = map[string]any{
+			"approvals":       gctx.Approvals,
+			"human_approvals": gctx.HumanApprovals,
+			"rejected_by":     gctx.RejectedBy,
+			"pending":         gctx.PendingReviewers,
+		}
+		readyGuards = append(readyGuards, guards.ReviewApproval(policy, t.reviewSeverity))
+	}
+	if err := guards.PopulateCommentState(ctx, client, gctx, obj); err != nil {
+		return nil, fmt.Errorf("loading comments: %w", err)
+	}
+	if gctx.OpenComments > 0 {
+		details["open_comments"] = gctx.OpenComments
+	}
+	readyGuards = append(readyGuards, guards.OpenComments)
+	if err := guards.PopulatePolicyState(ctx, client, gctx, obj.ID); err != nil {
+		return nil, fmt.Errorf("loading guard policy: %w", err)
+	}
+	readyGuards, err = guards.WithRules(ctx, client, gctx, guards.RulesMarkReady, readyGuards)
 	if err != nil {
+		return nil, fmt.Errorf("loading guard rules: %w", err)
+	}
+	outcome := t.runner.Run(ctx, gctx, readyGuards)
+	if outcome.Blocked {
+		result := map[string]any{
+			"entity_id": obj.ID,
+			"type":      obj.Type,
+			"status":    emergent.StatusDraft,
+			"message":   outcome.FormatBlockMessage(),
+			"remedy":    "Resolve the blocking guards, then retry.",
+		}
+		maps.Copy(result, details)
+		return mcp.JSONResult(result)
+	}
+
+	// All children ready (or no children) — mark as ready, guarded by the
+	// version we validated. The children and guards were checked above, so
+	// the registry only checks the transition itself. Another writer
+	// marking it ready concurrently is fine.
+	_, err = t.transitions.Apply(ctx, client, obj, validation.Transition{
+		To:              emergent.StatusReady,
+		Force:           p.Force,
+		Guarded:         true,
+		Reason:          p.Reason,
+		Via:             t.Name(),
+		ExpectedVersion: p.ExpectedVersion,
+	})
+	switch {
+	case errors.Is(err, validation.ErrAlreadyInState):
+	case errors.Is(err, emergent.ErrVersionConflict), errors.Is(err, validation.ErrRejected):
+		return mcp.ErrorResult(err.Error()), nil
+	case err != nil:
 		return nil, fmt.Errorf("updating status to ready: %w", err)
 	}
 
@@ -142,6 +263,10 @@ func (t *SpecMarkReady) Execute(ctx context.Context, params json.RawMessage) (*m
 	if name != "" {
 		result["name"] = name
 	}
+	maps.Copy(result, details)
+	if advisory := outcome.FormatAdvisoryMessage(); advisory != "" {
+		result["advisories"] = advisory
+	}
 
 	return mcp.JSONResult(result)
 }
@@ -256,3 +381,18 @@ func (t *SpecMarkReady) findUnreadyChildren(ctx context.Context, client *emergen
 
 	return result, nil
 }
+
+// lintOptions converts the optional lint configuration into linter options,
+// and reports whether linting is enabled.
+func lintOptions(cfg []config.LintConfig) (guards.LintOptions, bool) {
+	opts := guards.DefaultLintOptions()
+	if len(cfg) == 0 {
+		return opts, true
+	}
+	if sev, err := guards.ParseSeverity(cfg[0].Severity); err == nil {
+		opts.Severity = sev
+	}
+	opts.EARS = cfg[0].EARS
+	opts.VagueTerms = cfg[0].VagueTerms
+	return opts, cfg[0].Enabled
+}
@@ -5,8 +5,10 @@ package workflow
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
 	"github.com/emergent-company/specmcp/internal/emergent"
 	"github.com/emergent-company/specmcp/internal/guards"
 	"github.com/emergent-company/specmcp/internal/mcp"
@@ -20,6 +22,10 @@ type specNewParams struct {
 	Impact string   `json:"impact,omitempty"`
 	Tags   []string `json:"tags,omitempty"`
 	Force  bool     `json:"force,omitempty"`
+	Branch bool     `json:"branch,omitempty"`
+
+	DependsOn []string `json:"depends_on,omitempty"`
+	Apps      []string `json:"apps,omitempty"`
 }
 
 // SpecNew creates a new Change with its Proposal.
@@ -39,11 +45,11 @@ func NewSpecNew(factory *emergent.ClientFactory) *SpecNew {
 func (t *SpecNew) Name() string { return "spec_new" }
 
 func (t *SpecNew) Description() string {
-	return "Create a new change with its proposal. Runs pre-change guards to check for constitution, patterns, and project context. Use force=true to override soft blocks."
+	return "Create a new change with its proposal. Runs pre-change guards to check for constitution, patterns, and project context. Use force=true to override soft blocks. With branch=true the change gets its own Emergent branch: its artifacts and any shared entities it touches (Context, UIComponent, DataModel, ...) are written there and only reach the main graph when spec_archive merges the branch, so an abandoned change leaves the shared model untouched. apps scopes the change to Apps, whose per-app guard overrides then apply to it."
 }
 
 func (t *SpecNew) InputSchema() json.RawMessage {
-	return json.RawMessage(`{
+	return mcp.IdempotentSchema(json.RawMessage(`{
   "type": "object",
   "properties": {
     "name": {
@@ -70,10 +76,24 @@ func (t *SpecNew) InputSchema() json.RawMessage {
     "force": {
       "type": "boolean",
       "description": "Override soft blocks (e.g. missing constitution or patterns). Default: false"
+    },
+    "branch": {
+      "type": "boolean",
+      "description": "Create an Emergent branch for the change and route its writes there until spec_archive merges it. Default: false"
+    },
+    "depends_on": {
+      "type": "array",
+      "items": {"type": "string"},
+      "description": "IDs or names of changes this one builds on; archiving it is soft-blocked until they are archived. Change later with spec_set_change_dependencies."
+    },
+    "apps": {
+      "type": "array",
+      "items": {"type": "string"},
+      "description": "IDs or names of the Apps the change affects; links them with scoped_to_app and applies their per-app guard overrides (e.g. 'context_discovery@billing-api: off')"
     }
   },
   "required": ["name", "intent"]
-}`)
+}`))
 }
 
 func (t *SpecNew) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
@@ -107,17 +127,57 @@ func (t *SpecNew) Execute(ctx context.Context, params json.RawMessage) (*mcp.Too
 		)), nil
 	}
 
+	// Resolve dependencies before creating anything so a typo fails cleanly.
+	var deps []*emergent.Change
+	for _, ref := range p.DependsOn {
+		dep, err := client.ResolveChange(ctx, ref)
+		if err != nil {
+			return mcp.ErrorResult(fmt.Sprintf("depends_on: %v", err)), nil
+		}
+		deps = append(deps, dep)
+	}
+	var depGraph *emergent.ChangeGraph
+	if len(deps) > 0 {
+		g, err := client.LoadChangeGraph(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if g.Truncated {
+			return mcp.ErrorResult("depends_on: there are too many changes or dependencies to check the new ones for cycles; create the change without depends_on"), nil
+		}
+		depGraph = g
+	}
+	var apps []*graph.GraphObject
+	var appNames []string
+	for _, ref := range p.Apps {
+		app, err := resolveApp(ctx, client, ref)
+		if err != nil {
+			return nil, err
+		}
+		if app == nil {
+			return mcp.ErrorResult(fmt.Sprintf("apps: App %q not found", ref)), nil
+		}
+		apps = append(apps, app)
+		appNames = append(appNames, getString(app.Properties, "name"))
+	}
+
 	// Build guard context and populate project state
 	gctx := &guards.GuardContext{
 		ChangeName: p.Name,
 		Force:      p.Force,
+		Apps:       appNames,
+		ChangeTags: p.Tags,
 	}
 	if err := guards.PopulateProjectState(ctx, client, gctx); err != nil {
 		return nil, fmt.Errorf("populating project state for guards: %w", err)
 	}
 
-	// Run pre-change guards
-	outcome := t.runner.Run(ctx, gctx, guards.NewChangeGuards())
+	// Run pre-change guards and custom rules
+	changeGuards, err := guards.WithRules(ctx, client, gctx, guards.RulesNewChange, guards.NewChangeGuards())
+	if err != nil {
+		return nil, fmt.Errorf("loading guard rules: %w", err)
+	}
+	outcome := t.runner.Run(ctx, gctx, changeGuards)
 	if outcome.Blocked {
 		return mcp.ErrorResult(outcome.FormatBlockMessage()), nil
 	}
@@ -132,6 +192,44 @@ func (t *SpecNew) Execute(ctx context.Context, params json.RawMessage) (*mcp.Too
 		return nil, fmt.Errorf("creating change: %w", err)
 	}
 
+	// Create the branch after the Change so an idempotent replay, which
+	// returns the Change from the first attempt, doesn't create another.
+	if p.Branch && change.BranchID == "" {
+		branch, err := client.CreateBranch(ctx, "change/"+change.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := client.UpdateObject(ctx, change.ID, map[string]any{"branch_id": branch.ID}, nil); err != nil {
+			return nil, fmt.Errorf("recording branch on change: %w", err)
+		}
+		change.BranchID = branch.ID
+	}
+
+	if len(deps) > 0 {
+		for _, dep := range deps {
+			if err := client.AddChangeDependency(ctx, depGraph, change, dep); err != nil {
+				if errors.Is(err, emergent.ErrDependencyCycle) {
+					return mcp.ErrorResult(err.Error()), nil
+				}
+				return nil, err
+			}
+		}
+	}
+	// Checked first so an idempotent replay doesn't link an App twice.
+	for _, app := range apps {
+		exists, err := client.HasRelationshipByEdges(ctx, emergent.RelScopedToApp, change.ID, emergent.NewIDSet(app.ID, app.CanonicalID))
+		if err != nil {
+			return nil, fmt.Errorf("checking app scope: %w", err)
+		}
+		if exists {
+			continue
+		}
+		if _, err := client.CreateRelationship(ctx, emergent.RelScopedToApp, change.ID, app.ID, nil); err != nil {
+			return nil, fmt.Errorf("scoping change to app: %w", err)
+		}
+	}
+	ctx = emergent.WithBranch(ctx, change.BranchID)
+
 	// Create the Proposal linked to the Change
 	proposal, err := client.CreateProposal(ctx, change.ID, &emergent.Proposal{
 		Intent: p.Intent,
@@ -143,13 +241,28 @@ func (t *SpecNew) Execute(ctx context.Context, params json.RawMessage) (*mcp.Too
 		return nil, fmt.Errorf("creating proposal: %w", err)
 	}
 
+	changeResult := map[string]any{
+		"id":           change.ID,
+		"canonical_id": change.CanonicalID,
+		"name":         change.Name,
+		"status":       change.Status,
+	}
+	if change.BranchID != "" {
+		changeResult["branch_id"] = change.BranchID
+	}
+	if len(deps) > 0 {
+		names := make([]string, len(deps))
+		for i, dep := range deps {
+			names[i] = dep.Name
+		}
+		changeResult["depends_on"] = names
+	}
+	if len(appNames) > 0 {
+		changeResult["apps"] = appNames
+	}
+
 	result := map[string]any{
-		"change": map[string]any{
-			"id":           change.ID,
-			"canonical_id": change.CanonicalID,
-			"name":         change.Name,
-			"status":       change.Status,
-		},
+		"change": changeResult,
 		"proposal": map[string]any{
 			"id":           proposal.ID,
 			"canonical_id": proposal.CanonicalID,
@@ -164,9 +277,27 @@ func (t *SpecNew) Execute(ctx context.Context, params json.RawMessage) (*mcp.Too
 	if advisory := outcome.FormatAdvisoryMessage(); advisory != "" {
 		result["advisories"] = advisory
 	}
+	if len(outcome.Disabled) > 0 {
+		result["disabled_guards"] = outcome.Disabled
+	}
 
 	b, _ := json.MarshalIndent(result, "", "  ")
 	return &mcp.ToolsCallResult{
 		Content: []mcp.ContentBlock{mcp.TextContent(string(b))},
 	}, nil
 }
+
+// resolveApp finds an App by name or ID. Returns nil if there is none.
+func resolveApp(ctx context.Context, client *emergent.Client, ref string) (*graph.GraphObject, error) {
+	app, err := client.FindByTypeAndKey(ctx, emergent.TypeApp, ref)
+	if err != nil {
+		return nil, fmt.Errorf("looking up app %q: %w", ref, err)
+	}
+	if app != nil {
+		return app, nil
+	}
+	if obj, err := client.GetObject(ctx, ref); err == nil && obj.Type == emergent.TypeApp {
+		return obj, nil
+	}
+	return nil, nil
+}
@@ -30,7 +30,7 @@ func NewSpecStatus(factory *emergent.ClientFactory) *SpecStatus {
 func (t *SpecStatus) Name() string { return "spec_status" }
 
 func (t *SpecStatus) Description() string {
-	return "Get the workflow status of a change: current stage, readiness summary per artifact type, prioritized next steps to advance, and whether the change is ready to archive."
+	return "Get the workflow status of a change: current stage and per-stage progress (from the project's workflow definition), readiness summary and unresolved comment threads per artifact type, prioritized next steps to advance, and whether the change is ready to archive."
 }
 
 func (t *SpecStatus) InputSchema() json.RawMessage {
@@ -48,10 +48,11 @@ func (t *SpecStatus) InputSchema() json.RawMessage {
 
 // artifactSummary describes the readiness state of one artifact category.
 type artifactSummary struct {
-	Exists bool   `json:"exists"`
-	Ready  bool   `json:"ready"`
-	Count  int    `json:"count,omitempty"`
-	Detail string `json:"detail,omitempty"`
+	Exists       bool   `json:"exists"`
+	Ready        bool   `json:"ready"`
+	Count        int    `json:"count,omitempty"`
+	Detail       string `json:"detail,omitempty"`
+	OpenComments int    `json:"open_comments,omitempty"` // unresolved comment threads
 }
 
 func (t *SpecStatus) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
@@ -74,6 +75,7 @@ func (t *SpecStatus) Execute(ctx context.Context, params json.RawMessage) (*mcp.
 	if err != nil {
 		return mcp.ErrorResult(fmt.Sprintf("change not found: %v", err)), nil
 	}
+	ctx = emergent.WithBranch(ctx, change.BranchID)
 
 	// Build guard context for state
 	gctx := &guards.GuardContext{ChangeID: p.ChangeID}
@@ -84,128 +86,66 @@ func (t *SpecStatus) Execute(ctx context.Context, params json.RawMessage) (*mcp.
 	// Determine current stage and build per-artifact detail
 	specDetail := t.buildSpecDetail(ctx, client, p.ChangeID, gctx)
 
+	// Count open comment threads per artifact category
+	openComments, totalOpen, commentsTruncated, err := t.countOpenComments(ctx, client, p.ChangeID)
+	if err != nil {
+		return nil, fmt.Errorf("counting open comments: %w", err)
+	}
+
 	// Build artifact summaries
 	artifacts := map[string]artifactSummary{
 		"proposal": {
-			Exists: gctx.HasProposal,
-			Ready:  gctx.ProposalReady,
+			Exists:       gctx.HasProposal,
+			Ready:        gctx.ProposalReady,
+			OpenComments: openComments["proposal"],
 		},
 		"specs": {
-			Exists: gctx.HasSpec,
-			Ready:  gctx.AllSpecsReady,
-			Count:  gctx.SpecCount,
-			Detail: specDetail,
+			Exists:       gctx.HasSpec,
+			Ready:        gctx.AllSpecsReady,
+			Count:        gctx.SpecCount,
+			Detail:       specDetail,
+			OpenComments: openComments["specs"],
 		},
 		"design": {
-			Exists: gctx.HasDesign,
-			Ready:  gctx.DesignReady,
+			Exists:       gctx.HasDesign,
+			Ready:        gctx.DesignReady,
+			OpenComments: openComments["design"],
 		},
 		"tasks": {
-			Exists: gctx.HasTasks,
-			Count:  gctx.TaskCount,
-			Detail: fmt.Sprintf("%d/%d completed", gctx.CompletedTasks, gctx.TaskCount),
+			Exists:       gctx.HasTasks,
+			Count:        gctx.TaskCount,
+			Detail:       fmt.Sprintf("%d/%d completed", gctx.CompletedTasks, gctx.TaskCount),
+			OpenComments: openComments["tasks"],
 		},
 	}
 
-	// Determine stage
-	stage := t.determineStage(gctx)
-
-	// Build next steps
-	nextSteps := t.buildNextSteps(gctx)
+	// Stage and next steps come from the project's workflow definition
+	stage, nextSteps := gctx.Workflow.Progress(gctx)
+	if totalOpen > 0 {
+		nextSteps = append(nextSteps, fmt.Sprintf("Resolve %d open comment thread(s): spec_list_comments, then spec_resolve_comment (open threads soft-block spec_mark_ready)", totalOpen))
+	}
 
-	// Ready to archive?
-	readyToArchive := gctx.HasProposal && gctx.ProposalReady &&
-		gctx.HasSpec && gctx.AllSpecsReady &&
-		gctx.HasDesign && gctx.DesignReady &&
-		gctx.HasTasks && gctx.TaskCount > 0 && gctx.CompletedTasks == gctx.TaskCount
+	// Ready to archive once every stage is complete or skipped
+	readyToArchive := gctx.Workflow.Complete(gctx)
 
 	result := map[string]any{
 		"change_id":        p.ChangeID,
 		"change_name":      change.Name,
 		"change_status":    change.Status,
 		"stage":            stage,
+		"stages":           gctx.Workflow.States(gctx),
 		"artifacts":        artifacts,
 		"next_steps":       nextSteps,
 		"ready_to_archive": readyToArchive,
+		"open_comments":    totalOpen,
 	}
-
-	return mcp.JSONResult(result)
-}
-
-// determineStage returns the current workflow stage name.
-func (t *SpecStatus) determineStage(gctx *guards.GuardContext) string {
-	// Stages progress: propose → specify → design → implement → complete
-	if !gctx.HasProposal {
-		return "propose"
-	}
-	if !gctx.ProposalReady {
-		return "propose" // Proposal exists but not ready
-	}
-	if !gctx.HasSpec {
-		return "specify"
-	}
-	if !gctx.AllSpecsReady {
-		return "specify" // Specs exist but not all ready
-	}
-	if !gctx.HasDesign {
-		return "design"
-	}
-	if !gctx.DesignReady {
-		return "design" // Design exists but not ready
+	if commentsTruncated {
+		// The change is too large to load every comment, so the counts are
+		// lower bounds.
+		result["open_comments_truncated"] = true
 	}
-	if !gctx.HasTasks {
-		return "implement"
-	}
-	if gctx.CompletedTasks < gctx.TaskCount {
-		return "implement"
-	}
-	return "complete"
-}
-
-// buildNextSteps returns prioritized actions the user should take next.
-func (t *SpecStatus) buildNextSteps(gctx *guards.GuardContext) []string {
-	var steps []string
 
-	if !gctx.HasProposal {
-		steps = append(steps, "Add a proposal: spec_artifact with artifact_type='proposal'")
-		return steps // Can't do anything else without a proposal
-	}
-	if !gctx.ProposalReady {
-		steps = append(steps, "Mark proposal as ready: spec_mark_ready with the proposal's entity_id")
-		return steps
-	}
-
-	if !gctx.HasSpec {
-		steps = append(steps, "Add specs: spec_artifact with artifact_type='spec'")
-		return steps
-	}
-	if !gctx.AllSpecsReady {
-		steps = append(steps, "Mark all specs, requirements, and scenarios as ready (bottom-up) using spec_mark_ready")
-		return steps
-	}
-
-	if !gctx.HasDesign {
-		steps = append(steps, "Add a design: spec_artifact with artifact_type='design'")
-		return steps
-	}
-	if !gctx.DesignReady {
-		steps = append(steps, "Mark design as ready: spec_mark_ready with the design's entity_id")
-		return steps
-	}
-
-	if !gctx.HasTasks {
-		steps = append(steps, "Generate tasks: spec_generate_tasks")
-		return steps
-	}
-
-	incomplete := gctx.TaskCount - gctx.CompletedTasks
-	if incomplete > 0 {
-		steps = append(steps, fmt.Sprintf("Complete %d remaining task(s): use spec_complete_task", incomplete))
-		return steps
-	}
-
-	steps = append(steps, "All tasks complete. Archive the change: spec_archive")
-	return steps
+	return mcp.JSONResult(result)
 }
 
 // buildSpecDetail returns a human-readable summary of spec readiness,
@@ -313,3 +253,76 @@ func (t *SpecStatus) buildSpecDetail(ctx context.Context, client *emergent.Clien
 	}
 	return result
 }
+
+// commentCategory maps the type of a commented entity to its artifact
+// category in the status summary. The change itself has no category.
+var commentCategory = map[string]string{
+	emergent.TypeProposal:    "proposal",
+	emergent.TypeSpec:        "specs",
+	emergent.TypeRequirement: "specs",
+	emergent.TypeScenario:    "specs",
+	emergent.TypeDesign:      "design",
+	emergent.TypeTask:        "tasks",
+}
+
+// countOpenComments counts unresolved comment threads on the change and its
+// artifacts, per artifact category and in total. truncated reports that the
+// expansion hit its cap, so the counts may be too low.
+func (t *SpecStatus) countOpenComments(ctx context.Context, client *emergent.Client, changeID string) (counts map[string]int, total int, truncated bool, err error) {
+	resp, err := client.ExpandGraph(ctx, &graph.GraphExpandRequest{
+		RootIDs:   []string{changeID},
+		Direction: "both",
+		MaxDepth:  5,
+		MaxNodes:  1000,
+		MaxEdges:  2000,
+		RelationshipTypes: []string{
+			emergent.RelHasProposal,
+			emergent.RelHasSpec,
+			emergent.RelHasRequirement,
+			emergent.RelHasScenario,
+			emergent.RelHasDesign,
+			emergent.RelHasTask,
+			emergent.RelCommentsOn,
+		},
+	})
+	if err != nil {
+		return nil, 0, false, err
+	}
+
+	nodeIdx := emergent.NewNodeIndex(resp.Nodes)
+	emergent.CanonicalizeEdgeIDs(resp.Edges, nodeIdx)
+
+	byTarget := make(map[string][]*emergent.Comment)
+	seen := make(map[string]bool)
+	for _, edge := range resp.Edges {
+		if edge.Type != emergent.RelCommentsOn {
+			continue
+		}
+		node, ok := nodeIdx[edge.SrcID]
+		if !ok || node.Type != emergent.TypeComment || seen[edge.SrcID+"|"+edge.DstID] {
+			continue
+		}
+		seen[edge.SrcID+"|"+edge.DstID] = true
+		canonical := node.CanonicalID
+		if canonical == "" {
+			canonical = node.ID
+		}
+		replyTo, _ := node.Properties["reply_to"].(string)
+		resolved, _ := node.Properties["resolved"].(bool)
+		byTarget[edge.DstID] = append(byTarget[edge.DstID], &emergent.Comment{
+			ID: node.ID, CanonicalID: canonical, ReplyTo: replyTo, Resolved: resolved,
+		})
+	}
+
+	counts = make(map[string]int)
+	for targetID, comments := range byTarget {
+		open := emergent.OpenThreads(comments)
+		total += open
+		if node, ok := nodeIdx[targetID]; ok {
+			if category := commentCategory[node.Type]; category != "" {
+				counts[category] += open
+			}
+		}
+	}
+	return counts, total, resp.Truncated, nil
+}
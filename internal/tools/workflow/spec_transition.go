@@ -0,0 +1,224 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/mcp"
+	"github.com/emergent-company/specmcp/internal/validation"
+)
+
+// specTransitionParams defines the input for spec_transition.
+type specTransitionParams struct {
+	EntityID        string `json:"entity_id"`
+	ToStatus        string `json:"to_status"`
+	Force           bool   `json:"force,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+	ExpectedVersion int    `json:"expected_version,omitempty"`
+}
+
+// SpecTransition moves a Change, Task, Improvement, or workflow artifact to
+// another status through the transition registry (internal/validation), and
+// records the move in the entity's transition history. Transitions that do
+// more than change the status are handed to the tool that owns them, e.g.
+// archiving a Change goes through spec_archive.
+type SpecTransition struct {
+	factory     *emergent.ClientFactory
+	transitions *validation.Registry
+	owners      map[string]mcp.Tool
+}
+
+// NewSpecTransition creates a SpecTransition tool. owners are the tools that
+// own transitions with side effects (spec_mark_ready, spec_mark_draft,
+// spec_archive, spec_unarchive, spec_complete_task); spec_transition passes
+// those transitions on to them.
+func NewSpecTransition(factory *emergent.ClientFactory, owners ...mcp.Tool) *SpecTransition {
+	t := &SpecTransition{
+		factory:     factory,
+		transitions: validation.NewRegistry(),
+		owners:      make(map[string]mcp.Tool, len(owners)),
+	}
+	for _, o := range owners {
+		t.owners[o.Name()] = o
+	}
+	return t
+}
+
+func (t *SpecTransition) Name() string { return "spec_transition" }
+
+func (t *SpecTransition) Description() string {
+	return "Move a Change, Task, Improvement, or workflow artifact (Proposal, Spec, Requirement, Scenario, Design) to another status through its state machine. Invalid transitions are rejected (e.g. a completed Task is terminal); soft guards such as open subtasks can be overridden with force=true. Every status change is appended to the entity's transitions history with the reason. Transitions with side effects are carried out by the tool that owns them: ready → spec_mark_ready, draft → spec_mark_draft, archiving → spec_archive, unarchiving → spec_unarchive, completing a Task → spec_complete_task. Starting a Task needs an Agent, so use spec_assign_task."
+}
+
+func (t *SpecTransition) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entity_id": {
+      "type": "string",
+      "description": "ID of the Change, Task, Improvement, or workflow artifact"
+    },
+    "to_status": {
+      "type": "string",
+      "description": "Target status, e.g. blocked, pending, planned, deferred, ready, archived"
+    },
+    "force": {
+      "type": "boolean",
+      "description": "Override soft guards such as incomplete subtasks or children that are not ready (default: false)"
+    },
+    "reason": {
+      "type": "string",
+      "description": "Why the status is changing; recorded in the transition history (required to unarchive a Change)"
+    },
+    "expected_version": {
+      "type": "integer",
+      "description": "Only transition if the entity is still at this version; a conflict is reported instead of retried"
+    }
+  },
+  "required": ["entity_id", "to_status"]
+}`))
+}
+
+// transitionOwner returns the tool that owns moving an entity of entityType
+// from one status to another, or "" if spec_transition applies it itself.
+func transitionOwner(entityType, from, to string) string {
+	switch {
+	case emergent.IsWorkflowArtifactType(entityType) && to == emergent.StatusReady:
+		return "spec_mark_ready"
+	case emergent.IsWorkflowArtifactType(entityType) && to == emergent.StatusDraft:
+		return "spec_mark_draft"
+	case entityType == emergent.TypeChange && to == emergent.StatusArchived:
+		return "spec_archive"
+	case entityType == emergent.TypeChange && from == emergent.StatusArchived:
+		return "spec_unarchive"
+	case entityType == emergent.TypeTask && to == emergent.StatusInProgress:
+		return "spec_assign_task"
+	case entityType == emergent.TypeTask && to == emergent.StatusCompleted:
+		return "spec_complete_task"
+	}
+	return ""
+}
+
+// ownerIDParams names the ID parameter of owning tools that don't take
+// entity_id.
+var ownerIDParams = map[string]string{
+	"spec_archive":       "change_id",
+	"spec_unarchive":     "change_id",
+	"spec_complete_task": "task_id",
+}
+
+func (t *SpecTransition) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p specTransitionParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if p.EntityID == "" || p.ToStatus == "" {
+		return mcp.ErrorResult("entity_id and to_status are required"), nil
+	}
+
+	obj, err := client.GetObject(ctx, p.EntityID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("entity not found: %v", err)), nil
+	}
+	if !t.transitions.Supports(obj.Type) {
+		return mcp.ErrorResult(fmt.Sprintf(
+			"entity type %q has no status transitions. Changes, Tasks, Improvements, and workflow artifacts (Proposal, Spec, Requirement, Scenario, Design) do.",
+			obj.Type,
+		)), nil
+	}
+	branchCtx := emergent.WithBranch(ctx, emergent.ObjectBranch(obj))
+
+	// Check the transition table first; the guards run where the transition
+	// is applied, in Apply or in the owning tool.
+	from := validation.Status(obj)
+	err = t.transitions.Validate(obj.Type, from, p.ToStatus, &validation.TransitionContext{
+		Client: client,
+		Ctx:    branchCtx,
+		Force:  true,
+	}, obj.ID)
+	if errors.Is(err, validation.ErrAlreadyInState) {
+		return mcp.JSONResult(map[string]any{
+			"entity_id": obj.ID,
+			"type":      obj.Type,
+			"status":    p.ToStatus,
+			"message":   fmt.Sprintf("Already %s", p.ToStatus),
+		})
+	}
+	if err != nil {
+		return mcp.ErrorResult(err.Error()), nil
+	}
+
+	if owner := transitionOwner(obj.Type, from, p.ToStatus); owner != "" {
+		tool, ok := t.owners[owner]
+		if !ok || owner == "spec_assign_task" {
+			return mcp.ErrorResult(fmt.Sprintf("moving a %s from %s to %s is done by %s; use it instead", obj.Type, from, p.ToStatus, owner)), nil
+		}
+		idParam := ownerIDParams[owner]
+		if idParam == "" {
+			idParam = "entity_id"
+		}
+		args, err := json.Marshal(map[string]any{
+			idParam:            obj.ID,
+			"force":            p.Force,
+			"reason":           p.Reason,
+			"expected_version": p.ExpectedVersion,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("encoding %s parameters: %w", owner, err)
+		}
+		return tool.Execute(ctx, args)
+	}
+
+	// Improvements keep timestamps for their planning milestones.
+	props := map[string]any{}
+	if obj.Type == emergent.TypeImprovement {
+		now := time.Now().Format(time.RFC3339)
+		switch p.ToStatus {
+		case "planned":
+			props["planned_at"] = now
+		case emergent.StatusCompleted:
+			props["completed_at"] = now
+		}
+	}
+
+	updated, err := t.transitions.Apply(branchCtx, client, obj, validation.Transition{
+		To:              p.ToStatus,
+		Force:           p.Force,
+		Reason:          p.Reason,
+		Via:             t.Name(),
+		Properties:      props,
+		ExpectedVersion: p.ExpectedVersion,
+	})
+	if errors.Is(err, emergent.ErrVersionConflict) || errors.Is(err, validation.ErrRejected) {
+		return mcp.ErrorResult(err.Error()), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("updating status to %s: %w", p.ToStatus, err)
+	}
+
+	result := map[string]any{
+		"entity_id":   updated.ID,
+		"type":        obj.Type,
+		"from_status": from,
+		"status":      p.ToStatus,
+		"version":     updated.Version,
+		"transitions": validation.History(updated),
+		"message":     fmt.Sprintf("Moved %s from %s to %s", obj.Type, from, p.ToStatus),
+	}
+	if ref := refOf(obj); ref.Name != "" {
+		result["name"] = ref.Name
+	} else if title := getString(obj.Properties, "title"); title != "" {
+		result["name"] = title
+	}
+	return mcp.JSONResult(result)
+}
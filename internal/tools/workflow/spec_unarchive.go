@@ -0,0 +1,176 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/guards"
+	"github.com/emergent-company/specmcp/internal/mcp"
+	"github.com/emergent-company/specmcp/internal/validation"
+)
+
+// specUnarchiveParams defines the input for spec_unarchive.
+type specUnarchiveParams struct {
+	ChangeID        string `json:"change_id"`
+	Reason          string `json:"reason"`
+	ExpectedVersion int    `json:"expected_version,omitempty"`
+}
+
+// SpecUnarchive reopens an archived change.
+type SpecUnarchive struct {
+	factory     *emergent.ClientFactory
+	runner      *guards.Runner
+	transitions *validation.Registry
+}
+
+// NewSpecUnarchive creates a SpecUnarchive tool.
+func NewSpecUnarchive(factory *emergent.ClientFactory) *SpecUnarchive {
+	return &SpecUnarchive{
+		factory:     factory,
+		runner:      guards.NewRunner(),
+		transitions: validation.NewRegistry(),
+	}
+}
+
+func (t *SpecUnarchive) Name() string { return "spec_unarchive" }
+
+func (t *SpecUnarchive) Description() string {
+	return "Reopen an archived change, restoring the status it had before spec_archive. Requires a reason, which is recorded on the Change. Re-runs the archive guards against the current state and reports which of them would now block archiving again, so you know what reopening means. The change's merges into living specs are not reverted; they are listed as living_spec_contributions, and archiving again merges idempotently."
+}
+
+func (t *SpecUnarchive) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "change_id": {
+      "type": "string",
+      "description": "ID of the archived change to reopen"
+    },
+    "reason": {
+      "type": "string",
+      "description": "Why the change is being reopened (e.g. archived by mistake, follow-up work found)"
+    },
+    "expected_version": {
+      "type": "integer",
+      "description": "Only unarchive if the change is still at this version; a conflict is reported instead of retried"
+    }
+  },
+  "required": ["change_id", "reason"]
+}`))
+}
+
+func (t *SpecUnarchive) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p specUnarchiveParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if p.ChangeID == "" {
+		return mcp.ErrorResult("change_id is required"), nil
+	}
+	if strings.TrimSpace(p.Reason) == "" {
+		return mcp.ErrorResult("reason is required"), nil
+	}
+
+	obj, err := client.GetObject(ctx, p.ChangeID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("change not found: %v", err)), nil
+	}
+	if obj.Type != emergent.TypeChange {
+		return mcp.ErrorResult(fmt.Sprintf("entity %s is a %s, not a Change", p.ChangeID, obj.Type)), nil
+	}
+	change, err := emergent.ChangeFromObject(obj)
+	if err != nil {
+		return nil, fmt.Errorf("reading change: %w", err)
+	}
+
+	// Changes archived before the audit trail existed came from active.
+	restore := change.ArchivedFrom
+	if restore == "" {
+		restore = emergent.StatusActive
+	}
+	if err := t.transitions.Validate(emergent.TypeChange, change.Status, restore, &validation.TransitionContext{
+		Client: client,
+		Ctx:    ctx,
+	}, change.ID); err != nil {
+		if change.Status != emergent.StatusArchived {
+			return mcp.ErrorResult(fmt.Sprintf("change is not archived (status: %s)", change.Status)), nil
+		}
+		return mcp.ErrorResult(err.Error()), nil
+	}
+
+	_, err = t.transitions.Apply(ctx, client, obj, validation.Transition{
+		To:     restore,
+		Reason: p.Reason,
+		Via:    t.Name(),
+		Properties: map[string]any{
+			"unarchived_at":    time.Now().Format(time.RFC3339),
+			"unarchive_reason": p.Reason,
+			"merge_pending":    false,
+		},
+		ExpectedVersion: p.ExpectedVersion,
+	})
+	if errors.Is(err, emergent.ErrVersionConflict) || errors.Is(err, validation.ErrRejected) {
+		return mcp.ErrorResult(err.Error()), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unarchiving change: %w", err)
+	}
+
+	// Archive guards in reverse: what would stop the change from being
+	// archived again right now. Anything listed was either forced past at
+	// archive time or has changed since.
+	gctx := &guards.GuardContext{ChangeID: change.ID}
+	if err := guards.PopulateChangeState(ctx, client, gctx); err != nil {
+		return nil, fmt.Errorf("populating change state for guards: %w", err)
+	}
+	archiveGuards, err := guards.WithRules(ctx, client, gctx, guards.RulesArchive, guards.ArchiveGuards())
+	if err != nil {
+		return nil, fmt.Errorf("loading guard rules: %w", err)
+	}
+	outcome := t.runner.Run(ctx, gctx, archiveGuards)
+	contributions, err := livingSpecContributions(ctx, client, change.ID)
+	if err != nil {
+		return nil, err
+	}
+	var open []guards.Result
+	for _, r := range outcome.Results {
+		if !r.Passed {
+			open = append(open, r)
+		}
+	}
+
+	result := map[string]any{
+		"change_id":        change.ID,
+		"canonical_id":     change.CanonicalID,
+		"name":             change.Name,
+		"status":           restore,
+		"reason":           p.Reason,
+		"ready_to_archive": !outcome.Blocked,
+	}
+	if change.ArchivedAt != "" {
+		result["archived_at"] = change.ArchivedAt
+	}
+	// Living specs keep what the change merged into them.
+	if len(contributions) > 0 {
+		result["living_spec_contributions"] = contributions
+	}
+	if len(open) > 0 {
+		result["archive_issues"] = open
+		result["message"] = fmt.Sprintf("Reopened change %q as %s; %d archive guard(s) no longer pass, resolve them before archiving again", change.Name, restore, len(open))
+	} else {
+		result["message"] = fmt.Sprintf("Reopened change %q as %s; all archive guards still pass, so it can be archived again as is", change.Name, restore)
+	}
+
+	return mcp.JSONResult(result)
+}
@@ -0,0 +1,207 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+	"github.com/emergent-company/specmcp/internal/mcp"
+	"github.com/emergent-company/specmcp/internal/validation"
+)
+
+// specUpdateArtifactParams defines the input for spec_update_artifact.
+type specUpdateArtifactParams struct {
+	EntityID        string         `json:"entity_id"`
+	Properties      map[string]any `json:"properties"`
+	ExpectedVersion int            `json:"expected_version,omitempty"`
+}
+
+// SpecUpdateArtifact patches an existing workflow artifact. Editing a ready
+// artifact returns it to draft, and ready parents are reset as well since
+// their readiness covered the old content.
+type SpecUpdateArtifact struct {
+	factory     *emergent.ClientFactory
+	transitions *validation.Registry
+}
+
+// NewSpecUpdateArtifact creates a SpecUpdateArtifact tool.
+func NewSpecUpdateArtifact(factory *emergent.ClientFactory) *SpecUpdateArtifact {
+	return &SpecUpdateArtifact{
+		factory:     factory,
+		transitions: validation.NewRegistry(),
+	}
+}
+
+func (t *SpecUpdateArtifact) Name() string { return "spec_update_artifact" }
+
+func (t *SpecUpdateArtifact) Description() string {
+	return "Edit an existing workflow artifact (Proposal, Spec, Requirement, Scenario, Design) in place instead of creating a duplicate. Only the given properties change. If the artifact was ready it returns to draft, and ready parents (Scenario → Requirement → Spec) are reset to draft too. The result lists downstream stages (design, tasks) built on the artifact that may now be stale."
+}
+
+func (t *SpecUpdateArtifact) InputSchema() json.RawMessage {
+	return mcp.IdempotentSchema(json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "entity_id": {
+      "type": "string",
+      "description": "ID of the workflow artifact to edit"
+    },
+    "properties": {
+      "type": "object",
+      "description": "Properties to set, e.g. {\"then\": \"...\"} for a Scenario or {\"description\": \"...\"} for a Requirement. Omitted properties are unchanged. status cannot be set here; use spec_mark_ready or spec_mark_draft."
+    },
+    "expected_version": {
+      "type": "integer",
+      "description": "Only update if the artifact is still at this version; a conflict is reported instead of retried"
+    }
+  },
+  "required": ["entity_id", "properties"]
+}`))
+}
+
+func (t *SpecUpdateArtifact) Execute(ctx context.Context, params json.RawMessage) (*mcp.ToolsCallResult, error) {
+	var p specUpdateArtifactParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("invalid parameters: %v", err)), nil
+	}
+
+	client, err := t.factory.ClientFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating client: %w", err)
+	}
+
+	if p.EntityID == "" {
+		return mcp.ErrorResult("entity_id is required"), nil
+	}
+	if len(p.Properties) == 0 {
+		return mcp.ErrorResult("properties must contain at least one property to change"), nil
+	}
+	if _, ok := p.Properties["status"]; ok {
+		return mcp.ErrorResult("status cannot be set with spec_update_artifact; use spec_mark_ready or spec_mark_draft to change readiness"), nil
+	}
+	if _, ok := p.Properties["transitions"]; ok {
+		return mcp.ErrorResult("transitions is the status history and cannot be edited"), nil
+	}
+
+	obj, err := client.GetObject(ctx, p.EntityID)
+	if err != nil {
+		return mcp.ErrorResult(fmt.Sprintf("entity not found: %v", err)), nil
+	}
+	ctx = emergent.WithBranch(ctx, emergent.ObjectBranch(obj))
+	if !emergent.IsWorkflowArtifactType(obj.Type) {
+		return mcp.ErrorResult(fmt.Sprintf(
+			"entity type %q is not a workflow artifact. spec_update_artifact edits Proposal, Spec, Requirement, Scenario, and Design; use spec_artifact for shared entities.",
+			obj.Type,
+		)), nil
+	}
+
+	ancestors, change, err := artifactLineage(ctx, client, obj)
+	if err != nil {
+		return nil, fmt.Errorf("resolving parents: %w", err)
+	}
+	if change != nil {
+		if s, _ := change.Properties["status"].(string); s == emergent.StatusArchived {
+			return mcp.ErrorResult("cannot edit artifacts of an archived change"), nil
+		}
+	}
+
+	// Apply the patch, guarded by the version we read. A ready artifact goes
+	// back to draft in the same update; on a conflict the fresh read decides
+	// whether that is still needed.
+	current := obj
+	wasReady := statusOf(obj) == emergent.StatusReady
+	expected, reload := obj.Version, reloadObject(ctx, client, obj.ID, func(fresh *graph.GraphObject) error {
+		current = fresh
+		wasReady = statusOf(fresh) == emergent.StatusReady
+		return nil
+	})
+	if p.ExpectedVersion > 0 {
+		expected, reload = p.ExpectedVersion, nil
+	}
+	var updated *graph.GraphObject
+	err = emergent.RetryOnConflict(expected, func(version int) error {
+		props := maps.Clone(p.Properties)
+		if wasReady {
+			if err := t.transitions.Validate(obj.Type, emergent.StatusReady, emergent.StatusDraft, &validation.TransitionContext{
+				Client: client,
+				Ctx:    ctx,
+			}, obj.ID); err != nil {
+				return err
+			}
+			props["status"] = emergent.StatusDraft
+			validation.AppendHistory(props, current, emergent.StatusReady, validation.Transition{
+				To:     emergent.StatusDraft,
+				Reason: "edited",
+				Via:    t.Name(),
+			})
+		}
+		var updateErr error
+		updated, updateErr = client.UpdateObjectIfVersion(ctx, obj.ID, version, props, nil)
+		return updateErr
+	}, reload)
+	if errors.Is(err, emergent.ErrVersionConflict) || errors.Is(err, validation.ErrInvalidTransition) {
+		return mcp.ErrorResult(err.Error()), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("updating %s: %w", obj.Type, err)
+	}
+
+	result := map[string]any{
+		"entity_id":          updated.ID,
+		"type":               obj.Type,
+		"version":            updated.Version,
+		"updated_properties": slices.Sorted(maps.Keys(p.Properties)),
+		"status":             statusOf(updated),
+	}
+	if updated.ChangeSummary == nil {
+		result["message"] = fmt.Sprintf("No changes: %s already has these values", obj.Type)
+		return mcp.JSONResult(result)
+	}
+
+	// The parents' readiness covered the old content, so reset any that are
+	// ready: Scenario → Requirement → Spec.
+	var reverted []artifactRef
+	for _, a := range ancestors {
+		ok, err := revertToDraft(ctx, client, a.ID, fmt.Sprintf("%s %s edited", obj.Type, obj.ID))
+		if err != nil {
+			return nil, fmt.Errorf("resetting parent readiness: %w", err)
+		}
+		if ok {
+			reverted = append(reverted, refOf(a))
+		}
+	}
+
+	msg := fmt.Sprintf("Updated %s", obj.Type)
+	if wasReady {
+		msg += " and returned it to draft; mark it ready again with spec_mark_ready once reviewed"
+		result["was_ready"] = true
+	}
+	if len(reverted) > 0 {
+		result["reverted_parents"] = reverted
+	}
+	if change != nil {
+		stale, err := downstreamStages(ctx, client, change.ID, changeArtifact(obj, ancestors))
+		if err != nil {
+			return nil, fmt.Errorf("checking downstream stages: %w", err)
+		}
+		if len(stale) > 0 {
+			result["stale_stages"] = stale
+		}
+	}
+	result["message"] = msg
+
+	return mcp.JSONResult(result)
+}
+
+// statusOf returns an artifact's status property, treating missing as draft.
+func statusOf(obj *graph.GraphObject) string {
+	if s, ok := obj.Properties["status"].(string); ok && s != "" {
+		return s
+	}
+	return emergent.StatusDraft
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/config"
 	"github.com/emergent-company/specmcp/internal/emergent"
 	"github.com/emergent-company/specmcp/internal/guards"
 	"github.com/emergent-company/specmcp/internal/mcp"
@@ -19,17 +20,21 @@ type specVerifyParams struct {
 // SpecVerify verifies a change across 3 dimensions: completeness, correctness, coherence.
 type SpecVerify struct {
 	factory *emergent.ClientFactory
+	lint    guards.LintOptions
+	linting bool
 }
 
-// NewSpecVerify creates a SpecVerify tool.
-func NewSpecVerify(factory *emergent.ClientFactory) *SpecVerify {
-	return &SpecVerify{factory: factory}
+// NewSpecVerify creates a SpecVerify tool. An optional LintConfig configures
+// the requirement wording checks in the correctness dimension.
+func NewSpecVerify(factory *emergent.ClientFactory, cfg ...config.LintConfig) *SpecVerify {
+	lint, linting := lintOptions(cfg)
+	return &SpecVerify{factory: factory, lint: lint, linting: linting}
 }
 
 func (t *SpecVerify) Name() string { return "spec_verify" }
 
 func (t *SpecVerify) Description() string {
-	return "Verify a change across 3 dimensions: completeness (all required artifacts and tasks exist), correctness (requirements map to implementations), and coherence (design patterns are consistent). Returns a verification report with issues categorized by severity."
+	return "Verify a change across 3 dimensions: completeness (all required artifacts and tasks exist), correctness (requirements map to implementations and are worded testably: RFC 2119 keywords consistent with strength, no vague terms or non-testable phrasing, with suggested rewrites), and coherence (design patterns are consistent). Returns a verification report with issues categorized by severity."
 }
 
 func (t *SpecVerify) InputSchema() json.RawMessage {
@@ -73,6 +78,7 @@ func (t *SpecVerify) Execute(ctx context.Context, params json.RawMessage) (*mcp.
 	if err != nil {
 		return mcp.ErrorResult(fmt.Sprintf("change not found: %v", err)), nil
 	}
+	ctx = emergent.WithBranch(ctx, change.BranchID)
 
 	// Build guard context for state
 	gctx := &guards.GuardContext{ChangeID: change.ID}
@@ -135,12 +141,13 @@ func (t *SpecVerify) Execute(ctx context.Context, params json.RawMessage) (*mcp.
 	}, nil
 }
 
-// checkCompleteness verifies all required artifacts exist.
+// checkCompleteness verifies all required artifacts exist. An artifact type
+// is required when it belongs to a non-optional stage of the workflow.
 func (t *SpecVerify) checkCompleteness(_ context.Context, gctx *guards.GuardContext) []verifyIssue {
 	var issues []verifyIssue
 
 	// Proposal is required
-	if !gctx.HasProposal {
+	if !gctx.HasProposal && stageRequired(gctx, "proposal") {
 		issues = append(issues, verifyIssue{
 			Dimension: "completeness",
 			Severity:  "CRITICAL",
@@ -150,7 +157,7 @@ func (t *SpecVerify) checkCompleteness(_ context.Context, gctx *guards.GuardCont
 	}
 
 	// Specs should exist
-	if !gctx.HasSpec {
+	if !gctx.HasSpec && stageRequired(gctx, "spec") {
 		issues = append(issues, verifyIssue{
 			Dimension: "completeness",
 			Severity:  "CRITICAL",
@@ -160,7 +167,7 @@ func (t *SpecVerify) checkCompleteness(_ context.Context, gctx *guards.GuardCont
 	}
 
 	// Design should exist
-	if !gctx.HasDesign {
+	if !gctx.HasDesign && stageRequired(gctx, "design") {
 		issues = append(issues, verifyIssue{
 			Dimension: "completeness",
 			Severity:  "CRITICAL",
@@ -170,7 +177,7 @@ func (t *SpecVerify) checkCompleteness(_ context.Context, gctx *guards.GuardCont
 	}
 
 	// Tasks should exist
-	if !gctx.HasTasks {
+	if !gctx.HasTasks && stageRequired(gctx, "task") {
 		issues = append(issues, verifyIssue{
 			Dimension: "completeness",
 			Severity:  "CRITICAL",
@@ -205,6 +212,16 @@ func (t *SpecVerify) checkCompleteness(_ context.Context, gctx *guards.GuardCont
 	return issues
 }
 
+// stageRequired reports whether artifactType belongs to a non-optional
+// workflow stage.
+func stageRequired(gctx *guards.GuardContext, artifactType string) bool {
+	if gctx.Workflow == nil {
+		return true
+	}
+	st := gctx.Workflow.StageFor(artifactType, nil)
+	return st != nil && !st.Optional
+}
+
 // checkCorrectness verifies requirements map to implementations.
 func (t *SpecVerify) checkCorrectness(ctx context.Context, client *emergent.Client, changeID string, gctx *guards.GuardContext) []verifyIssue {
 	var issues []verifyIssue
@@ -295,6 +312,9 @@ func (t *SpecVerify) checkCorrectness(ctx context.Context, client *emergent.Clie
 			if len(scenIDs) > 0 {
 				requirementsWithScenarios++
 			}
+			if node, ok := nodeMap[reqID]; ok && t.linting {
+				issues = append(issues, t.lintRequirement(node)...)
+			}
 		}
 	}
 
@@ -334,6 +354,32 @@ func (t *SpecVerify) checkCorrectness(ctx context.Context, client *emergent.Clie
 	return issues
 }
 
+// lintRequirement reports a requirement's wording issues, one per lint
+// finding, with the suggested rewrite or fix as the remedy.
+func (t *SpecVerify) lintRequirement(node *graph.ExpandNode) []verifyIssue {
+	name := getString(node.Properties, "name")
+	severity := "WARNING"
+	switch t.lint.Severity {
+	case guards.Suggestion:
+		severity = "SUGGESTION"
+	case guards.HardBlock:
+		severity = "CRITICAL"
+	}
+	var issues []verifyIssue
+	for _, li := range guards.LintRequirement(getString(node.Properties, "description"), getString(node.Properties, "strength"), t.lint) {
+		issue := verifyIssue{
+			Dimension: "correctness",
+			Severity:  severity,
+			Message:   fmt.Sprintf("Requirement %q: %s", name, li.Message),
+		}
+		if li.Suggestion != "" {
+			issue.Remedy = li.Suggestion
+		}
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
 // checkCoherence verifies design adherence and pattern consistency.
 func (t *SpecVerify) checkCoherence(ctx context.Context, client *emergent.Client, changeID string, gctx *guards.GuardContext) []verifyIssue {
 	var issues []verifyIssue
@@ -0,0 +1,166 @@
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/emergent-company/emergent/apps/server-go/pkg/sdk/graph"
+	"github.com/emergent-company/specmcp/internal/emergent"
+)
+
+// ErrRejected is matched (via errors.Is) by every *RejectedError.
+var ErrRejected = errors.New("transition rejected")
+
+// RejectedError is returned by Apply when the registry refuses a transition.
+// It wraps the validator's error.
+type RejectedError struct {
+	Err error
+}
+
+func (e *RejectedError) Error() string { return e.Err.Error() }
+
+func (e *RejectedError) Unwrap() error { return e.Err }
+
+// Is makes errors.Is(err, ErrRejected) match.
+func (e *RejectedError) Is(target error) bool {
+	return target == ErrRejected
+}
+
+// Transition is a requested status change of one entity.
+type Transition struct {
+	To     string
+	Force  bool   // bypass the validator's soft guards
+	Reason string // recorded in the history
+	Via    string // tool making the change, recorded in the history
+
+	// Guarded tells Apply that the caller already ran the soft guards of
+	// this transition through guards.Runner, which honours force and the
+	// guard policy, so only the transition table is checked.
+	Guarded bool
+
+	// Properties are written in the same update as the status.
+	Properties map[string]any
+
+	// ExpectedVersion pins the version to update; a conflict is then
+	// returned instead of retried.
+	ExpectedVersion int
+}
+
+// initialStatus is the status of an entity whose status property is unset.
+var initialStatus = map[string]string{
+	emergent.TypeChange:      emergent.StatusActive,
+	emergent.TypeTask:        emergent.StatusPending,
+	emergent.TypeImprovement: emergent.StatusProposed,
+	emergent.TypeProposal:    emergent.StatusDraft,
+	emergent.TypeSpec:        emergent.StatusDraft,
+	emergent.TypeRequirement: emergent.StatusDraft,
+	emergent.TypeScenario:    emergent.StatusDraft,
+	emergent.TypeDesign:      emergent.StatusDraft,
+}
+
+// Status returns obj's status, or the initial status of its type if unset.
+func Status(obj *graph.GraphObject) string {
+	if s, ok := obj.Properties["status"].(string); ok && s != "" {
+		return s
+	}
+	return InitialStatus(obj.Type)
+}
+
+// InitialStatus returns the status an entity of the given type has before
+// its status property is set, or "" for types without a lifecycle.
+func InitialStatus(typeName string) string {
+	return initialStatus[typeName]
+}
+
+// History returns obj's transition history, oldest first.
+func History(obj *graph.GraphObject) []emergent.StatusTransition {
+	raw, ok := obj.Properties["transitions"]
+	if !ok {
+		return nil
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var out []emergent.StatusTransition
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// AppendHistory sets props["transitions"] to obj's history followed by an
+// entry for the move from the given status to t.To. Apply calls it; tools
+// that change the status as part of a larger update call it directly.
+func AppendHistory(props map[string]any, obj *graph.GraphObject, from string, t Transition) {
+	props["transitions"] = append(History(obj), emergent.StatusTransition{
+		From:   from,
+		To:     t.To,
+		At:     time.Now().UTC().Format(time.RFC3339),
+		Reason: t.Reason,
+		Forced: t.Force,
+		Via:    t.Via,
+	})
+}
+
+// Apply validates t against the registry and writes obj's new status,
+// together with t.Properties and a new entry in its transition history. The
+// update is guarded by obj's version (or t.ExpectedVersion); on a conflict
+// without a pinned version, Apply re-reads the entity and retries as long as
+// it is still in the status the transition was validated from.
+//
+// Apply returns ErrAlreadyInState if obj is already in t.To, a
+// *RejectedError if the registry refuses the transition, and an
+// emergent.ErrVersionConflict if the entity was changed concurrently. When
+// the concurrent writer made the same transition, the error also matches
+// ErrAlreadyInState.
+func (r *Registry) Apply(ctx context.Context, client *emergent.Client, obj *graph.GraphObject, t Transition) (*graph.GraphObject, error) {
+	from := Status(obj)
+	tctx := &TransitionContext{Client: client, Ctx: ctx, Force: t.Force || t.Guarded}
+	if err := r.Validate(obj.Type, from, t.To, tctx, obj.ID); err != nil {
+		if errors.Is(err, ErrAlreadyInState) {
+			return nil, err
+		}
+		return nil, &RejectedError{Err: err}
+	}
+
+	current := obj
+	expected, reload := obj.Version, func() (int, error) {
+		fresh, err := client.GetObject(ctx, obj.ID)
+		if err != nil {
+			return 0, err
+		}
+		switch s := Status(fresh); s {
+		case from:
+			current = fresh
+			return fresh.Version, nil
+		case t.To:
+			return 0, fmt.Errorf("%w: %s %s was moved to %s concurrently (%w)", emergent.ErrVersionConflict, obj.Type, obj.ID, s, ErrAlreadyInState)
+		default:
+			return 0, fmt.Errorf("%w: %s %s was updated concurrently and is now %s", emergent.ErrVersionConflict, obj.Type, obj.ID, s)
+		}
+	}
+	if t.ExpectedVersion > 0 {
+		expected, reload = t.ExpectedVersion, nil
+	}
+	var updated *graph.GraphObject
+	err := emergent.RetryOnConflict(expected, func(version int) error {
+		props := maps.Clone(t.Properties)
+		if props == nil {
+			props = make(map[string]any)
+		}
+		props["status"] = t.To
+		AppendHistory(props, current, from, t)
+		var err error
+		updated, err = client.UpdateObjectIfVersion(ctx, obj.ID, version, props, nil)
+		return err
+	}, reload)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
@@ -14,6 +14,13 @@ var taskTransitions = map[string][]string{
 	emergent.StatusCompleted:  {}, // terminal
 }
 
+// forcedTaskTransitions are allowed only with force: completing a task that
+// was never started, as spec_complete_task allowed before tasks had a state
+// machine.
+var forcedTaskTransitions = map[string][]string{
+	emergent.StatusPending: {emergent.StatusCompleted},
+}
+
 type taskValidator struct{}
 
 // NewTaskValidator creates a validator for Task entities
@@ -23,7 +30,7 @@ func NewTaskValidator() Validator {
 
 func (v *taskValidator) Validate(from, to string, ctx *TransitionContext, taskID string) error {
 	// Check if transition is allowed
-	if !isAllowedTransition(from, to, taskTransitions) {
+	if !isAllowedTransition(from, to, taskTransitions) && !(ctx.Force && isAllowedTransition(from, to, forcedTaskTransitions)) {
 		return transitionError(from, to)
 	}
 
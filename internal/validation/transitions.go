@@ -68,6 +68,12 @@ func (r *Registry) Register(entityType string, validator Validator) {
 	r.validators[entityType] = validator
 }
 
+// Supports reports whether entityType has a state machine in the registry
+func (r *Registry) Supports(entityType string) bool {
+	_, ok := r.validators[entityType]
+	return ok
+}
+
 // Validate checks if a state transition is allowed
 func (r *Registry) Validate(entityType, from, to string, ctx *TransitionContext, entityID string) error {
 	if from == to {
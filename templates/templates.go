@@ -0,0 +1,16 @@
+// Package templates embeds the SpecMCP template pack definitions so the
+// specmcp binary can install and upgrade them without the source tree.
+//
+// Files ending in -v013.json use the object-keyed schema format expected by
+// Emergent v0.13; the others use the array format of v0.14 and later.
+package templates
+
+import "embed"
+
+// DefaultPack is the pack installed by "specmcp pack" when none is named.
+const DefaultPack = "specmcp-pack-v2.json"
+
+// FS holds the embedded template pack files.
+//
+//go:embed *.json
+var FS embed.FS